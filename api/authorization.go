@@ -0,0 +1,192 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+const (
+	// profileHeaderKey selects which profile the user is acting as when
+	// they belong to more than one.
+	profileHeaderKey        = "X-Profile-ID"
+	authorizationProfileKey = "authorization_profile"
+	userProfilesKey         = "user_profiles"
+)
+
+var (
+	errNoProfile        = errors.New("user has no profile")
+	errProfileRequired  = fmt.Errorf("user has several profiles, select one with the %s header", profileHeaderKey)
+	errProfileNotMember = errors.New("user cannot act as the selected profile")
+)
+
+// userProfiles returns every profile the authenticated user may act as,
+// with their member role in it, resolved through the profiles_users junction
+// table. The result is cached on the request context.
+func (server *Server) userProfiles(ctx *gin.Context) ([]db.ListProfilesByUserRow, error) {
+	if cached, ok := ctx.Get(userProfilesKey); ok {
+		return cached.([]db.ListProfilesByUserRow), nil
+	}
+
+	memberships, err := server.store.ListProfilesByUser(ctx, authPayload(ctx).UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Set(userProfilesKey, memberships)
+	return memberships, nil
+}
+
+// actingProfile resolves the profile the authenticated user is acting as,
+// with their member role in it. If the user belongs to a single profile it
+// is used, otherwise the X-Profile-ID header must name one of their
+// profiles.
+func (server *Server) actingProfile(ctx *gin.Context) (db.ListProfilesByUserRow, int, error) {
+	memberships, err := server.userProfiles(ctx)
+	if err != nil {
+		return db.ListProfilesByUserRow{}, http.StatusInternalServerError, err
+	}
+	if len(memberships) == 0 {
+		return db.ListProfilesByUserRow{}, http.StatusForbidden, errNoProfile
+	}
+
+	header := ctx.GetHeader(profileHeaderKey)
+	if header == "" {
+		if len(memberships) > 1 {
+			return db.ListProfilesByUserRow{}, http.StatusBadRequest, errProfileRequired
+		}
+		return memberships[0], http.StatusOK, nil
+	}
+
+	profileID, err := uuid.Parse(header)
+	if err != nil {
+		return db.ListProfilesByUserRow{}, http.StatusBadRequest, fmt.Errorf("invalid %s header: %w", profileHeaderKey, err)
+	}
+	for _, membership := range memberships {
+		if membership.Profiles.ID == profileID {
+			return membership, http.StatusOK, nil
+		}
+	}
+	return db.ListProfilesByUserRow{}, http.StatusForbidden, errProfileNotMember
+}
+
+// actingRoles returns the roles the member may exercise through the acting
+// profile. The admin role only carries over to owners and managers of the
+// profile, never to its staff.
+func actingRoles(membership db.ListProfilesByUserRow) (util.RoleSet, error) {
+	roles, err := util.ParseRoles(membership.Profiles.Roles)
+	if err != nil {
+		return nil, err
+	}
+	if !util.MemberRole(membership.MemberRole).CanManage() {
+		delete(roles, util.RoleAdmin)
+	}
+	return roles, nil
+}
+
+// RequireRole creates a gin middleware that only lets the request through
+// if the acting profile holds one of the given roles. Owners and managers
+// of an admin profile always pass.
+// It must run after authMiddleware. The acting profile is stored in the
+// context under authorizationProfileKey.
+func (server *Server) RequireRole(roles ...util.Role) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		membership, status, err := server.actingProfile(ctx)
+		if err != nil {
+			ctx.AbortWithStatusJSON(status, errorResponse(err))
+			return
+		}
+
+		profileRoles, err := actingRoles(membership)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+
+		if !profileRoles.Has(roles...) {
+			err := errors.New("profile does not have the required role")
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+
+		ctx.Set(authorizationProfileKey, membership.Profiles)
+		ctx.Next()
+	}
+}
+
+// currentProfile returns the acting profile stored by RequireRole.
+func currentProfile(ctx *gin.Context) db.Profiles {
+	return ctx.MustGet(authorizationProfileKey).(db.Profiles)
+}
+
+// isAdmin reports whether the acting profile holds the admin role and the
+// user owns or manages it.
+func (server *Server) isAdmin(ctx *gin.Context) (bool, int, error) {
+	membership, status, err := server.actingProfile(ctx)
+	if err != nil {
+		return false, status, err
+	}
+
+	roles, err := actingRoles(membership)
+	if err != nil {
+		return false, http.StatusOK, nil
+	}
+	_, ok := roles[util.RoleAdmin]
+	return ok, http.StatusOK, nil
+}
+
+// authorizeOwner checks that the authenticated user may act as the owner
+// profile of a resource (venues.owned_by, practitioners.created_by,
+// events.created_by), or is an admin. It writes the error response and
+// returns false when the check fails.
+func (server *Server) authorizeOwner(ctx *gin.Context, owner uuid.NullUUID) bool {
+	ok, status, err := server.isOwner(ctx, owner)
+	if err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return false
+	}
+	if !ok {
+		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("resource does not belong to the authenticated user")))
+		return false
+	}
+	return true
+}
+
+// isOwner reports whether the user is acting as the owner profile of a
+// resource as one of its owners or managers, or is an admin. Staff of the
+// owner profile do not own its resources.
+func (server *Server) isOwner(ctx *gin.Context, owner uuid.NullUUID) (bool, int, error) {
+	membership, status, err := server.actingProfile(ctx)
+	if err != nil {
+		return false, status, err
+	}
+
+	if owner.Valid && membership.Profiles.ID == owner.UUID &&
+		util.MemberRole(membership.MemberRole).CanManage() {
+		return true, http.StatusOK, nil
+	}
+
+	return server.isAdmin(ctx)
+}
+
+// callerOwns is isOwner for public routes, where the caller may not be
+// signed in. Callers without a valid access token or an acting profile own
+// nothing.
+func (server *Server) callerOwns(ctx *gin.Context, owner uuid.NullUUID) (bool, error) {
+	payload, err := verifyAuthorizationHeader(server.tokenMaker, ctx.GetHeader(authorizationHeaderKey))
+	if err != nil {
+		return false, nil
+	}
+
+	ctx.Set(authorizationPayloadKey, payload)
+	ok, status, err := server.isOwner(ctx, owner)
+	if err != nil && status != http.StatusInternalServerError {
+		return false, nil
+	}
+	return ok, err
+}
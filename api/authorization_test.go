@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/token"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// membershipStore serves a fixed set of profile memberships.
+type membershipStore struct {
+	db.Store
+	memberships []db.ListProfilesByUserRow
+}
+
+func (store membershipStore) ListProfilesByUser(ctx context.Context, usersID uuid.UUID) ([]db.ListProfilesByUserRow, error) {
+	return store.memberships, nil
+}
+
+func newMembership(roles util.Role, member util.MemberRole) db.ListProfilesByUserRow {
+	return db.ListProfilesByUserRow{
+		Profiles:   db.Profiles{ID: uuid.New(), Roles: string(roles)},
+		MemberRole: string(member),
+	}
+}
+
+func TestIsOwner(t *testing.T) {
+	owner := newMembership(util.RoleVenueOwner, util.MemberOwner)
+	manager := newMembership(util.RoleVenueOwner, util.MemberManager)
+	staff := newMembership(util.RoleVenueOwner, util.MemberStaff)
+	admin := newMembership(util.RoleAdmin, util.MemberOwner)
+	adminStaff := newMembership(util.RoleAdmin, util.MemberStaff)
+
+	testCases := []struct {
+		name        string
+		memberships []db.ListProfilesByUserRow
+		profile     uuid.UUID
+		resource    uuid.UUID
+		wantOwner   bool
+		wantAdmin   bool
+		wantStatus  int
+	}{
+		{
+			name:        "owner",
+			memberships: []db.ListProfilesByUserRow{owner},
+			resource:    owner.Profiles.ID,
+			wantOwner:   true,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "manager",
+			memberships: []db.ListProfilesByUserRow{manager},
+			resource:    manager.Profiles.ID,
+			wantOwner:   true,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "staff",
+			memberships: []db.ListProfilesByUserRow{staff},
+			resource:    staff.Profiles.ID,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "admin",
+			memberships: []db.ListProfilesByUserRow{admin},
+			resource:    owner.Profiles.ID,
+			wantOwner:   true,
+			wantAdmin:   true,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "staff of an admin profile",
+			memberships: []db.ListProfilesByUserRow{adminStaff},
+			resource:    owner.Profiles.ID,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "acting as the owner profile",
+			memberships: []db.ListProfilesByUserRow{staff, owner},
+			profile:     owner.Profiles.ID,
+			resource:    owner.Profiles.ID,
+			wantOwner:   true,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "acting as another profile",
+			memberships: []db.ListProfilesByUserRow{staff, owner},
+			profile:     staff.Profiles.ID,
+			resource:    owner.Profiles.ID,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "acting as another profile of an admin",
+			memberships: []db.ListProfilesByUserRow{owner, admin},
+			profile:     owner.Profiles.ID,
+			resource:    manager.Profiles.ID,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "no acting profile selected",
+			memberships: []db.ListProfilesByUserRow{staff, owner},
+			resource:    owner.Profiles.ID,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "not a member of the selected profile",
+			memberships: []db.ListProfilesByUserRow{owner},
+			profile:     manager.Profiles.ID,
+			resource:    manager.Profiles.ID,
+			wantStatus:  http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, membershipStore{memberships: tc.memberships})
+
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.profile != uuid.Nil {
+				ctx.Request.Header.Set(profileHeaderKey, tc.profile.String())
+			}
+			payload, err := token.NewPayload(uuid.New(), token.PurposeAccess, time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx.Set(authorizationPayloadKey, payload)
+
+			owner, status, err := server.isOwner(ctx, uuid.NullUUID{UUID: tc.resource, Valid: true})
+			if status != tc.wantStatus {
+				t.Fatalf("isOwner() status = %d, want %d (err %v)", status, tc.wantStatus, err)
+			}
+			if owner != tc.wantOwner {
+				t.Errorf("isOwner() = %v, want %v", owner, tc.wantOwner)
+			}
+
+			admin, _, _ := server.isAdmin(ctx)
+			if admin != tc.wantAdmin {
+				t.Errorf("isAdmin() = %v, want %v", admin, tc.wantAdmin)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tedobanks/tabularasa_backend/availability"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// maxAvailabilityRange bounds the from/to window of an availability query.
+const maxAvailabilityRange = 31 * 24 * time.Hour
+
+// availabilityURI defines the URI parameter for availability lookups.
+type availabilityURI struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// availabilityRequest defines the query parameters for availability lookups.
+type availabilityRequest struct {
+	From time.Time `form:"from" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   time.Time `form:"to" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// availabilityResponse lists the free slots between From and To.
+type availabilityResponse struct {
+	From        time.Time               `json:"from"`
+	To          time.Time               `json:"to"`
+	Granularity string                  `json:"granularity"`
+	Slots       []availability.Interval `json:"slots"`
+}
+
+// bindAvailability parses and validates the URI and query of an availability request.
+func bindAvailability(ctx *gin.Context) (uuid.UUID, availabilityRequest, bool) {
+	var uri availabilityURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return uuid.UUID{}, availabilityRequest{}, false
+	}
+
+	var req availabilityRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return uuid.UUID{}, availabilityRequest{}, false
+	}
+
+	if !req.From.Before(req.To) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("from must be before to")))
+		return uuid.UUID{}, availabilityRequest{}, false
+	}
+	if req.To.Sub(req.From) > maxAvailabilityRange {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("range must not exceed %s", maxAvailabilityRange)))
+		return uuid.UUID{}, availabilityRequest{}, false
+	}
+
+	id, err := uuid.Parse(uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid ID format: %w", err)))
+		return uuid.UUID{}, availabilityRequest{}, false
+	}
+
+	return id, req, true
+}
+
+// getVenueAvailability lists the free slots of a venue.
+// GET /venues/:id/availability?from=&to=
+func (server *Server) getVenueAvailability(ctx *gin.Context) {
+	venueID, req, ok := bindAvailability(ctx)
+	if !ok {
+		return
+	}
+
+	venue, ok := server.loadVenue(ctx, venueID)
+	if !ok {
+		return
+	}
+
+	rsp := availabilityResponse{
+		From:        req.From,
+		To:          req.To,
+		Granularity: server.config.AvailabilityGranularity.String(),
+		Slots:       []availability.Interval{},
+	}
+	if !venue.IsAvailable.Bool {
+		ctx.JSON(http.StatusOK, rsp)
+		return
+	}
+
+	days, err := util.ParseWeekdays(venue.RentalDays.String)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Venues are rented by the day, so look at whole days around the range
+	bookings, err := server.store.ListBookedVenuesBetween(ctx, db.ListBookedVenuesBetweenParams{
+		VenueID:  venueID,
+		FromTime: util.StartOfDay(req.From),
+		ToTime:   util.StartOfDay(req.To).AddDate(0, 0, 1),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	bookedFor := make([]time.Time, 0, len(bookings))
+	for _, booking := range bookings {
+		bookedFor = append(bookedFor, booking.BookedFor)
+	}
+
+	schedule := availability.Schedule{
+		Days: days,
+		Hours: util.OpeningHours{
+			OpensAt:  venue.OpensAt.Time,
+			ClosesAt: venue.ClosesAt.Time,
+			Valid:    venue.OpensAt.Valid && venue.ClosesAt.Valid,
+		},
+	}
+	rsp.Slots = availability.FreeSlots(schedule, availability.WholeDays(bookedFor), req.From, req.To, server.config.AvailabilityGranularity)
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// getPractitionerAvailability lists the free slots of a practitioner. Each
+// slot lasts APPOINTMENT_DURATION and starts AVAILABILITY_GRANULARITY after
+// the previous one, so every slot can be booked.
+// GET /practitioners/:id/availability?from=&to=
+func (server *Server) getPractitionerAvailability(ctx *gin.Context) {
+	serviceID, req, ok := bindAvailability(ctx)
+	if !ok {
+		return
+	}
+
+	practitioner, ok := server.loadPractitioner(ctx, serviceID)
+	if !ok {
+		return
+	}
+
+	rsp := availabilityResponse{
+		From:        req.From,
+		To:          req.To,
+		Granularity: server.config.AvailabilityGranularity.String(),
+		Slots:       []availability.Interval{},
+	}
+	if !practitioner.IsAvailable.Bool {
+		ctx.JSON(http.StatusOK, rsp)
+		return
+	}
+
+	days, err := util.ParseWeekdays(practitioner.WorkingDays.String)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Appointments that started up to one duration before the range can still overlap it
+	duration := server.config.AppointmentDuration
+	bookings, err := server.store.ListBookedPractitionersBetween(ctx, db.ListBookedPractitionersBetweenParams{
+		ServiceID: serviceID,
+		FromTime:  req.From.Add(-duration),
+		ToTime:    req.To,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	bookedFor := make([]time.Time, 0, len(bookings))
+	for _, booking := range bookings {
+		bookedFor = append(bookedFor, booking.BookedFor)
+	}
+
+	schedule := availability.Schedule{
+		Days: days,
+		Hours: util.OpeningHours{
+			OpensAt:  practitioner.OpensAt.Time,
+			ClosesAt: practitioner.ClosesAt.Time,
+			Valid:    practitioner.OpensAt.Valid && practitioner.ClosesAt.Valid,
+		},
+	}
+	// Each slot is a whole appointment, offered every granularity
+	busy := availability.Fixed(bookedFor, duration)
+	rsp.Slots = availability.FreeSlotsEvery(schedule, busy, req.From, req.To, duration, server.config.AvailabilityGranularity)
+
+	ctx.JSON(http.StatusOK, rsp)
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tedobanks/tabularasa_backend/availability"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+)
+
+// practitionerStore serves one practitioner and its appointments.
+type practitionerStore struct {
+	db.Store
+
+	practitioner db.Practitioners
+	bookings     []db.BookedPractitioners
+}
+
+func (store *practitionerStore) GetPractitioner(ctx context.Context, id uuid.UUID) (db.Practitioners, error) {
+	if id != store.practitioner.ID {
+		return db.Practitioners{}, sql.ErrNoRows
+	}
+	return store.practitioner, nil
+}
+
+func (store *practitionerStore) ListBookedPractitionersBetween(ctx context.Context, arg db.ListBookedPractitionersBetweenParams) ([]db.BookedPractitioners, error) {
+	var bookings []db.BookedPractitioners
+	for _, booking := range store.bookings {
+		if booking.ServiceID == arg.ServiceID && !booking.BookedFor.Before(arg.FromTime) && booking.BookedFor.Before(arg.ToTime) {
+			bookings = append(bookings, booking)
+		}
+	}
+	return bookings, nil
+}
+
+func TestGetPractitionerAvailability(t *testing.T) {
+	day := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	practitioner := db.Practitioners{
+		ID:          uuid.New(),
+		IsAvailable: sql.NullBool{Bool: true, Valid: true},
+		OpensAt:     sql.NullTime{Time: clock(9, 0), Valid: true},
+		ClosesAt:    sql.NullTime{Time: clock(12, 0), Valid: true},
+		WorkingDays: newNullString("daily"),
+	}
+	store := &practitionerStore{
+		practitioner: practitioner,
+		bookings: []db.BookedPractitioners{
+			{ID: uuid.New(), ServiceID: practitioner.ID, BookedFor: clock(10, 0)},
+		},
+	}
+
+	server := newTestServer(t, store)
+	server.config.AppointmentDuration = time.Hour
+	server.config.AvailabilityGranularity = 30 * time.Minute
+
+	query := url.Values{}
+	query.Set("from", day.Format(time.RFC3339))
+	query.Set("to", day.AddDate(0, 0, 1).Format(time.RFC3339))
+	request := httptest.NewRequest(http.MethodGet, "/practitioners/"+practitioner.ID.String()+"/availability?"+query.Encode(), nil)
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+
+	var rsp availabilityResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &rsp); err != nil {
+		t.Fatalf("cannot decode response %q: %v", recorder.Body.String(), err)
+	}
+
+	// One-hour appointments start every half hour; those overlapping the
+	// 10:00 appointment or running past closing are left out
+	want := []availability.Interval{
+		{Start: clock(9, 0), End: clock(10, 0)},
+		{Start: clock(11, 0), End: clock(12, 0)},
+	}
+	if !reflect.DeepEqual(rsp.Slots, want) {
+		t.Fatalf("slots = %v, want %v", rsp.Slots, want)
+	}
+}
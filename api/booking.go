@@ -0,0 +1,256 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/payments"
+)
+
+// bookVenueURI defines the URI parameter for booking a venue.
+type bookVenueURI struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// bookVenueRequest defines the request body for booking a venue.
+type bookVenueRequest struct {
+	BookedFor time.Time `json:"booked_for" binding:"required"`
+	Type      string    `json:"type"`
+}
+
+// bookVenueResponse is a venue booking together with the checkout paying for it.
+type bookVenueResponse struct {
+	db.BookVenueTxResult
+	Checkout *payments.Checkout `json:"checkout,omitempty"`
+}
+
+// bookVenue books a venue for the acting profile and records the purchase.
+// The booking price is paid through the returned checkout.
+// POST /venues/:id/bookings
+func (server *Server) bookVenue(ctx *gin.Context) {
+	var uri bookVenueURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req bookVenueRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	venueID, err := uuid.Parse(uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid venue ID format: %w", err)))
+		return
+	}
+
+	arg := db.BookVenueTxParams{
+		VenueID:    venueID,
+		BookedBy:   currentProfile(ctx).ID,
+		BookedFor:  req.BookedFor,
+		Type:       req.Type,
+		TaxRules:   server.taxRules,
+		PendingTTL: server.config.PendingPurchaseTTL,
+		HoldFor:    server.config.WaitlistHold,
+	}
+
+	result, err := server.store.BookVenueTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("venue not found")))
+			return
+		}
+		ctx.JSON(bookingErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	description := fmt.Sprintf("%s on %s", result.Venue.Name, result.Booking.BookedFor.Format(dateLayout))
+	checkout, ok := server.startCheckout(ctx, description, &result.Purchase)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, bookVenueResponse{BookVenueTxResult: result, Checkout: checkout})
+}
+
+var bookingSorts = []sortOrder{{"booked_for", timeKey}}
+
+// listBookingsRequest defines the paging parameters for listing bookings.
+type listBookingsRequest struct {
+	pageRequest
+}
+
+// listVenueBookings lists the bookings of a venue in date order. Only the
+// venue's owner may see them.
+// GET /venues/:id/bookings
+func (server *Server) listVenueBookings(ctx *gin.Context) {
+	venueID, ok := bindVenueID(ctx)
+	if !ok {
+		return
+	}
+
+	var req listBookingsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.bindPage(ctx, req.pageRequest, bookingSorts...)
+	if !ok {
+		return
+	}
+
+	venue, ok := server.loadVenue(ctx, venueID)
+	if !ok || !server.authorizeOwner(ctx, venue.OwnedBy) {
+		return
+	}
+
+	bookings, err := server.store.ListBookedVenuesByVenue(ctx, db.ListBookedVenuesByVenueParams{
+		VenueID:         venue.ID,
+		CursorID:        p.cursorID(),
+		CursorBookedFor: p.cursorTime(),
+		PageLimit:       p.fetch(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(p, bookings, func(booking db.BookedVenues) (string, uuid.UUID) {
+		return encodeTime(booking.BookedFor), booking.ID
+	}))
+}
+
+// bookPractitionerURI defines the URI parameter for booking a practitioner.
+type bookPractitionerURI struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// bookPractitionerRequest defines the request body for booking a practitioner.
+type bookPractitionerRequest struct {
+	BookedFor time.Time `json:"booked_for" binding:"required"`
+	Type      string    `json:"type"`
+}
+
+// bookPractitionerResponse is an appointment together with the checkout paying for it.
+type bookPractitionerResponse struct {
+	db.BookPractitionerTxResult
+	Checkout *payments.Checkout `json:"checkout,omitempty"`
+}
+
+// bookPractitioner books an appointment with a practitioner for the acting
+// profile and records the purchase. Appointments last APPOINTMENT_DURATION.
+// POST /practitioners/:id/bookings
+func (server *Server) bookPractitioner(ctx *gin.Context) {
+	var uri bookPractitionerURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req bookPractitionerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	serviceID, err := uuid.Parse(uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid practitioner ID format: %w", err)))
+		return
+	}
+
+	arg := db.BookPractitionerTxParams{
+		ServiceID: serviceID,
+		BookedBy:  currentProfile(ctx).ID,
+		BookedFor: req.BookedFor,
+		Type:      req.Type,
+		Duration:  server.config.AppointmentDuration,
+		Currency:  server.config.Currency,
+		TaxRules:  server.taxRules,
+	}
+
+	result, err := server.store.BookPractitionerTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("practitioner not found")))
+			return
+		}
+		ctx.JSON(bookingErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	description := fmt.Sprintf("%s at %s", result.Practitioner.Name, result.Booking.BookedFor.Format(time.RFC3339))
+	checkout, ok := server.startCheckout(ctx, description, &result.Purchase)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, bookPractitionerResponse{BookPractitionerTxResult: result, Checkout: checkout})
+}
+
+// listPractitionerBookings lists the appointments of a practitioner in date
+// order. Only the practitioner's creator may see them.
+// GET /practitioners/:id/bookings
+func (server *Server) listPractitionerBookings(ctx *gin.Context) {
+	practitionerID, ok := bindPractitionerID(ctx)
+	if !ok {
+		return
+	}
+
+	var req listBookingsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.bindPage(ctx, req.pageRequest, bookingSorts...)
+	if !ok {
+		return
+	}
+
+	practitioner, ok := server.loadPractitioner(ctx, practitionerID)
+	if !ok || !server.authorizeOwner(ctx, practitioner.CreatedBy) {
+		return
+	}
+
+	bookings, err := server.store.ListBookedPractitionersByService(ctx, db.ListBookedPractitionersByServiceParams{
+		ServiceID:       practitioner.ID,
+		CursorID:        p.cursorID(),
+		CursorBookedFor: p.cursorTime(),
+		PageLimit:       p.fetch(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(p, bookings, func(booking db.BookedPractitioners) (string, uuid.UUID) {
+		return encodeTime(booking.BookedFor), booking.ID
+	}))
+}
+
+// bookingErrorStatus maps errors returned by the booking transactions to HTTP status codes.
+func bookingErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrVenueAlreadyBooked),
+		errors.Is(err, db.ErrVenueDayHeld),
+		errors.Is(err, db.ErrAppointmentConflict):
+		return http.StatusConflict
+	case errors.Is(err, db.ErrVenueUnavailable),
+		errors.Is(err, db.ErrVenueClosed),
+		errors.Is(err, db.ErrPractitionerUnavailable),
+		errors.Is(err, db.ErrOutsideWorkingHours):
+		return http.StatusUnprocessableEntity
+	default:
+		return dbErrorStatus(err)
+	}
+}
@@ -0,0 +1,322 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/payments"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// cancelRequest defines the optional request body of a cancellation.
+type cancelRequest struct {
+	Reason string `json:"reason" binding:"max=255"`
+}
+
+// bindCancelRequest binds the cancellation body, which may be left out.
+func bindCancelRequest(ctx *gin.Context) (cancelRequest, bool) {
+	var req cancelRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return req, false
+	}
+	return req, true
+}
+
+// cancellingParty works out who is cancelling. The buyer cancels under the
+// cancellation policy; the seller's owners and managers or an admin cancel
+// with a full refund. It writes the error response and returns false when
+// the user is neither.
+func (server *Server) cancellingParty(ctx *gin.Context, buyer uuid.UUID, seller uuid.NullUUID) (fullRefund bool, ok bool) {
+	if currentProfile(ctx).ID == buyer {
+		return false, true
+	}
+
+	owner, status, err := server.isOwner(ctx, seller)
+	if err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return false, false
+	}
+	if !owner {
+		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("only the buyer or the seller can cancel")))
+		return false, false
+	}
+	return true, true
+}
+
+// bookingURI defines the URI parameter for addressing a venue booking or
+// practitioner appointment by ID.
+type bookingURI struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// cancelBooking cancels a venue booking or a practitioner appointment and
+// refunds its purchase according to the venue's or practitioner's
+// cancellation policy. The freed venue day goes to the next profile on the
+// venue's waitlist.
+// POST /bookings/:id/cancel
+func (server *Server) cancelBooking(ctx *gin.Context) {
+	var uri bookingURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	bookingID, err := uuid.Parse(uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid booking ID format: %w", err)))
+		return
+	}
+
+	req, ok := bindCancelRequest(ctx)
+	if !ok {
+		return
+	}
+
+	cancelTx, buyer, seller, ok := server.loadBookingParties(ctx, bookingID)
+	if !ok {
+		return
+	}
+
+	fullRefund, ok := server.cancellingParty(ctx, buyer, seller)
+	if !ok {
+		return
+	}
+
+	result, err := cancelTx(ctx, db.CancelTxParams{
+		ID:          bookingID,
+		CancelledBy: currentProfile(ctx).ID,
+		Reason:      req.Reason,
+		FullRefund:  fullRefund,
+		Now:         time.Now(),
+		HoldFor:     server.config.WaitlistHold,
+	})
+	if err != nil {
+		ctx.JSON(cancelErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	if !server.issueRefund(ctx, &result) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// cancelTxFunc is one of the store's cancellation transactions.
+type cancelTxFunc func(ctx context.Context, arg db.CancelTxParams) (db.CancelTxResult, error)
+
+// loadBookingParties finds a venue booking or practitioner appointment by ID
+// and returns the transaction cancelling it, the booking profile and the
+// profile selling the booked venue or practitioner.
+func (server *Server) loadBookingParties(ctx *gin.Context, bookingID uuid.UUID) (cancelTxFunc, uuid.UUID, uuid.NullUUID, bool) {
+	venueBooking, err := server.store.GetBookedVenue(ctx, bookingID)
+	if err == nil {
+		venue, ok := server.loadVenue(ctx, venueBooking.VenueID)
+		if !ok {
+			return nil, uuid.UUID{}, uuid.NullUUID{}, false
+		}
+		return server.store.CancelVenueBookingTx, venueBooking.BookedBy, venue.OwnedBy, true
+	}
+	if err != sql.ErrNoRows {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return nil, uuid.UUID{}, uuid.NullUUID{}, false
+	}
+
+	appointment, err := server.store.GetBookedPractitioner(ctx, bookingID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("booking not found")))
+			return nil, uuid.UUID{}, uuid.NullUUID{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return nil, uuid.UUID{}, uuid.NullUUID{}, false
+	}
+
+	practitioner, ok := server.loadPractitioner(ctx, appointment.ServiceID)
+	if !ok {
+		return nil, uuid.UUID{}, uuid.NullUUID{}, false
+	}
+	return server.store.CancelPractitionerBookingTx, appointment.BookedBy, practitioner.CreatedBy, true
+}
+
+// issueRefund pays out the refund owed by a cancellation through the
+// payment provider and records the outcome. A refund the provider rejects
+// is recorded as failed, to be settled by hand; only a database error fails
+// the request.
+func (server *Server) issueRefund(ctx *gin.Context, result *db.CancelTxResult) bool {
+	cancellation := result.Cancellation
+	if util.RefundStatus(cancellation.RefundStatus) != util.RefundPending {
+		return true
+	}
+
+	arg := db.RecordCancellationRefundParams{
+		ID:           cancellation.ID,
+		RefundStatus: string(util.RefundIssued),
+	}
+
+	refund, err := server.payments.Refund(ctx, payments.RefundParams{
+		PaymentID: result.Purchase.ProviderPaymentID.String,
+		Amount:    int64(cancellation.RefundAmount),
+		Reason:    cancellation.Reason.String,
+	})
+	if err != nil {
+		arg.RefundStatus = string(util.RefundFailed)
+	} else {
+		arg.ProviderRefundID = sql.NullString{String: refund.ID, Valid: true}
+	}
+
+	result.Cancellation, err = server.store.RecordCancellationRefund(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+	return true
+}
+
+// cancelErrorStatus maps errors returned by the cancellation transactions to HTTP status codes.
+func cancelErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, db.ErrAlreadyCancelled):
+		return http.StatusConflict
+	case errors.Is(err, db.ErrNotTicketPurchase):
+		return http.StatusUnprocessableEntity
+	default:
+		return dbErrorStatus(err)
+	}
+}
+
+// cancellationPolicyRequest defines the request body for setting a
+// cancellation policy.
+type cancellationPolicyRequest struct {
+	FullRefundHours      *int32 `json:"full_refund_hours" binding:"required,min=0"`
+	NoRefundHours        *int32 `json:"no_refund_hours" binding:"required,min=0"`
+	PartialRefundPercent *int32 `json:"partial_refund_percent" binding:"required,min=0,max=100"`
+}
+
+// bindCancellationPolicy binds and validates a cancellation policy.
+func bindCancellationPolicy(ctx *gin.Context) (util.CancellationPolicy, bool) {
+	var req cancellationPolicyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return util.CancellationPolicy{}, false
+	}
+
+	policy := util.CancellationPolicy{
+		FullRefundHours:      *req.FullRefundHours,
+		NoRefundHours:        *req.NoRefundHours,
+		PartialRefundPercent: *req.PartialRefundPercent,
+	}
+	if err := policy.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		return util.CancellationPolicy{}, false
+	}
+	return policy, true
+}
+
+// updateVenueCancellationPolicy sets the cancellation policy of a venue's bookings.
+// PUT /venues/:id/cancellation-policy
+func (server *Server) updateVenueCancellationPolicy(ctx *gin.Context) {
+	venueID, ok := bindVenueID(ctx)
+	if !ok {
+		return
+	}
+
+	policy, ok := bindCancellationPolicy(ctx)
+	if !ok {
+		return
+	}
+
+	venue, ok := server.loadVenue(ctx, venueID)
+	if !ok || !server.authorizeOwner(ctx, venue.OwnedBy) {
+		return
+	}
+
+	venue, err := server.store.UpdateVenueCancellationPolicy(ctx, db.UpdateVenueCancellationPolicyParams{
+		ID:                   venue.ID,
+		FullRefundHours:      policy.FullRefundHours,
+		NoRefundHours:        policy.NoRefundHours,
+		PartialRefundPercent: policy.PartialRefundPercent,
+	})
+	if err != nil {
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, venue)
+}
+
+// updatePractitionerCancellationPolicy sets the cancellation policy of a
+// practitioner's appointments.
+// PUT /practitioners/:id/cancellation-policy
+func (server *Server) updatePractitionerCancellationPolicy(ctx *gin.Context) {
+	practitionerID, ok := bindPractitionerID(ctx)
+	if !ok {
+		return
+	}
+
+	policy, ok := bindCancellationPolicy(ctx)
+	if !ok {
+		return
+	}
+
+	practitioner, ok := server.loadPractitioner(ctx, practitionerID)
+	if !ok || !server.authorizeOwner(ctx, practitioner.CreatedBy) {
+		return
+	}
+
+	practitioner, err := server.store.UpdatePractitionerCancellationPolicy(ctx, db.UpdatePractitionerCancellationPolicyParams{
+		ID:                   practitioner.ID,
+		FullRefundHours:      policy.FullRefundHours,
+		NoRefundHours:        policy.NoRefundHours,
+		PartialRefundPercent: policy.PartialRefundPercent,
+	})
+	if err != nil {
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, practitioner)
+}
+
+// updateEventCancellationPolicy sets the cancellation policy of an event's tickets.
+// PUT /events/:id/cancellation-policy
+func (server *Server) updateEventCancellationPolicy(ctx *gin.Context) {
+	eventID, ok := bindEventID(ctx)
+	if !ok {
+		return
+	}
+
+	policy, ok := bindCancellationPolicy(ctx)
+	if !ok {
+		return
+	}
+
+	event, ok := server.loadEvent(ctx, eventID)
+	if !ok || !server.authorizeOwner(ctx, event.CreatedBy) {
+		return
+	}
+
+	event, err := server.store.UpdateEventCancellationPolicy(ctx, db.UpdateEventCancellationPolicyParams{
+		ID:                   event.ID,
+		FullRefundHours:      policy.FullRefundHours,
+		NoRefundHours:        policy.NoRefundHours,
+		PartialRefundPercent: policy.PartialRefundPercent,
+	})
+	if err != nil {
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	server.respondEvent(ctx, http.StatusOK, event)
+}
@@ -0,0 +1,232 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/money"
+)
+
+// currencyHeaderKey names the caller's preferred currency, like the
+// currency query parameter.
+const currencyHeaderKey = "X-Currency"
+
+var errNoExchangeRate = errors.New("no exchange rate")
+
+// priceCurrency returns the currency of a new price, CURRENCY unless one was given.
+func (server *Server) priceCurrency(code string) string {
+	if code == "" {
+		return server.config.Currency
+	}
+	return code
+}
+
+// priceConverter renders prices in the caller's preferred currency. Each
+// exchange rate is looked up once per request.
+type priceConverter struct {
+	store  db.Store
+	target money.Currency
+	rates  map[money.Currency]money.Rate
+}
+
+// newPriceConverter reads the preferred currency from the currency query
+// parameter or the X-Currency header. It returns nil when the caller has no
+// preference, and writes the error response for an unknown currency.
+func (server *Server) newPriceConverter(ctx *gin.Context) (*priceConverter, bool) {
+	code := ctx.Query("currency")
+	if code == "" {
+		code = ctx.GetHeader(currencyHeaderKey)
+	}
+	if code == "" {
+		return nil, true
+	}
+
+	target, err := money.ParseCurrency(code)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return nil, false
+	}
+
+	return &priceConverter{
+		store:  server.store,
+		target: target,
+		rates:  make(map[money.Currency]money.Rate),
+	}, true
+}
+
+// convert renders a price stored in currency in the preferred currency.
+// Missing prices stay missing.
+func (conv *priceConverter) convert(ctx *gin.Context, amount sql.NullInt32, currency string) (*money.Money, error) {
+	if !amount.Valid {
+		return nil, nil
+	}
+
+	from, err := money.ParseCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	price := money.New(int64(amount.Int32), from)
+	if from == conv.target {
+		return &price, nil
+	}
+
+	rate, err := conv.rate(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+
+	price, err = rate.Convert(price)
+	if err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+// rate finds the exchange rate from a currency into the preferred one,
+// inverting the opposite rate if only that is known.
+func (conv *priceConverter) rate(ctx *gin.Context, from money.Currency) (money.Rate, error) {
+	if rate, ok := conv.rates[from]; ok {
+		return rate, nil
+	}
+
+	row, err := conv.store.GetExchangeRate(ctx, db.GetExchangeRateParams{Base: string(from), Quote: string(conv.target)})
+	inverse := false
+	if err == sql.ErrNoRows {
+		row, err = conv.store.GetExchangeRate(ctx, db.GetExchangeRateParams{Base: string(conv.target), Quote: string(from)})
+		inverse = true
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return money.Rate{}, fmt.Errorf("%w from %s to %s", errNoExchangeRate, from, conv.target)
+		}
+		return money.Rate{}, err
+	}
+
+	rate, err := money.ParseRate(row.Base, row.Quote, row.Rate)
+	if err != nil {
+		return money.Rate{}, err
+	}
+	if inverse {
+		rate = rate.Invert()
+	}
+
+	conv.rates[from] = rate
+	return rate, nil
+}
+
+// convertErrorStatus maps errors returned while converting prices to HTTP status codes.
+func convertErrorStatus(err error) int {
+	if errors.Is(err, errNoExchangeRate) {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+// venuePrices are a venue's prices in the caller's preferred currency.
+type venuePrices struct {
+	Rent         *money.Money `json:"rent,omitempty"`
+	BookingPrice *money.Money `json:"booking_price,omitempty"`
+}
+
+// venueResponse is a venue, with its prices converted when the caller
+// asked for a currency.
+type venueResponse struct {
+	db.Venues
+	Display *venuePrices `json:"display,omitempty"`
+}
+
+// newVenueResponses converts the prices of venues for the caller. It writes
+// the error response and returns false when a price cannot be converted.
+func (server *Server) newVenueResponses(ctx *gin.Context, venues ...db.Venues) ([]venueResponse, bool) {
+	conv, ok := server.newPriceConverter(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	rsp := make([]venueResponse, 0, len(venues))
+	for _, venue := range venues {
+		item := venueResponse{Venues: venue}
+		if conv != nil {
+			rent, err := conv.convert(ctx, venue.Rent, venue.Currency)
+			if err != nil {
+				ctx.JSON(convertErrorStatus(err), errorResponse(err))
+				return nil, false
+			}
+			bookingPrice, err := conv.convert(ctx, venue.BookingPrice, venue.Currency)
+			if err != nil {
+				ctx.JSON(convertErrorStatus(err), errorResponse(err))
+				return nil, false
+			}
+			item.Display = &venuePrices{Rent: rent, BookingPrice: bookingPrice}
+		}
+		rsp = append(rsp, item)
+	}
+	return rsp, true
+}
+
+// ticketTierResponse is a ticket tier, with its price converted when the
+// caller asked for a currency.
+type ticketTierResponse struct {
+	db.TicketTiers
+	DisplayPrice *money.Money `json:"display_price,omitempty"`
+}
+
+// newTicketTierResponses converts the prices of ticket tiers for the caller.
+// It writes the error response and returns false when a price cannot be converted.
+func (server *Server) newTicketTierResponses(ctx *gin.Context, tiers ...db.TicketTiers) ([]ticketTierResponse, bool) {
+	conv, ok := server.newPriceConverter(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	rsp := make([]ticketTierResponse, 0, len(tiers))
+	for _, tier := range tiers {
+		item := ticketTierResponse{TicketTiers: tier}
+		if conv != nil {
+			price, err := conv.convert(ctx, sql.NullInt32{Int32: tier.Price, Valid: true}, tier.Currency)
+			if err != nil {
+				ctx.JSON(convertErrorStatus(err), errorResponse(err))
+				return nil, false
+			}
+			item.DisplayPrice = price
+		}
+		rsp = append(rsp, item)
+	}
+	return rsp, true
+}
+
+// purchaseResponse is a purchase, with its amount converted when the caller
+// asked for a currency.
+type purchaseResponse struct {
+	db.Purchases
+	DisplayAmount *money.Money `json:"display_amount,omitempty"`
+}
+
+// newPurchaseResponses converts the amounts of purchases for the caller.
+// It writes the error response and returns false when an amount cannot be converted.
+func (server *Server) newPurchaseResponses(ctx *gin.Context, purchases ...db.Purchases) ([]purchaseResponse, bool) {
+	conv, ok := server.newPriceConverter(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	rsp := make([]purchaseResponse, 0, len(purchases))
+	for _, purchase := range purchases {
+		item := purchaseResponse{Purchases: purchase}
+		if conv != nil {
+			amount, err := conv.convert(ctx, purchase.Amount, purchase.Currency)
+			if err != nil {
+				ctx.JSON(convertErrorStatus(err), errorResponse(err))
+				return nil, false
+			}
+			item.DisplayAmount = amount
+		}
+		rsp = append(rsp, item)
+	}
+	return rsp, true
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lib/pq"
+)
+
+// Postgres error codes the API translates into client errors.
+const (
+	pqNotNullViolation    = "23502"
+	pqForeignKeyViolation = "23503"
+	pqUniqueViolation     = "23505"
+	pqCheckViolation      = "23514"
+)
+
+// pqErrorCode returns the Postgres error code of err, or "" if err did not
+// come from Postgres.
+func pqErrorCode(err error) pq.ErrorCode {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code
+	}
+	return ""
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation.
+func isUniqueViolation(err error) bool {
+	return pqErrorCode(err) == pqUniqueViolation
+}
+
+// dbErrorStatus maps errors returned by the store to HTTP status codes.
+// Conflicts with existing rows become 409, values the schema rejects 422.
+func dbErrorStatus(err error) int {
+	switch pqErrorCode(err) {
+	case pqUniqueViolation, pqForeignKeyViolation:
+		return http.StatusConflict
+	case pqCheckViolation, pqNotNullViolation:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
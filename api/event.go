@@ -0,0 +1,471 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+const dateLayout = "2006-01-02"
+
+// eventRequest defines the request body for creating or replacing an event.
+type eventRequest struct {
+	VenueID         string     `json:"venue_id" binding:"omitempty,uuid"`
+	ImageLinks      []string   `json:"image_links" binding:"omitempty,dive,url"`
+	Name            string     `json:"name" binding:"required,max=255"`
+	Theme           string     `json:"theme" binding:"max=255"`
+	Description     string     `json:"description" binding:"max=255"`
+	Audience        string     `json:"audience" binding:"max=255"`
+	Activities      []string   `json:"activities" binding:"omitempty,dive,required,max=255"`
+	StartTime       *time.Time `json:"start_time"`
+	StartDate       string     `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate         string     `json:"end_date" binding:"required,datetime=2006-01-02"`
+	TotalParticpant *int32     `json:"total_particpant" binding:"omitempty,min=0"`
+}
+
+// eventFields holds the parsed and validated fields of an eventRequest.
+type eventFields struct {
+	venueID   uuid.NullUUID
+	startDate time.Time
+	endDate   time.Time
+}
+
+// parse converts the string fields of the request and checks the dates.
+func (req eventRequest) parse() (eventFields, error) {
+	var fields eventFields
+	var err error
+
+	if req.VenueID != "" {
+		fields.venueID.UUID, err = uuid.Parse(req.VenueID)
+		if err != nil {
+			return fields, fmt.Errorf("invalid venue ID format: %w", err)
+		}
+		fields.venueID.Valid = true
+	}
+
+	fields.startDate, err = time.Parse(dateLayout, req.StartDate)
+	if err != nil {
+		return fields, fmt.Errorf("invalid start_date: %w", err)
+	}
+	fields.endDate, err = time.Parse(dateLayout, req.EndDate)
+	if err != nil {
+		return fields, fmt.Errorf("invalid end_date: %w", err)
+	}
+
+	if fields.endDate.Before(fields.startDate) {
+		return fields, errors.New("end_date must not be before start_date")
+	}
+	if req.StartTime != nil && req.StartTime.Format(dateLayout) != req.StartDate {
+		return fields, errors.New("start_time must fall on start_date")
+	}
+	return fields, nil
+}
+
+// checkEventVenueBooked verifies that the organiser holds a booking of the
+// venue for every day the event runs.
+func (server *Server) checkEventVenueBooked(ctx *gin.Context, organiser uuid.UUID, fields eventFields) (int, error) {
+	if !fields.venueID.Valid {
+		return http.StatusOK, nil
+	}
+
+	_, err := server.store.GetVenue(ctx, fields.venueID.UUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return http.StatusUnprocessableEntity, errors.New("venue not found")
+		}
+		return http.StatusInternalServerError, err
+	}
+
+	bookedDays, err := server.store.CountBookedVenueDays(ctx, db.CountBookedVenueDaysParams{
+		VenueID:  fields.venueID.UUID,
+		BookedBy: organiser,
+		FromDate: fields.startDate,
+		ToDate:   fields.endDate,
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	eventDays := int64(fields.endDate.Sub(fields.startDate)/(24*time.Hour)) + 1
+	if bookedDays < eventDays {
+		return http.StatusUnprocessableEntity, errors.New("event dates are not covered by a booking of its venue")
+	}
+	return http.StatusOK, nil
+}
+
+// eventResponse is an event together with how many profiles favourited it.
+type eventResponse struct {
+	db.Events
+	FavouriteCount int64 `json:"favourite_count"`
+}
+
+// newEventResponses attaches favourite counts to events with a single query.
+func (server *Server) newEventResponses(ctx *gin.Context, events []db.Events) ([]eventResponse, error) {
+	ids := make([]uuid.UUID, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+
+	counts, err := server.store.CountFavouritesByEvents(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	countByEvent := make(map[uuid.UUID]int64, len(counts))
+	for _, count := range counts {
+		countByEvent[count.EventID.UUID] = count.FavouriteCount
+	}
+
+	rsp := make([]eventResponse, 0, len(events))
+	for _, event := range events {
+		rsp = append(rsp, eventResponse{Events: event, FavouriteCount: countByEvent[event.ID]})
+	}
+	return rsp, nil
+}
+
+// respondEvent writes a single event with its favourite count.
+func (server *Server) respondEvent(ctx *gin.Context, status int, event db.Events) {
+	rsp, err := server.newEventResponses(ctx, []db.Events{event})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(status, rsp[0])
+}
+
+// createEvent handles the creation of a draft event by the acting profile.
+// POST /events
+func (server *Server) createEvent(ctx *gin.Context) {
+	var req eventRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	fields, err := req.parse()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	organiser := currentProfile(ctx).ID
+	if status, err := server.checkEventVenueBooked(ctx, organiser, fields); err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+
+	arg := db.CreateEventParams{
+		VenueID:         fields.venueID,
+		ImageLinks:      req.ImageLinks,
+		Name:            newNullString(req.Name),
+		Theme:           newNullString(req.Theme),
+		Description:     newNullString(req.Description),
+		Audience:        newNullString(req.Audience),
+		Activities:      req.Activities,
+		CreatedBy:       uuid.NullUUID{UUID: organiser, Valid: true},
+		StartTime:       newNullTime(req.StartTime),
+		StartDate:       fields.startDate,
+		EndDate:         sql.NullTime{Time: fields.endDate, Valid: true},
+		TotalParticpant: newNullInt32(req.TotalParticpant),
+	}
+
+	event, err := server.store.CreateEvent(ctx, arg)
+	if err != nil {
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	server.respondEvent(ctx, http.StatusCreated, event)
+}
+
+// eventURI defines the URI parameter for addressing an event by ID.
+type eventURI struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// bindEventID parses the event ID from the URI.
+func bindEventID(ctx *gin.Context) (uuid.UUID, bool) {
+	var uri eventURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return uuid.UUID{}, false
+	}
+
+	eventID, err := uuid.Parse(uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid event ID format: %w", err)))
+		return uuid.UUID{}, false
+	}
+	return eventID, true
+}
+
+// loadEvent fetches an event, writing a 404 if it does not exist.
+func (server *Server) loadEvent(ctx *gin.Context, eventID uuid.UUID) (db.Events, bool) {
+	event, err := server.store.GetEvent(ctx, eventID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("event not found")))
+			return db.Events{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return db.Events{}, false
+	}
+	return event, true
+}
+
+// getEvent handles fetching a single event by ID. Drafts are only shown to
+// their organiser; everyone else gets a 404.
+// GET /events/:id
+func (server *Server) getEvent(ctx *gin.Context) {
+	eventID, ok := bindEventID(ctx)
+	if !ok {
+		return
+	}
+
+	event, ok := server.loadEvent(ctx, eventID)
+	if !ok {
+		return
+	}
+
+	if util.EventStatus(event.Status) == util.EventDraft {
+		owner, err := server.callerOwns(ctx, event.CreatedBy)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if !owner {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("event not found")))
+			return
+		}
+	}
+
+	server.respondEvent(ctx, http.StatusOK, event)
+}
+
+var eventSorts = []sortOrder{{"start", timeKey}, {"newest", timeKey}}
+
+// listEventsRequest defines the paging parameters and status filter for listing events.
+type listEventsRequest struct {
+	pageRequest
+	Status string `form:"status" binding:"omitempty,oneof=published cancelled completed"`
+}
+
+// listEvents handles fetching a page of events that are no longer drafts.
+// GET /events?sort=start|newest&status=...
+func (server *Server) listEvents(ctx *gin.Context) {
+	var req listEventsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.bindPage(ctx, req.pageRequest, eventSorts...)
+	if !ok {
+		return
+	}
+
+	arg := db.ListEventsParams{
+		Status:    newNullString(req.Status),
+		Sort:      p.sort.name,
+		CursorID:  p.cursorID(),
+		PageLimit: p.fetch(),
+	}
+	if p.sort.name == "newest" {
+		arg.CursorCreatedAt = p.cursorTime()
+	} else {
+		arg.CursorStartDate = p.cursorTime()
+	}
+
+	events, err := server.store.ListEvents(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := server.newEventResponses(ctx, events)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(p, rsp, func(event eventResponse) (string, uuid.UUID) {
+		if p.sort.name == "newest" {
+			return encodeTime(event.CreatedAt), event.ID
+		}
+		return encodeTime(event.StartDate), event.ID
+	}))
+}
+
+// updateEvent handles replacing the details of a draft or published event.
+// The participant limit cannot drop below the tickets already sold.
+// PUT /events/:id
+func (server *Server) updateEvent(ctx *gin.Context) {
+	eventID, ok := bindEventID(ctx)
+	if !ok {
+		return
+	}
+
+	var req eventRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	fields, err := req.parse()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	event, ok := server.loadEvent(ctx, eventID)
+	if !ok || !server.authorizeOwner(ctx, event.CreatedBy) {
+		return
+	}
+
+	if !util.EventStatus(event.Status).IsEditable() {
+		ctx.JSON(http.StatusConflict, errorResponse(fmt.Errorf("cannot update a %s event", event.Status)))
+		return
+	}
+
+	if status, err := server.checkEventVenueBooked(ctx, event.CreatedBy.UUID, fields); err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+
+	arg := db.UpdateEventParams{
+		ID:              event.ID,
+		VenueID:         fields.venueID,
+		ImageLinks:      req.ImageLinks,
+		Name:            newNullString(req.Name),
+		Theme:           newNullString(req.Theme),
+		Description:     newNullString(req.Description),
+		Audience:        newNullString(req.Audience),
+		Activities:      req.Activities,
+		StartTime:       newNullTime(req.StartTime),
+		StartDate:       fields.startDate,
+		EndDate:         sql.NullTime{Time: fields.endDate, Valid: true},
+		TotalParticpant: newNullInt32(req.TotalParticpant),
+	}
+
+	event, err = server.store.UpdateEventTx(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("event not found for update")))
+			return
+		}
+		if errors.Is(err, db.ErrCapacityBelowSold) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	server.respondEvent(ctx, http.StatusOK, event)
+}
+
+// updateEventStatusRequest defines the request body for moving an event through its lifecycle.
+type updateEventStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=draft published cancelled completed"`
+}
+
+// updateEventStatus handles lifecycle transitions:
+// draft -> published|cancelled, published -> cancelled|completed.
+// Cancelling an event cancels its ticket purchases with a full refund.
+// PUT /events/:id/status
+func (server *Server) updateEventStatus(ctx *gin.Context) {
+	eventID, ok := bindEventID(ctx)
+	if !ok {
+		return
+	}
+
+	var req updateEventStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	event, ok := server.loadEvent(ctx, eventID)
+	if !ok || !server.authorizeOwner(ctx, event.CreatedBy) {
+		return
+	}
+
+	from, to := util.EventStatus(event.Status), util.EventStatus(req.Status)
+	if !from.CanTransition(to) {
+		ctx.JSON(http.StatusConflict, errorResponse(fmt.Errorf("cannot move event from %s to %s", from, to)))
+		return
+	}
+
+	if to == util.EventCancelled {
+		server.cancelEvent(ctx, event)
+		return
+	}
+
+	event, err := server.store.UpdateEventStatus(ctx, db.UpdateEventStatusParams{
+		ID:     event.ID,
+		Status: string(to),
+	})
+	if err != nil {
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	server.respondEvent(ctx, http.StatusOK, event)
+}
+
+// cancelEvent cancels an event together with its ticket purchases and
+// refunds every buyer in full.
+func (server *Server) cancelEvent(ctx *gin.Context, event db.Events) {
+	result, err := server.store.CancelEventTx(ctx, db.CancelTxParams{
+		ID:          event.ID,
+		CancelledBy: currentProfile(ctx).ID,
+		Reason:      "event cancelled",
+		Now:         time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrEventNotCancellable) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	for i := range result.Cancellations {
+		if !server.issueRefund(ctx, &result.Cancellations[i]) {
+			return
+		}
+	}
+
+	server.respondEvent(ctx, http.StatusOK, result.Event)
+}
+
+// deleteEvent handles deleting an event by ID.
+// DELETE /events/:id
+func (server *Server) deleteEvent(ctx *gin.Context) {
+	eventID, ok := bindEventID(ctx)
+	if !ok {
+		return
+	}
+
+	event, ok := server.loadEvent(ctx, eventID)
+	if !ok || !server.authorizeOwner(ctx, event.CreatedBy) {
+		return
+	}
+
+	err := server.store.DeleteEvent(ctx, event.ID)
+	if err != nil {
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// addFavourite marks an event as a favourite of the acting profile.
+// Favouriting an event twice returns the existing favourite. Only published
+// events can be favourited, except by their organiser.
+// PUT /events/:id/favourite
+func (server *Server) addFavourite(ctx *gin.Context) {
+	eventID, ok := bindEventID(ctx)
+	if !ok {
+		return
+	}
+
+	event, ok := server.loadEvent(ctx, eventID)
+	if !ok {
+		return
+	}
+
+	if status := util.EventStatus(event.Status); status != util.EventPublished {
+		owner, code, err := server.isOwner(ctx, event.CreatedBy)
+		if err != nil {
+			ctx.JSON(code, errorResponse(err))
+			return
+		}
+		if !owner {
+			if status == util.EventDraft {
+				ctx.JSON(http.StatusNotFound, errorResponse(errors.New("event not found")))
+				return
+			}
+			ctx.JSON(http.StatusConflict, errorResponse(fmt.Errorf("cannot favourite a %s event", status)))
+			return
+		}
+	}
+
+	favourite, err := server.store.CreateFavourite(ctx, db.CreateFavouriteParams{
+		EventID: uuid.NullUUID{UUID: event.ID, Valid: true},
+		AddedBy: uuid.NullUUID{UUID: currentProfile(ctx).ID, Valid: true},
+	})
+	if err != nil {
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, favourite)
+}
+
+// removeFavourite removes an event from the acting profile's favourites.
+// Removing an event that is not a favourite is not an error.
+// DELETE /events/:id/favourite
+func (server *Server) removeFavourite(ctx *gin.Context) {
+	eventID, ok := bindEventID(ctx)
+	if !ok {
+		return
+	}
+
+	err := server.store.DeleteFavouriteByUserAndEvent(ctx, db.DeleteFavouriteByUserAndEventParams{
+		EventID: uuid.NullUUID{UUID: eventID, Valid: true},
+		AddedBy: uuid.NullUUID{UUID: currentProfile(ctx).ID, Valid: true},
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// favouriteEventResponse is a favourited event and when it was favourited.
+type favouriteEventResponse struct {
+	eventResponse
+	FavouritedAt time.Time `json:"favourited_at"`
+	favouriteID  uuid.UUID
+}
+
+var favouriteSorts = []sortOrder{{"newest", timeKey}}
+
+// listMyFavouritesRequest defines the paging parameters for listing favourites.
+type listMyFavouritesRequest struct {
+	pageRequest
+}
+
+// listMyFavourites lists the events favourited by the acting profile, most
+// recent first. Drafts are left out unless the profile organises them.
+// GET /me/favourites
+func (server *Server) listMyFavourites(ctx *gin.Context) {
+	var req listMyFavouritesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.bindPage(ctx, req.pageRequest, favouriteSorts...)
+	if !ok {
+		return
+	}
+
+	rows, err := server.store.ListFavouriteEventsByUser(ctx, db.ListFavouriteEventsByUserParams{
+		AddedBy:         uuid.NullUUID{UUID: currentProfile(ctx).ID, Valid: true},
+		CursorID:        p.cursorID(),
+		CursorCreatedAt: p.cursorTime(),
+		PageLimit:       p.fetch(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	events := make([]db.Events, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, row.Events)
+	}
+
+	withCounts, err := server.newEventResponses(ctx, events)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]favouriteEventResponse, 0, len(rows))
+	for i, row := range rows {
+		rsp = append(rsp, favouriteEventResponse{
+			eventResponse: withCounts[i],
+			FavouritedAt:  row.FavouritedAt,
+			favouriteID:   row.FavouriteID,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(p, rsp, func(favourite favouriteEventResponse) (string, uuid.UUID) {
+		return encodeTime(favourite.FavouritedAt), favourite.favouriteID
+	}))
+}
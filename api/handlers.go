@@ -2,12 +2,15 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid" // <--- Import uuid package
 	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/token"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -19,6 +22,38 @@ func newNullString(s string) sql.NullString {
 	return sql.NullString{String: s, Valid: true}
 }
 
+// Helper function to create sql.NullInt32 from an optional int32
+func newNullInt32(i *int32) sql.NullInt32 {
+	if i == nil {
+		return sql.NullInt32{Valid: false}
+	}
+	return sql.NullInt32{Int32: *i, Valid: true}
+}
+
+// Helper function to create sql.NullBool from an optional bool
+func newNullBool(b *bool) sql.NullBool {
+	if b == nil {
+		return sql.NullBool{Valid: false}
+	}
+	return sql.NullBool{Bool: *b, Valid: true}
+}
+
+// Helper function to create sql.NullFloat64 from an optional float64
+func newNullFloat64(f *float64) sql.NullFloat64 {
+	if f == nil {
+		return sql.NullFloat64{Valid: false}
+	}
+	return sql.NullFloat64{Float64: *f, Valid: true}
+}
+
+// Helper function to create sql.NullTime from an optional time
+func newNullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{Valid: false}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
 // Function to hash a password
 func hashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -67,12 +102,153 @@ func (server *Server) createUser(ctx *gin.Context) {
 	}
 
 	user, err := server.store.CreateUser(ctx, arg)
+	if err != nil {
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// userResponse is the public view of a user, without the password hash.
+type userResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Firstname string    `json:"firstname"`
+	Lastname  string    `json:"lastname"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newUserResponse(user db.Users) userResponse {
+	return userResponse{
+		ID:        user.ID,
+		Email:     user.Email,
+		Firstname: user.Firstname.String,
+		Lastname:  user.Lastname.String,
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+// loginUserRequest defines the request body for logging in.
+type loginUserRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// loginUserResponse carries the tokens issued on login.
+type loginUserResponse struct {
+	SessionID             uuid.UUID    `json:"session_id"`
+	AccessToken           string       `json:"access_token"`
+	AccessTokenExpiresAt  time.Time    `json:"access_token_expires_at"`
+	RefreshToken          string       `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time    `json:"refresh_token_expires_at"`
+	User                  userResponse `json:"user"`
+}
+
+// loginUser verifies a user's credentials, starts a session and issues
+// an access token together with a refresh token.
+// POST /users/login
+func (server *Server) loginUser(ctx *gin.Context) {
+	var req loginUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("invalid email or password")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !user.Password.Valid || checkPassword(req.Password, user.Password.String) != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("invalid email or password")))
+		return
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.ID, token.PurposeAccess, server.config.AccessTokenDuration)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.ID, token.PurposeRefresh, server.config.RefreshTokenDuration)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
+		ID:           refreshPayload.ID,
+		UserID:       user.ID,
+		RefreshToken: refreshToken,
+		UserAgent:    ctx.Request.UserAgent(),
+		ClientIp:     ctx.ClientIP(),
+		IsBlocked:    false,
+		ExpiresAt:    refreshPayload.ExpiredAt,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := loginUserResponse{
+		SessionID:             session.ID,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshPayload.ExpiredAt,
+		User:                  newUserResponse(user),
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// logoutUserRequest defines the request body for logging out.
+type logoutUserRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// logoutUser revokes the session behind a refresh token.
+// POST /users/logout
+func (server *Server) logoutUser(ctx *gin.Context) {
+	var req logoutUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	refreshPayload, err := server.tokenMaker.VerifyToken(req.RefreshToken, token.PurposeRefresh)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	session, err := server.store.GetSession(ctx, refreshPayload.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("session not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if session.UserID != authPayload(ctx).UserID || session.RefreshToken != req.RefreshToken {
+		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("session does not belong to the authenticated user")))
+		return
+	}
+
+	err = server.store.BlockSession(ctx, session.ID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	ctx.JSON(http.StatusNoContent, nil)
 }
 
 // getUserRequest defines the URI parameter for getting a user by ID.
@@ -107,7 +283,7 @@ func (server *Server) getUser(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	ctx.JSON(http.StatusOK, newUserResponse(user))
 }
 
 // getUserByEmailRequest defines the query parameter for getting a user by email.
@@ -134,17 +310,18 @@ func (server *Server) getUserByEmail(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	ctx.JSON(http.StatusOK, newUserResponse(user))
 }
 
-// listUsersRequest defines optional query parameters for listing users (e.g., pagination).
+var userSorts = []sortOrder{{"email", stringKey}, {"newest", timeKey}}
+
+// listUsersRequest defines the paging parameters for listing users.
 type listUsersRequest struct {
-	// You could add Limit int64 `form:"limit" binding:"min=1"`
-	// You could add Offset int64 `form:"offset" binding:"min=0"`
+	pageRequest
 }
 
-// listUsers handles fetching a list of all users.
-// GET /users
+// listUsers handles fetching a page of users.
+// GET /users?sort=email|newest&limit=...&cursor=...
 func (server *Server) listUsers(ctx *gin.Context) {
 	var req listUsersRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
@@ -152,13 +329,34 @@ func (server *Server) listUsers(ctx *gin.Context) {
 		return
 	}
 
-	users, err := server.store.ListUsers(ctx)
+	p, ok := server.bindPage(ctx, req.pageRequest, userSorts...)
+	if !ok {
+		return
+	}
+
+	users, err := server.store.ListUsers(ctx, db.ListUsersParams{
+		Sort:            p.sort.name,
+		CursorID:        p.cursorID(),
+		CursorEmail:     p.cursorString(),
+		CursorCreatedAt: p.cursorTime(),
+		PageLimit:       p.fetch(),
+	})
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, users)
+	rsp := make([]userResponse, 0, len(users))
+	for _, user := range users {
+		rsp = append(rsp, newUserResponse(user))
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(p, rsp, func(user userResponse) (string, uuid.UUID) {
+		if p.sort.name == "newest" {
+			return encodeTime(user.CreatedAt), user.ID
+		}
+		return user.Email, user.ID
+	}))
 }
 
 // updateuserRequest defines the request body for updating a user.
@@ -217,11 +415,11 @@ func (server *Server) updateUser(ctx *gin.Context) {
 			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("user not found for update")))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	ctx.JSON(http.StatusOK, newUserResponse(user))
 }
 
 // deleteUserRequest defines the URI parameter for deleting a user by ID.
@@ -246,9 +444,22 @@ func (server *Server) deleteUser(ctx *gin.Context) {
 		return
 	}
 
+	// Users may only delete their own account, unless they are an admin
+	if authPayload(ctx).UserID != uuidID {
+		admin, status, err := server.isAdmin(ctx)
+		if err != nil {
+			ctx.JSON(status, errorResponse(err))
+			return
+		}
+		if !admin {
+			ctx.JSON(http.StatusForbidden, errorResponse(errors.New("cannot delete another user's account")))
+			return
+		}
+	}
+
 	err = server.store.DeleteUser(ctx, uuidID) // <--- Pass uuid.UUID
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
 		return
 	}
 
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/invoice"
+	"github.com/tedobanks/tabularasa_backend/money"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// getPurchaseInvoice handles downloading the invoice of a paid purchase as a PDF.
+// GET /purchases/:id/invoice.pdf
+func (server *Server) getPurchaseInvoice(ctx *gin.Context) {
+	server.renderPurchaseDocument(ctx, invoice.KindInvoice)
+}
+
+// getPurchaseReceipt handles downloading the receipt of a paid purchase as a PDF.
+// GET /purchases/:id/receipt.pdf
+func (server *Server) getPurchaseReceipt(ctx *gin.Context) {
+	server.renderPurchaseDocument(ctx, invoice.KindReceipt)
+}
+
+// renderPurchaseDocument renders the invoice or receipt of a purchase for
+// its buyer, its seller or an admin. The invoice is issued on first request
+// if it was not issued when the purchase was paid.
+func (server *Server) renderPurchaseDocument(ctx *gin.Context, kind invoice.Kind) {
+	var uri purchaseURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	purchaseID, err := uuid.Parse(uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid purchase ID format: %w", err)))
+		return
+	}
+
+	purchase, err := server.store.GetPurchase(ctx, purchaseID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("purchase not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !purchase.PurchasedBy.Valid || purchase.PurchasedBy.UUID != currentProfile(ctx).ID {
+		seller, ok := server.purchaseSeller(ctx, purchase)
+		if !ok || !server.authorizeOwner(ctx, seller) {
+			return
+		}
+	}
+
+	result, err := server.store.IssueInvoiceTx(ctx, purchase.ID)
+	if err != nil {
+		ctx.JSON(invoiceErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	doc, err := newInvoiceDocument(kind, result)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	var pdf bytes.Buffer
+	if err := invoice.Render(&pdf, doc); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s.pdf", kind, result.Invoice.Number)
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
+	ctx.Data(http.StatusOK, "application/pdf", pdf.Bytes())
+}
+
+// purchaseSeller returns the profile selling what a purchase paid for: the
+// owner of the venue, or the creator of the practitioner or event. It writes
+// the error response and returns false when the venue, practitioner or event
+// cannot be loaded.
+func (server *Server) purchaseSeller(ctx *gin.Context, purchase db.Purchases) (uuid.NullUUID, bool) {
+	switch {
+	case purchase.VenueID.Valid:
+		venue, ok := server.loadVenue(ctx, purchase.VenueID.UUID)
+		return venue.OwnedBy, ok
+	case purchase.ServiceID.Valid:
+		practitioner, ok := server.loadPractitioner(ctx, purchase.ServiceID.UUID)
+		return practitioner.CreatedBy, ok
+	case purchase.EventID.Valid:
+		event, ok := server.loadEvent(ctx, purchase.EventID.UUID)
+		return event.CreatedBy, ok
+	default:
+		return uuid.NullUUID{}, true
+	}
+}
+
+// newInvoiceDocument prepares an issued invoice for rendering.
+func newInvoiceDocument(kind invoice.Kind, result db.InvoiceTxResult) (invoice.Document, error) {
+	currency, err := money.ParseCurrency(result.Invoice.Currency)
+	if err != nil {
+		return invoice.Document{}, err
+	}
+	amount := func(minor int32) money.Money {
+		return money.New(int64(minor), currency)
+	}
+
+	doc := invoice.Document{
+		Kind:             kind,
+		Number:           result.Invoice.Number,
+		IssuedAt:         result.Invoice.IssuedAt,
+		PaidAt:           result.Purchase.PaidAt.Time,
+		PaymentReference: result.Purchase.ProviderPaymentID.String,
+		Seller: invoice.Party{
+			Name:    result.Invoice.SellerName,
+			Address: result.Invoice.SellerAddress.String,
+			Country: result.Invoice.SellerCountry.String,
+		},
+		Buyer: invoice.Party{
+			Name:    result.Invoice.BuyerName,
+			Address: result.Invoice.BuyerAddress.String,
+			Country: result.Invoice.BuyerCountry.String,
+		},
+		Subtotal: amount(result.Invoice.Subtotal),
+		Tax:      amount(result.Invoice.TaxAmount),
+		Total:    amount(result.Invoice.Total),
+	}
+
+	for _, line := range result.Lines {
+		doc.Lines = append(doc.Lines, invoice.Line{
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitAmount:  amount(line.UnitAmount),
+			Amount:      amount(line.Amount),
+			Tax:         util.InvoiceLineKind(line.Kind) == util.InvoiceLineTax,
+		})
+	}
+	return doc, nil
+}
+
+// invoiceErrorStatus maps errors returned while issuing invoices to HTTP status codes.
+func invoiceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, db.ErrPurchaseNotPaid):
+		return http.StatusConflict
+	case errors.Is(err, db.ErrNoSeller):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
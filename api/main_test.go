@@ -0,0 +1,32 @@
+package api
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+func newTestServer(t *testing.T, store db.Store) *Server {
+	config := util.Config{
+		TokenSymmetricKey:    "12345678901234567890123456789012",
+		PaymentProvider:      "fake",
+		PaymentWebhookSecret: "webhook-secret",
+		Currency:             "USD",
+		DefaultPageSize:      20,
+		MaxPageSize:          100,
+	}
+
+	server, err := NewServer(config, store)
+	if err != nil {
+		t.Fatalf("cannot create server: %v", err)
+	}
+	return server
+}
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+)
+
+var notificationSorts = []sortOrder{{"newest", timeKey}}
+
+// listNotificationsRequest defines the paging parameters for listing notifications.
+type listNotificationsRequest struct {
+	pageRequest
+}
+
+// listNotifications lists the notifications of the acting profile, newest first.
+// GET /me/notifications
+func (server *Server) listNotifications(ctx *gin.Context) {
+	var req listNotificationsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.bindPage(ctx, req.pageRequest, notificationSorts...)
+	if !ok {
+		return
+	}
+
+	notifications, err := server.store.ListNotificationsByProfile(ctx, db.ListNotificationsByProfileParams{
+		ProfileID:       currentProfile(ctx).ID,
+		CursorID:        p.cursorID(),
+		CursorCreatedAt: p.cursorTime(),
+		PageLimit:       p.fetch(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(p, notifications, func(notification db.Notifications) (string, uuid.UUID) {
+		return encodeTime(notification.CreatedAt), notification.ID
+	}))
+}
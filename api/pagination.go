@@ -0,0 +1,212 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// keyKind is the type of the column a list is sorted by.
+type keyKind int
+
+const (
+	stringKey keyKind = iota
+	timeKey
+	intKey
+	floatKey
+)
+
+// sortOrder is one of the whitelisted orderings of a list endpoint.
+type sortOrder struct {
+	name string
+	key  keyKind
+}
+
+// pageRequest defines the query parameters shared by every paginated list.
+type pageRequest struct {
+	Cursor string `form:"cursor"`
+	Limit  int32  `form:"limit" binding:"omitempty,min=1"`
+	Sort   string `form:"sort"`
+}
+
+// pageCursor is the position after which the next page starts: the sort key
+// and ID of the last item returned. It is handed to clients as an opaque token.
+type pageCursor struct {
+	Sort string    `json:"s"`
+	Key  string    `json:"k"`
+	ID   uuid.UUID `json:"i"`
+}
+
+func encodeCursor(cursor pageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(token string) (pageCursor, error) {
+	var cursor pageCursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, errInvalidCursor
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, errInvalidCursor
+	}
+	return cursor, nil
+}
+
+// page is a validated pageRequest.
+type page struct {
+	sort   sortOrder
+	limit  int32
+	cursor *pageCursor
+}
+
+// bindPage validates the paging parameters against the whitelisted sort
+// orders, the first of which is the default. The page size is capped at the
+// configured maximum.
+func (server *Server) bindPage(ctx *gin.Context, req pageRequest, sorts ...sortOrder) (page, bool) {
+	p := page{sort: sorts[0], limit: server.config.DefaultPageSize}
+
+	if req.Sort != "" {
+		names := make([]string, len(sorts))
+		found := false
+		for i, sort := range sorts {
+			names[i] = sort.name
+			if sort.name == req.Sort {
+				p.sort = sort
+				found = true
+			}
+		}
+		if !found {
+			ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("sort must be one of: %s", strings.Join(names, ", "))))
+			return p, false
+		}
+	}
+
+	if req.Limit > 0 {
+		p.limit = req.Limit
+	}
+	if p.limit > server.config.MaxPageSize {
+		p.limit = server.config.MaxPageSize
+	}
+	if p.limit < 1 {
+		p.limit = 1
+	}
+
+	if req.Cursor == "" {
+		return p, true
+	}
+
+	cursor, err := decodeCursor(req.Cursor)
+	if err == nil && cursor.Sort != p.sort.name {
+		err = errInvalidCursor
+	}
+	if err == nil {
+		switch p.sort.key {
+		case timeKey:
+			_, err = time.Parse(time.RFC3339Nano, cursor.Key)
+		case intKey:
+			_, err = strconv.ParseInt(cursor.Key, 10, 32)
+		case floatKey:
+			_, err = strconv.ParseFloat(cursor.Key, 64)
+		}
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidCursor))
+		return p, false
+	}
+
+	p.cursor = &cursor
+	return p, true
+}
+
+// fetch is the number of rows to query: one more than the page size, so
+// that the presence of a further page can be detected.
+func (p page) fetch() int32 {
+	return p.limit + 1
+}
+
+func (p page) cursorID() uuid.NullUUID {
+	if p.cursor == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: p.cursor.ID, Valid: true}
+}
+
+func (p page) cursorString() sql.NullString {
+	if p.cursor == nil || p.sort.key != stringKey {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: p.cursor.Key, Valid: true}
+}
+
+func (p page) cursorTime() sql.NullTime {
+	if p.cursor == nil || p.sort.key != timeKey {
+		return sql.NullTime{}
+	}
+	t, _ := time.Parse(time.RFC3339Nano, p.cursor.Key)
+	return sql.NullTime{Time: t, Valid: true}
+}
+
+func (p page) cursorInt() sql.NullInt32 {
+	if p.cursor == nil || p.sort.key != intKey {
+		return sql.NullInt32{}
+	}
+	n, _ := strconv.ParseInt(p.cursor.Key, 10, 32)
+	return sql.NullInt32{Int32: int32(n), Valid: true}
+}
+
+func (p page) cursorFloat() sql.NullFloat64 {
+	if p.cursor == nil || p.sort.key != floatKey {
+		return sql.NullFloat64{}
+	}
+	f, _ := strconv.ParseFloat(p.cursor.Key, 64)
+	return sql.NullFloat64{Float64: f, Valid: true}
+}
+
+// encodeTime, encodeInt and encodeFloat format sort keys for a pageCursor.
+func encodeTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+func encodeInt(n int32) string {
+	return strconv.FormatInt(int64(n), 10)
+}
+
+func encodeFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// pageResponse is the envelope returned by every list endpoint.
+// NextCursor is empty on the last page.
+type pageResponse[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// newPageResponse trims the extra row fetched by the query and, if there was
+// one, derives the next cursor from the last item kept.
+func newPageResponse[T any](p page, items []T, key func(T) (string, uuid.UUID)) pageResponse[T] {
+	rsp := pageResponse[T]{Items: items}
+	if rsp.Items == nil {
+		rsp.Items = []T{}
+	}
+
+	if int32(len(items)) > p.limit {
+		rsp.Items = items[:p.limit]
+		k, id := key(rsp.Items[p.limit-1])
+		rsp.NextCursor = encodeCursor(pageCursor{Sort: p.sort.name, Key: k, ID: id})
+	}
+	return rsp
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/payments"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// newPaymentProvider creates the payment provider named by PAYMENT_PROVIDER.
+// Webhooks cannot be verified without PAYMENT_WEBHOOK_SECRET, so it is
+// required.
+func newPaymentProvider(config util.Config) (payments.Provider, error) {
+	if config.PaymentWebhookSecret == "" {
+		return nil, errors.New("PAYMENT_WEBHOOK_SECRET must be set")
+	}
+
+	switch config.PaymentProvider {
+	case "", "fake":
+		return payments.NewFakeProvider(config.PaymentWebhookSecret), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", config.PaymentProvider)
+	}
+}
+
+// startCheckout creates a checkout paying the pending purchases and links
+// them to it, updating them in place. It returns nil when there is nothing
+// to pay. If the provider fails, the purchases are marked failed and the
+// error response is written.
+func (server *Server) startCheckout(ctx *gin.Context, description string, purchases ...*db.Purchases) (*payments.Checkout, bool) {
+	var pending []*db.Purchases
+	var amount int64
+	for _, purchase := range purchases {
+		if util.PurchaseStatus(purchase.Status) == util.PurchasePending {
+			pending = append(pending, purchase)
+			amount += int64(purchase.Amount.Int32)
+		}
+	}
+	if len(pending) == 0 {
+		return nil, true
+	}
+
+	checkout, err := server.payments.CreateCheckout(ctx, payments.CheckoutParams{
+		Reference:   pending[0].ID.String(),
+		Description: description,
+		Amount:      amount,
+		Currency:    pending[0].Currency,
+	})
+	if err != nil {
+		for _, purchase := range pending {
+			if err := server.store.FailPurchase(ctx, purchase.ID); err != nil {
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+				return nil, false
+			}
+			purchase.Status = string(util.PurchaseFailed)
+		}
+		ctx.JSON(http.StatusBadGateway, errorResponse(err))
+		return nil, false
+	}
+
+	for _, purchase := range pending {
+		*purchase, err = server.store.SetPurchaseCheckout(ctx, db.SetPurchaseCheckoutParams{
+			Provider:           server.payments.Name(),
+			ProviderCheckoutID: checkout.ID,
+			ID:                 purchase.ID,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return nil, false
+		}
+	}
+
+	return &checkout, true
+}
+
+// handlePaymentWebhook applies an event sent by the payment provider to the
+// purchases of its checkout. Completed checkouts pay their pending
+// purchases and are captured, or voided when none was still pending;
+// failed checkouts fail them. Events that were already applied are
+// acknowledged without changing anything.
+// POST /webhooks/payments
+func (server *Server) handlePaymentWebhook(ctx *gin.Context) {
+	payload, err := ctx.GetRawData()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	event, err := server.payments.VerifyWebhook(payload, ctx.Request.Header)
+	if err != nil {
+		if errors.Is(err, payments.ErrInvalidSignature) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var status util.PurchaseStatus
+	switch event.Type {
+	case payments.EventCheckoutCompleted:
+		status = util.PurchasePaid
+	case payments.EventCheckoutFailed:
+		status = util.PurchaseFailed
+	default:
+		// Acknowledge events we do not handle so the provider stops sending them
+		ctx.JSON(http.StatusOK, db.ApplyPaymentEventTxResult{})
+		return
+	}
+
+	result, err := server.store.ApplyPaymentEventTx(ctx, db.ApplyPaymentEventTxParams{
+		Provider:   server.payments.Name(),
+		EventID:    event.ID,
+		Type:       string(event.Type),
+		CheckoutID: event.CheckoutID,
+		PaymentID:  event.PaymentID,
+		Status:     status,
+		HoldFor:    server.config.WaitlistHold,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if status == util.PurchasePaid {
+		// Money is only taken for purchases that were still waiting for it.
+		// A checkout whose purchases expired or were cancelled meanwhile is
+		// voided. Both are safe to repeat, so a redelivered event retries
+		// whichever failed.
+		if result.Paid {
+			err = server.payments.Capture(ctx, event.PaymentID)
+		} else {
+			err = server.payments.Void(ctx, event.PaymentID)
+		}
+		if err != nil {
+			ctx.JSON(http.StatusBadGateway, errorResponse(err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
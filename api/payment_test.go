@@ -0,0 +1,230 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/payments"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// paymentStore applies payment events in memory the way ApplyPaymentEventTx
+// does: each event is applied once and settles the pending purchases of its
+// checkout.
+type paymentStore struct {
+	db.Store
+
+	events    map[string]bool
+	purchases []db.Purchases
+	calls     int
+}
+
+func (store *paymentStore) ApplyPaymentEventTx(ctx context.Context, arg db.ApplyPaymentEventTxParams) (db.ApplyPaymentEventTxResult, error) {
+	store.calls++
+
+	var result db.ApplyPaymentEventTxResult
+	if store.events[arg.EventID] {
+		result.Duplicate = true
+		result.Paid = store.checkoutPaid(arg.CheckoutID)
+		return result, nil
+	}
+	store.events[arg.EventID] = true
+
+	for i := range store.purchases {
+		purchase := &store.purchases[i]
+		if purchase.ProviderCheckoutID.String != arg.CheckoutID || util.PurchaseStatus(purchase.Status) != util.PurchasePending {
+			continue
+		}
+		purchase.Status = string(arg.Status)
+		result.Purchases = append(result.Purchases, *purchase)
+	}
+	result.Paid = store.checkoutPaid(arg.CheckoutID)
+	return result, nil
+}
+
+// checkoutPaid reports whether any purchase of the checkout is paid.
+func (store *paymentStore) checkoutPaid(checkoutID string) bool {
+	for _, purchase := range store.purchases {
+		if purchase.ProviderCheckoutID.String == checkoutID && util.PurchaseStatus(purchase.Status) == util.PurchasePaid {
+			return true
+		}
+	}
+	return false
+}
+
+// deliverWebhook sends a webhook request to the server.
+func deliverWebhook(server *Server, payload []byte, header http.Header) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/webhooks/payments", bytes.NewReader(payload))
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// decodePaymentResult decodes the body of a webhook response.
+func decodePaymentResult(t *testing.T, recorder *httptest.ResponseRecorder) db.ApplyPaymentEventTxResult {
+	var result db.ApplyPaymentEventTxResult
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		t.Fatalf("cannot decode response %q: %v", recorder.Body.String(), err)
+	}
+	return result
+}
+
+func TestPaymentWebhook(t *testing.T) {
+	store := &paymentStore{events: make(map[string]bool)}
+	server := newTestServer(t, store)
+	provider := server.payments.(*payments.FakeProvider)
+
+	checkout, err := provider.CreateCheckout(context.Background(), payments.CheckoutParams{Amount: 2500, Currency: "USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.purchases = []db.Purchases{{
+		ID:                 uuid.New(),
+		Status:             string(util.PurchasePending),
+		Provider:           newNullString("fake"),
+		ProviderCheckoutID: newNullString(checkout.ID),
+	}}
+
+	event, err := provider.Complete(checkout.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, header, err := provider.WebhookRequest(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("bad signature", func(t *testing.T) {
+		forged := http.Header{}
+		forged.Set(payments.FakeSignatureHeader, "0000")
+
+		recorder := deliverWebhook(server, payload, forged)
+		if recorder.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+		}
+
+		tampered := bytes.Replace(payload, []byte(event.PaymentID), []byte("pay_other"), 1)
+		recorder = deliverWebhook(server, tampered, header)
+		if recorder.Code != http.StatusUnauthorized {
+			t.Fatalf("tampered status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+		}
+
+		if store.calls != 0 {
+			t.Fatalf("store called %d times for unsigned events", store.calls)
+		}
+		if store.purchases[0].Status != string(util.PurchasePending) {
+			t.Fatalf("purchase status = %q, want pending", store.purchases[0].Status)
+		}
+	})
+
+	t.Run("first delivery", func(t *testing.T) {
+		recorder := deliverWebhook(server, payload, header)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+		}
+
+		result := decodePaymentResult(t, recorder)
+		if result.Duplicate {
+			t.Fatal("first delivery reported as duplicate")
+		}
+		if len(result.Purchases) != 1 || result.Purchases[0].Status != string(util.PurchasePaid) {
+			t.Fatalf("purchases = %+v, want one paid purchase", result.Purchases)
+		}
+
+		payment, ok := provider.Payment(event.PaymentID)
+		if !ok || !payment.Captured {
+			t.Fatalf("payment %+v was not captured", payment)
+		}
+	})
+
+	t.Run("duplicate delivery", func(t *testing.T) {
+		before := append([]db.Purchases(nil), store.purchases...)
+
+		recorder := deliverWebhook(server, payload, header)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+		}
+
+		result := decodePaymentResult(t, recorder)
+		if !result.Duplicate {
+			t.Fatal("redelivered event not reported as duplicate")
+		}
+		if len(result.Purchases) != 0 {
+			t.Fatalf("duplicate event settled purchases %+v", result.Purchases)
+		}
+		for i := range before {
+			if store.purchases[i] != before[i] {
+				t.Fatalf("purchase changed from %+v to %+v", before[i], store.purchases[i])
+			}
+		}
+	})
+}
+
+func TestPaymentWebhookAfterExpiry(t *testing.T) {
+	store := &paymentStore{events: make(map[string]bool)}
+	server := newTestServer(t, store)
+	provider := server.payments.(*payments.FakeProvider)
+
+	checkout, err := provider.CreateCheckout(context.Background(), payments.CheckoutParams{Amount: 2500, Currency: "USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The purchase expired and released its place before the buyer paid
+	store.purchases = []db.Purchases{{
+		ID:                 uuid.New(),
+		Status:             string(util.PurchaseFailed),
+		Provider:           newNullString("fake"),
+		ProviderCheckoutID: newNullString(checkout.ID),
+	}}
+
+	event, err := provider.Complete(checkout.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, header, err := provider.WebhookRequest(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, delivery := range []string{"first delivery", "duplicate delivery"} {
+		t.Run(delivery, func(t *testing.T) {
+			recorder := deliverWebhook(server, payload, header)
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+			}
+
+			result := decodePaymentResult(t, recorder)
+			if result.Paid || len(result.Purchases) != 0 {
+				t.Fatalf("result = %+v, want no paid purchases", result)
+			}
+			if store.purchases[0].Status != string(util.PurchaseFailed) {
+				t.Fatalf("purchase status = %q, want failed", store.purchases[0].Status)
+			}
+
+			payment, ok := provider.Payment(event.PaymentID)
+			if !ok || payment.Captured || !payment.Voided {
+				t.Fatalf("payment %+v was not voided", payment)
+			}
+		})
+	}
+}
+
+func TestNewServerRequiresWebhookSecret(t *testing.T) {
+	config := util.Config{
+		TokenSymmetricKey: "12345678901234567890123456789012",
+		PaymentProvider:   "fake",
+	}
+
+	if _, err := NewServer(config, &paymentStore{}); err == nil {
+		t.Fatal("NewServer accepted an empty PAYMENT_WEBHOOK_SECRET")
+	}
+}
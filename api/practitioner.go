@@ -0,0 +1,251 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// practitionerRequest defines the request body for creating or replacing a practitioner service.
+type practitionerRequest struct {
+	Name        string     `json:"name" binding:"required,max=255"`
+	Description string     `json:"description" binding:"required,max=255"`
+	ImageLink   string     `json:"image_link" binding:"omitempty,url,max=255"`
+	IsAvailable *bool      `json:"is_available"`
+	OpensAt     *time.Time `json:"opens_at"`
+	ClosesAt    *time.Time `json:"closes_at"`
+	WorkingDays string     `json:"working_days" binding:"max=255"`
+}
+
+// validate checks the working hours and working days of the request.
+func (req practitionerRequest) validate() error {
+	if (req.OpensAt == nil) != (req.ClosesAt == nil) {
+		return fmt.Errorf("opens_at and closes_at must be set together")
+	}
+
+	if req.OpensAt != nil {
+		hours := util.OpeningHours{OpensAt: *req.OpensAt, ClosesAt: *req.ClosesAt, Valid: true}
+		if err := hours.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := util.ParseWeekdays(req.WorkingDays); err != nil {
+		return fmt.Errorf("invalid working_days: %w", err)
+	}
+	return nil
+}
+
+// isAvailable defaults to true, matching the column default.
+func (req practitionerRequest) isAvailable() sql.NullBool {
+	if req.IsAvailable == nil {
+		return sql.NullBool{Bool: true, Valid: true}
+	}
+	return newNullBool(req.IsAvailable)
+}
+
+// createPractitioner handles the creation of a practitioner service
+// owned by the acting profile.
+// POST /practitioners
+func (server *Server) createPractitioner(ctx *gin.Context) {
+	var req practitionerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.CreatePractitionerParams{
+		Name:        req.Name,
+		Description: req.Description,
+		ImageLink:   newNullString(req.ImageLink),
+		IsAvailable: req.isAvailable(),
+		CreatedBy:   uuid.NullUUID{UUID: currentProfile(ctx).ID, Valid: true},
+		OpensAt:     newNullTime(req.OpensAt),
+		ClosesAt:    newNullTime(req.ClosesAt),
+		WorkingDays: newNullString(req.WorkingDays),
+	}
+
+	practitioner, err := server.store.CreatePractitioner(ctx, arg)
+	if err != nil {
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, practitioner)
+}
+
+// practitionerURI defines the URI parameter for addressing a practitioner by ID.
+type practitionerURI struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// bindPractitionerID parses the practitioner ID from the URI.
+func bindPractitionerID(ctx *gin.Context) (uuid.UUID, bool) {
+	var uri practitionerURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return uuid.UUID{}, false
+	}
+
+	practitionerID, err := uuid.Parse(uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid practitioner ID format: %w", err)))
+		return uuid.UUID{}, false
+	}
+	return practitionerID, true
+}
+
+// loadPractitioner fetches a practitioner, writing a 404 if it does not exist.
+func (server *Server) loadPractitioner(ctx *gin.Context, practitionerID uuid.UUID) (db.Practitioners, bool) {
+	practitioner, err := server.store.GetPractitioner(ctx, practitionerID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("practitioner not found")))
+			return db.Practitioners{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return db.Practitioners{}, false
+	}
+	return practitioner, true
+}
+
+// getPractitioner handles fetching a single practitioner by ID.
+// GET /practitioners/:id
+func (server *Server) getPractitioner(ctx *gin.Context) {
+	practitionerID, ok := bindPractitionerID(ctx)
+	if !ok {
+		return
+	}
+
+	practitioner, ok := server.loadPractitioner(ctx, practitionerID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, practitioner)
+}
+
+var practitionerSorts = []sortOrder{{"name", stringKey}, {"newest", timeKey}}
+
+// listPractitionersRequest defines the paging parameters and filters for listing practitioners.
+type listPractitionersRequest struct {
+	pageRequest
+	IsAvailable *bool `form:"is_available"`
+}
+
+// listPractitioners handles fetching a page of practitioners.
+// GET /practitioners?sort=name|newest&is_available=...
+func (server *Server) listPractitioners(ctx *gin.Context) {
+	var req listPractitionersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.bindPage(ctx, req.pageRequest, practitionerSorts...)
+	if !ok {
+		return
+	}
+
+	practitioners, err := server.store.ListPractitioners(ctx, db.ListPractitionersParams{
+		IsAvailable:     newNullBool(req.IsAvailable),
+		Sort:            p.sort.name,
+		CursorID:        p.cursorID(),
+		CursorName:      p.cursorString(),
+		CursorCreatedAt: p.cursorTime(),
+		PageLimit:       p.fetch(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(p, practitioners, func(practitioner db.Practitioners) (string, uuid.UUID) {
+		if p.sort.name == "newest" {
+			return encodeTime(practitioner.CreatedAt), practitioner.ID
+		}
+		return practitioner.Name, practitioner.ID
+	}))
+}
+
+// updatePractitioner handles replacing a practitioner service.
+// PUT /practitioners/:id
+func (server *Server) updatePractitioner(ctx *gin.Context) {
+	practitionerID, ok := bindPractitionerID(ctx)
+	if !ok {
+		return
+	}
+
+	var req practitionerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	practitioner, ok := server.loadPractitioner(ctx, practitionerID)
+	if !ok || !server.authorizeOwner(ctx, practitioner.CreatedBy) {
+		return
+	}
+
+	arg := db.UpdatePractitionerParams{
+		ID:          practitioner.ID,
+		Name:        req.Name,
+		Description: req.Description,
+		ImageLink:   newNullString(req.ImageLink),
+		IsAvailable: req.isAvailable(),
+		CreatedBy:   practitioner.CreatedBy,
+		OpensAt:     newNullTime(req.OpensAt),
+		ClosesAt:    newNullTime(req.ClosesAt),
+		WorkingDays: newNullString(req.WorkingDays),
+	}
+
+	practitioner, err := server.store.UpdatePractitioner(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("practitioner not found for update")))
+			return
+		}
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, practitioner)
+}
+
+// deletePractitioner handles deleting a practitioner by ID.
+// DELETE /practitioners/:id
+func (server *Server) deletePractitioner(ctx *gin.Context) {
+	practitionerID, ok := bindPractitionerID(ctx)
+	if !ok {
+		return
+	}
+
+	practitioner, ok := server.loadPractitioner(ctx, practitionerID)
+	if !ok || !server.authorizeOwner(ctx, practitioner.CreatedBy) {
+		return
+	}
+
+	err := server.store.DeletePractitioner(ctx, practitioner.ID)
+	if err != nil {
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
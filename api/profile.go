@@ -0,0 +1,565 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+var (
+	errNotProfileMember = errors.New("user is not a member of this profile")
+	errAdminRole        = errors.New("only admins can grant the admin role")
+)
+
+// profileRequest defines the request body for creating or replacing a profile.
+type profileRequest struct {
+	Bio          string   `json:"bio" binding:"max=255"`
+	PhoneNo      string   `json:"phone_no" binding:"max=20"`
+	Country      string   `json:"country" binding:"max=255"`
+	Address      string   `json:"address" binding:"max=255"`
+	Experience   *int32   `json:"experience" binding:"omitempty,min=0"`
+	Field        string   `json:"field" binding:"max=255"`
+	BusinessName string   `json:"business_name" binding:"max=255"`
+	Roles        []string `json:"roles" binding:"required,min=1,dive,oneof=attendee venue_owner practitioner organiser admin"`
+}
+
+// roles converts the requested roles into the profiles.roles format,
+// refusing the admin role unless the caller is already an admin.
+func (server *Server) roles(ctx *gin.Context, req profileRequest) (string, int, error) {
+	roles := util.RoleSet{}
+	for _, role := range req.Roles {
+		roles[util.Role(role)] = struct{}{}
+	}
+
+	if _, ok := roles[util.RoleAdmin]; ok {
+		admin, status, err := server.isAdmin(ctx)
+		if err != nil {
+			return "", status, err
+		}
+		if !admin {
+			return "", http.StatusForbidden, errAdminRole
+		}
+	}
+	return roles.String(), http.StatusOK, nil
+}
+
+// createProfile handles the creation of a profile owned by the authenticated user.
+// POST /profiles
+func (server *Server) createProfile(ctx *gin.Context) {
+	var req profileRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	roles, status, err := server.roles(ctx, req)
+	if err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+
+	arg := db.CreateProfileTxParams{
+		CreateProfileParams: db.CreateProfileParams{
+			ID:           uuid.New(),
+			Bio:          newNullString(req.Bio),
+			PhoneNo:      newNullString(req.PhoneNo),
+			Country:      newNullString(req.Country),
+			Address:      newNullString(req.Address),
+			Experience:   newNullInt32(req.Experience),
+			Field:        newNullString(req.Field),
+			BusinessName: newNullString(req.BusinessName),
+			Roles:        roles,
+		},
+		OwnerID: authPayload(ctx).UserID,
+	}
+
+	result, err := server.store.CreateProfileTx(ctx, arg)
+	if err != nil {
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, result)
+}
+
+// profileURI defines the URI parameter for addressing a profile by ID.
+type profileURI struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// bindProfileID parses the profile ID from the URI.
+func bindProfileID(ctx *gin.Context) (uuid.UUID, bool) {
+	var uri profileURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return uuid.UUID{}, false
+	}
+
+	profileID, err := uuid.Parse(uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid profile ID format: %w", err)))
+		return uuid.UUID{}, false
+	}
+	return profileID, true
+}
+
+// memberRole returns the authenticated user's role in a profile. Admins
+// are treated as owners of every profile.
+func (server *Server) memberRole(ctx *gin.Context, profileID uuid.UUID) (util.MemberRole, int, error) {
+	member, err := server.store.GetProfileMember(ctx, db.GetProfileMemberParams{
+		ProfilesID: profileID,
+		UsersID:    authPayload(ctx).UserID,
+	})
+	if err == nil {
+		return util.MemberRole(member.MemberRole), http.StatusOK, nil
+	}
+	if err != sql.ErrNoRows {
+		return "", http.StatusInternalServerError, err
+	}
+
+	admin, status, err := server.isAdmin(ctx)
+	if err != nil {
+		return "", status, err
+	}
+	if admin {
+		return util.MemberOwner, http.StatusOK, nil
+	}
+	return "", http.StatusForbidden, errNotProfileMember
+}
+
+// getProfile handles fetching a single profile by ID.
+// GET /profiles/:id
+func (server *Server) getProfile(ctx *gin.Context) {
+	profileID, ok := bindProfileID(ctx)
+	if !ok {
+		return
+	}
+
+	profile, err := server.store.GetProfile(ctx, profileID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("profile not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, profile)
+}
+
+var profileSorts = []sortOrder{{"newest", timeKey}}
+
+// listProfilesRequest defines the paging parameters and filters for listing profiles.
+type listProfilesRequest struct {
+	pageRequest
+	Country string `form:"country" binding:"max=255"`
+}
+
+// listProfiles handles fetching a page of profiles, newest first.
+// GET /profiles?country=...
+func (server *Server) listProfiles(ctx *gin.Context) {
+	var req listProfilesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.bindPage(ctx, req.pageRequest, profileSorts...)
+	if !ok {
+		return
+	}
+
+	profiles, err := server.store.ListProfiles(ctx, db.ListProfilesParams{
+		Country:         newNullString(req.Country),
+		CursorID:        p.cursorID(),
+		CursorCreatedAt: p.cursorTime(),
+		PageLimit:       p.fetch(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(p, profiles, func(profile db.Profiles) (string, uuid.UUID) {
+		return encodeTime(profile.CreatedAt), profile.ID
+	}))
+}
+
+// updateProfile handles replacing a profile. Owners and managers may update it.
+// PUT /profiles/:id
+func (server *Server) updateProfile(ctx *gin.Context) {
+	profileID, ok := bindProfileID(ctx)
+	if !ok {
+		return
+	}
+
+	var req profileRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	role, status, err := server.memberRole(ctx, profileID)
+	if err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+	if !role.CanManage() {
+		ctx.JSON(http.StatusForbidden, errorResponse(fmt.Errorf("%s members cannot update the profile", role)))
+		return
+	}
+
+	roles, status, err := server.roles(ctx, req)
+	if err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+
+	arg := db.UpdateProfileParams{
+		ID:           profileID,
+		Bio:          newNullString(req.Bio),
+		PhoneNo:      newNullString(req.PhoneNo),
+		Country:      newNullString(req.Country),
+		Address:      newNullString(req.Address),
+		Experience:   newNullInt32(req.Experience),
+		Field:        newNullString(req.Field),
+		BusinessName: newNullString(req.BusinessName),
+		Roles:        roles,
+	}
+
+	profile, err := server.store.UpdateProfile(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("profile not found for update")))
+			return
+		}
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, profile)
+}
+
+// deleteProfile handles deleting a profile. Only owners may delete it.
+// DELETE /profiles/:id
+func (server *Server) deleteProfile(ctx *gin.Context) {
+	profileID, ok := bindProfileID(ctx)
+	if !ok {
+		return
+	}
+
+	role, status, err := server.memberRole(ctx, profileID)
+	if err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+	if role != util.MemberOwner {
+		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("only owners can delete the profile")))
+		return
+	}
+
+	err = server.store.DeleteProfileTx(ctx, profileID)
+	if err != nil {
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// myProfileResponse is a profile together with the user's role in it.
+type myProfileResponse struct {
+	db.Profiles
+	MemberRole string `json:"member_role"`
+}
+
+var membershipSorts = []sortOrder{{"oldest", timeKey}}
+
+// listMembershipsRequest defines the paging parameters for listing profile memberships.
+type listMembershipsRequest struct {
+	pageRequest
+}
+
+// listMyProfiles lists every profile the authenticated user belongs to.
+// GET /me/profiles
+func (server *Server) listMyProfiles(ctx *gin.Context) {
+	var req listMembershipsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.bindPage(ctx, req.pageRequest, membershipSorts...)
+	if !ok {
+		return
+	}
+
+	rows, err := server.store.ListProfileMembershipsByUser(ctx, db.ListProfileMembershipsByUserParams{
+		UsersID:         authPayload(ctx).UserID,
+		CursorID:        p.cursorID(),
+		CursorCreatedAt: p.cursorTime(),
+		PageLimit:       p.fetch(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]myProfileResponse, 0, len(rows))
+	for _, row := range rows {
+		rsp = append(rsp, myProfileResponse{Profiles: row.Profiles, MemberRole: row.MemberRole})
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(p, rsp, func(profile myProfileResponse) (string, uuid.UUID) {
+		return encodeTime(profile.CreatedAt), profile.ID
+	}))
+}
+
+// listProfileMembers lists the users of a profile. Only members may see them.
+// GET /profiles/:id/members
+func (server *Server) listProfileMembers(ctx *gin.Context) {
+	profileID, ok := bindProfileID(ctx)
+	if !ok {
+		return
+	}
+
+	var req listMembershipsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.bindPage(ctx, req.pageRequest, membershipSorts...)
+	if !ok {
+		return
+	}
+
+	if _, status, err := server.memberRole(ctx, profileID); err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+
+	members, err := server.store.ListProfileMembers(ctx, db.ListProfileMembersParams{
+		ProfilesID:      profileID,
+		CursorID:        p.cursorID(),
+		CursorCreatedAt: p.cursorTime(),
+		PageLimit:       p.fetch(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(p, members, func(member db.ListProfileMembersRow) (string, uuid.UUID) {
+		return encodeTime(member.CreatedAt), member.UsersID
+	}))
+}
+
+// addProfileMemberRequest defines the request body for linking a user to a profile.
+type addProfileMemberRequest struct {
+	Email      string `json:"email" binding:"required,email"`
+	MemberRole string `json:"member_role" binding:"required,oneof=owner manager staff"`
+}
+
+// addProfileMember links an existing user to a profile. Managers may add
+// staff; owners may add members with any role.
+// POST /profiles/:id/members
+func (server *Server) addProfileMember(ctx *gin.Context) {
+	profileID, ok := bindProfileID(ctx)
+	if !ok {
+		return
+	}
+
+	var req addProfileMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	role, status, err := server.memberRole(ctx, profileID)
+	if err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+	newRole := util.MemberRole(req.MemberRole)
+	if !role.CanManage() || (role != util.MemberOwner && newRole != util.MemberStaff) {
+		ctx.JSON(http.StatusForbidden, errorResponse(fmt.Errorf("%s members cannot add %s members", role, newRole)))
+		return
+	}
+
+	user, err := server.store.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("user not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	member, err := server.store.LinkUserToProfile(ctx, db.LinkUserToProfileParams{
+		ProfilesID: profileID,
+		UsersID:    user.ID,
+		MemberRole: string(newRole),
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			ctx.JSON(http.StatusConflict, errorResponse(errors.New("user is already a member of this profile")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, member)
+}
+
+// profileMemberURI defines the URI parameters for addressing a profile member.
+type profileMemberURI struct {
+	ID     string `uri:"id" binding:"required,uuid"`
+	UserID string `uri:"user_id" binding:"required,uuid"`
+}
+
+// bindProfileMember parses the profile and user IDs from the URI.
+func bindProfileMember(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	var uri profileMemberURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return uuid.UUID{}, uuid.UUID{}, false
+	}
+
+	profileID, err := uuid.Parse(uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid profile ID format: %w", err)))
+		return uuid.UUID{}, uuid.UUID{}, false
+	}
+
+	userID, err := uuid.Parse(uri.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid user ID format: %w", err)))
+		return uuid.UUID{}, uuid.UUID{}, false
+	}
+	return profileID, userID, true
+}
+
+// loadProfileMember fetches a membership, writing a 404 if it does not exist.
+func (server *Server) loadProfileMember(ctx *gin.Context, profileID, userID uuid.UUID) (db.ProfilesUsers, bool) {
+	member, err := server.store.GetProfileMember(ctx, db.GetProfileMemberParams{
+		ProfilesID: profileID,
+		UsersID:    userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("member not found")))
+			return db.ProfilesUsers{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return db.ProfilesUsers{}, false
+	}
+	return member, true
+}
+
+// updateProfileMemberRequest defines the request body for changing a member's role.
+type updateProfileMemberRequest struct {
+	MemberRole string `json:"member_role" binding:"required,oneof=owner manager staff"`
+}
+
+// updateProfileMember changes a member's role. Only owners may do this.
+// PUT /profiles/:id/members/:user_id
+func (server *Server) updateProfileMember(ctx *gin.Context) {
+	profileID, userID, ok := bindProfileMember(ctx)
+	if !ok {
+		return
+	}
+
+	var req updateProfileMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	role, status, err := server.memberRole(ctx, profileID)
+	if err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+	if role != util.MemberOwner {
+		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("only owners can change member roles")))
+		return
+	}
+
+	member, err := server.store.UpdateProfileMemberRoleTx(ctx, db.UpdateProfileMemberRoleParams{
+		ProfilesID: profileID,
+		UsersID:    userID,
+		MemberRole: req.MemberRole,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("member not found")))
+			return
+		}
+		if errors.Is(err, db.ErrLastOwner) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, member)
+}
+
+// removeProfileMember unlinks a user from a profile. Members may remove
+// themselves, managers may remove staff and owners may remove anyone.
+// DELETE /profiles/:id/members/:user_id
+func (server *Server) removeProfileMember(ctx *gin.Context) {
+	profileID, userID, ok := bindProfileMember(ctx)
+	if !ok {
+		return
+	}
+
+	role, status, err := server.memberRole(ctx, profileID)
+	if err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+
+	member, ok := server.loadProfileMember(ctx, profileID, userID)
+	if !ok {
+		return
+	}
+
+	self := userID == authPayload(ctx).UserID
+	allowed := self || role == util.MemberOwner ||
+		(role == util.MemberManager && util.MemberRole(member.MemberRole) == util.MemberStaff)
+	if !allowed {
+		ctx.JSON(http.StatusForbidden, errorResponse(fmt.Errorf("%s members cannot remove %s members", role, member.MemberRole)))
+		return
+	}
+
+	err = server.store.RemoveProfileMemberTx(ctx, db.UnlinkUserFromProfileParams{
+		ProfilesID: profileID,
+		UsersID:    userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("member not found")))
+			return
+		}
+		if errors.Is(err, db.ErrLastOwner) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+)
+
+// purchaseURI defines the URI parameter for addressing a purchase by ID.
+type purchaseURI struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// cancelPurchase cancels an event ticket purchase and refunds it according
+// to the event's cancellation policy. The freed place goes to the next
+// profile on the event's waitlist.
+// POST /purchases/:id/cancel
+func (server *Server) cancelPurchase(ctx *gin.Context) {
+	var uri purchaseURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	purchaseID, err := uuid.Parse(uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid purchase ID format: %w", err)))
+		return
+	}
+
+	req, ok := bindCancelRequest(ctx)
+	if !ok {
+		return
+	}
+
+	purchase, err := server.store.GetPurchase(ctx, purchaseID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("purchase not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if !purchase.EventID.Valid {
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(db.ErrNotTicketPurchase))
+		return
+	}
+
+	event, ok := server.loadEvent(ctx, purchase.EventID.UUID)
+	if !ok {
+		return
+	}
+
+	fullRefund, ok := server.cancellingParty(ctx, purchase.PurchasedBy.UUID, event.CreatedBy)
+	if !ok {
+		return
+	}
+
+	result, err := server.store.CancelPurchaseTx(ctx, db.CancelTxParams{
+		ID:          purchase.ID,
+		CancelledBy: currentProfile(ctx).ID,
+		Reason:      req.Reason,
+		FullRefund:  fullRefund,
+		Now:         time.Now(),
+		HoldFor:     server.config.WaitlistHold,
+	})
+	if err != nil {
+		ctx.JSON(cancelErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	if !server.issueRefund(ctx, &result) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+var purchaseSorts = []sortOrder{{"newest", timeKey}}
+
+// listPurchasesRequest defines the paging parameters for listing purchases.
+type listPurchasesRequest struct {
+	pageRequest
+}
+
+// listMyPurchases lists the purchases of the acting profile, newest first.
+// GET /me/purchases
+func (server *Server) listMyPurchases(ctx *gin.Context) {
+	var req listPurchasesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.bindPage(ctx, req.pageRequest, purchaseSorts...)
+	if !ok {
+		return
+	}
+
+	purchases, err := server.store.ListPurchasesByUser(ctx, db.ListPurchasesByUserParams{
+		PurchasedBy:     uuid.NullUUID{UUID: currentProfile(ctx).ID, Valid: true},
+		CursorID:        p.cursorID(),
+		CursorCreatedAt: p.cursorTime(),
+		PageLimit:       p.fetch(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, ok := server.newPurchaseResponses(ctx, purchases...)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(p, rsp, func(purchase purchaseResponse) (string, uuid.UUID) {
+		return encodeTime(purchase.CreatedAt), purchase.ID
+	}))
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+)
+
+// Types of search results.
+const (
+	resultVenue        = "venue"
+	resultPractitioner = "practitioner"
+	resultEvent        = "event"
+)
+
+var searchSorts = []sortOrder{{"relevance", floatKey}}
+
+// searchRequest defines the query parameters of a full-text search. The
+// query uses web search syntax: quoted phrases, "or" and -excluded words.
+type searchRequest struct {
+	pageRequest
+	Query string `form:"q" binding:"required,max=255"`
+	Type  string `form:"type" binding:"omitempty,oneof=venue practitioner event"`
+}
+
+// searchResult is one match of a full-text search. Snippet is an excerpt of
+// the matching text with the search terms wrapped in <b> tags.
+type searchResult struct {
+	Type    string    `json:"type"`
+	ID      uuid.UUID `json:"id"`
+	Name    string    `json:"name"`
+	Snippet string    `json:"snippet"`
+	Rank    float32   `json:"rank"`
+}
+
+// search handles a full-text search across venues, practitioners and
+// published events, returning the best matches of every type in rank order.
+// GET /search?q=...&type=venue|practitioner|event
+func (server *Server) search(ctx *gin.Context) {
+	var req searchRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.bindPage(ctx, req.pageRequest, searchSorts...)
+	if !ok {
+		return
+	}
+
+	var results []searchResult
+
+	if req.Type == "" || req.Type == resultVenue {
+		rows, err := server.store.SearchVenuesText(ctx, db.SearchVenuesTextParams{
+			Query:      req.Query,
+			CursorID:   p.cursorID(),
+			CursorRank: p.cursorFloat(),
+			PageLimit:  p.fetch(),
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		for _, row := range rows {
+			results = append(results, searchResult{Type: resultVenue, ID: row.ID, Name: row.Name, Snippet: row.Snippet, Rank: row.Rank})
+		}
+	}
+
+	if req.Type == "" || req.Type == resultPractitioner {
+		rows, err := server.store.SearchPractitionersText(ctx, db.SearchPractitionersTextParams{
+			Query:      req.Query,
+			CursorID:   p.cursorID(),
+			CursorRank: p.cursorFloat(),
+			PageLimit:  p.fetch(),
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		for _, row := range rows {
+			results = append(results, searchResult{Type: resultPractitioner, ID: row.ID, Name: row.Name, Snippet: row.Snippet, Rank: row.Rank})
+		}
+	}
+
+	if req.Type == "" || req.Type == resultEvent {
+		rows, err := server.store.SearchEventsText(ctx, db.SearchEventsTextParams{
+			Query:      req.Query,
+			CursorID:   p.cursorID(),
+			CursorRank: p.cursorFloat(),
+			PageLimit:  p.fetch(),
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		for _, row := range rows {
+			results = append(results, searchResult{Type: resultEvent, ID: row.ID, Name: row.Name, Snippet: row.Snippet, Rank: row.Rank})
+		}
+	}
+
+	// Each query returns its own best matches after the cursor in
+	// (rank, id) descending order, so merging them in the same order and
+	// keeping the first page-worth yields the best matches overall.
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return bytes.Compare(results[i].ID[:], results[j].ID[:]) > 0
+	})
+	if int32(len(results)) > p.fetch() {
+		results = results[:p.fetch()]
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(p, results, func(result searchResult) (string, uuid.UUID) {
+		return encodeFloat(float64(result.Rank)), result.ID
+	}))
+}
@@ -1,30 +1,148 @@
 package api
 
 import (
+	"fmt"
+
 	"github.com/gin-gonic/gin"
 	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/geo"
+	"github.com/tedobanks/tabularasa_backend/payments"
+	"github.com/tedobanks/tabularasa_backend/tax"
+	"github.com/tedobanks/tabularasa_backend/token"
+	"github.com/tedobanks/tabularasa_backend/util"
 )
 
 // Server serves HTTP requests for our application.
 type Server struct {
-	store  *db.Queries // Use *db.Queries directly since your sqlc generated db.go provides it
-	router *gin.Engine
+	config     util.Config
+	store      db.Store
+	tokenMaker token.Maker
+	geocoder   geo.Geocoder
+	payments   payments.Provider
+	taxRules   *tax.Rules
+	router     *gin.Engine
 }
 
 // NewServer creates a new HTTP server and sets up routing.
-func NewServer(store *db.Queries) *Server {
-	server := &Server{store: store}
+func NewServer(config util.Config, store db.Store) (*Server, error) {
+	tokenMaker, err := token.NewMaker(config.TokenType, config.TokenSymmetricKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create token maker: %w", err)
+	}
+
+	var geocoder geo.Geocoder = geo.NopGeocoder{}
+	if config.GeocoderFixtures != "" {
+		geocoder, err = geo.LoadFixtureGeocoder(config.GeocoderFixtures)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create geocoder: %w", err)
+		}
+	}
+
+	provider, err := newPaymentProvider(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create payment provider: %w", err)
+	}
+
+	var taxRules *tax.Rules
+	if config.TaxRules != "" {
+		taxRules, err = tax.LoadRules(config.TaxRules)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load tax rules: %w", err)
+		}
+	}
+
+	server := &Server{
+		config:     config,
+		store:      store,
+		tokenMaker: tokenMaker,
+		geocoder:   geocoder,
+		payments:   provider,
+		taxRules:   taxRules,
+	}
+
+	server.setupRouter()
+	return server, nil
+}
+
+func (server *Server) setupRouter() {
 	router := gin.Default()
 
-	// Register your API routes here
-	// Example:
+	// Public routes
 	router.POST("/user", server.createUser)
+	router.POST("/users/login", server.loginUser)
+	router.POST("/tokens/renew_access", server.renewAccessToken)
 	router.GET("/users", server.listUsers)
 	router.GET("/user/:id", server.getUser)
-	router.DELETE("/user/:id", server.deleteUser)
+	router.GET("/search", server.search)
+	router.GET("/venues", server.listVenues)
+	router.GET("/venues/search", server.searchVenues)
+	router.GET("/venues/nearby", server.listVenuesNearby)
+	router.GET("/venues/:id", server.getVenue)
+	router.GET("/venues/:id/availability", server.getVenueAvailability)
+	router.GET("/practitioners", server.listPractitioners)
+	router.GET("/practitioners/:id", server.getPractitioner)
+	router.GET("/practitioners/:id/availability", server.getPractitionerAvailability)
+	router.GET("/profiles", server.listProfiles)
+	router.GET("/profiles/:id", server.getProfile)
+	router.GET("/events", server.listEvents)
+	router.GET("/events/:id", server.getEvent)
+	router.GET("/events/:id/tiers", server.listTicketTiers)
+	router.POST("/webhooks/payments", server.handlePaymentWebhook)
+
+	// Routes that change state require a valid access token
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
+	authRoutes.POST("/users/logout", server.logoutUser)
+	authRoutes.DELETE("/user/:id", server.deleteUser)
+
+	authRoutes.POST("/venues", server.RequireRole(util.RoleVenueOwner), server.createVenue)
+	authRoutes.PUT("/venues/:id", server.updateVenue)
+	authRoutes.PATCH("/venues/:id", server.patchVenue)
+	authRoutes.DELETE("/venues/:id", server.deleteVenue)
+	authRoutes.PUT("/venues/:id/cancellation-policy", server.updateVenueCancellationPolicy)
+	authRoutes.POST("/venues/:id/bookings", server.RequireRole(util.RoleAttendee, util.RoleOrganiser), server.bookVenue)
+	authRoutes.GET("/venues/:id/bookings", server.listVenueBookings)
+	authRoutes.POST("/venues/:id/waitlist", server.RequireRole(util.RoleAttendee, util.RoleOrganiser), server.joinVenueWaitlist)
+	authRoutes.GET("/venues/:id/waitlist", server.listVenueWaitlist)
+	authRoutes.DELETE("/venues/:id/waitlist", server.RequireRole(util.RoleAttendee, util.RoleOrganiser), server.leaveVenueWaitlist)
+
+	authRoutes.POST("/practitioners", server.RequireRole(util.RolePractitioner), server.createPractitioner)
+	authRoutes.PUT("/practitioners/:id", server.updatePractitioner)
+	authRoutes.DELETE("/practitioners/:id", server.deletePractitioner)
+	authRoutes.PUT("/practitioners/:id/cancellation-policy", server.updatePractitionerCancellationPolicy)
+	authRoutes.POST("/practitioners/:id/bookings", server.RequireRole(util.RoleAttendee, util.RoleOrganiser), server.bookPractitioner)
+	authRoutes.GET("/practitioners/:id/bookings", server.listPractitionerBookings)
+
+	authRoutes.POST("/events", server.RequireRole(util.RoleOrganiser), server.createEvent)
+	authRoutes.PUT("/events/:id", server.updateEvent)
+	authRoutes.PUT("/events/:id/status", server.updateEventStatus)
+	authRoutes.DELETE("/events/:id", server.deleteEvent)
+	authRoutes.PUT("/events/:id/cancellation-policy", server.updateEventCancellationPolicy)
+	authRoutes.POST("/events/:id/tiers", server.createTicketTier)
+	authRoutes.POST("/events/:id/tickets", server.RequireRole(util.RoleAttendee, util.RoleOrganiser), server.purchaseTickets)
+	authRoutes.POST("/events/:id/waitlist", server.RequireRole(util.RoleAttendee, util.RoleOrganiser), server.joinEventWaitlist)
+	authRoutes.GET("/events/:id/waitlist", server.listEventWaitlist)
+	authRoutes.DELETE("/events/:id/waitlist", server.RequireRole(util.RoleAttendee, util.RoleOrganiser), server.leaveEventWaitlist)
+	authRoutes.PUT("/events/:id/favourite", server.RequireRole(util.AllRoles...), server.addFavourite)
+	authRoutes.DELETE("/events/:id/favourite", server.RequireRole(util.AllRoles...), server.removeFavourite)
+
+	authRoutes.POST("/profiles", server.createProfile)
+	authRoutes.PUT("/profiles/:id", server.updateProfile)
+	authRoutes.DELETE("/profiles/:id", server.deleteProfile)
+	authRoutes.GET("/profiles/:id/members", server.listProfileMembers)
+	authRoutes.POST("/profiles/:id/members", server.addProfileMember)
+	authRoutes.PUT("/profiles/:id/members/:user_id", server.updateProfileMember)
+	authRoutes.DELETE("/profiles/:id/members/:user_id", server.removeProfileMember)
+
+	authRoutes.POST("/bookings/:id/cancel", server.RequireRole(util.AllRoles...), server.cancelBooking)
+	authRoutes.POST("/purchases/:id/cancel", server.RequireRole(util.AllRoles...), server.cancelPurchase)
+	authRoutes.GET("/purchases/:id/invoice.pdf", server.RequireRole(util.AllRoles...), server.getPurchaseInvoice)
+	authRoutes.GET("/purchases/:id/receipt.pdf", server.RequireRole(util.AllRoles...), server.getPurchaseReceipt)
+	authRoutes.GET("/me/profiles", server.listMyProfiles)
+	authRoutes.GET("/me/purchases", server.RequireRole(util.AllRoles...), server.listMyPurchases)
+	authRoutes.GET("/me/notifications", server.RequireRole(util.AllRoles...), server.listNotifications)
+	authRoutes.GET("/me/favourites", server.RequireRole(util.AllRoles...), server.listMyFavourites)
 
 	server.router = router
-	return server
 }
 
 // Start runs the HTTP server on a specific address.
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/money"
+	"github.com/tedobanks/tabularasa_backend/payments"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// createTicketTierRequest defines the request body for adding a ticket tier to an event.
+type createTicketTierRequest struct {
+	Name     string `json:"name" binding:"required,max=255"`
+	Price    int32  `json:"price" binding:"min=0"`
+	Quantity int32  `json:"quantity" binding:"required,min=1"`
+	Currency string `json:"currency" binding:"omitempty,len=3"`
+}
+
+// createTicketTier handles adding a ticket tier to an event.
+// POST /events/:id/tiers
+func (server *Server) createTicketTier(ctx *gin.Context) {
+	eventID, ok := bindEventID(ctx)
+	if !ok {
+		return
+	}
+
+	var req createTicketTierRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	currency, err := money.ParseCurrency(server.priceCurrency(req.Currency))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	event, ok := server.loadEvent(ctx, eventID)
+	if !ok || !server.authorizeOwner(ctx, event.CreatedBy) {
+		return
+	}
+
+	if !util.EventStatus(event.Status).IsEditable() {
+		ctx.JSON(http.StatusConflict, errorResponse(fmt.Errorf("cannot add tiers to a %s event", event.Status)))
+		return
+	}
+
+	tier, err := server.store.CreateTicketTier(ctx, db.CreateTicketTierParams{
+		EventID:  event.ID,
+		Name:     req.Name,
+		Price:    req.Price,
+		Quantity: req.Quantity,
+		Currency: string(currency),
+	})
+	if err != nil {
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, tier)
+}
+
+var ticketTierSorts = []sortOrder{{"price", intKey}}
+
+// listTicketTiersRequest defines the paging parameters for listing ticket tiers.
+type listTicketTiersRequest struct {
+	pageRequest
+}
+
+// listTicketTiers handles fetching the ticket tiers of an event, cheapest first.
+// GET /events/:id/tiers
+func (server *Server) listTicketTiers(ctx *gin.Context) {
+	eventID, ok := bindEventID(ctx)
+	if !ok {
+		return
+	}
+
+	var req listTicketTiersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.bindPage(ctx, req.pageRequest, ticketTierSorts...)
+	if !ok {
+		return
+	}
+
+	tiers, err := server.store.ListTicketTiersByEvent(ctx, db.ListTicketTiersByEventParams{
+		EventID:     eventID,
+		CursorID:    p.cursorID(),
+		CursorPrice: p.cursorInt(),
+		PageLimit:   p.fetch(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, ok := server.newTicketTierResponses(ctx, tiers...)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(p, rsp, func(tier ticketTierResponse) (string, uuid.UUID) {
+		return encodeInt(tier.Price), tier.ID
+	}))
+}
+
+// purchaseTicketsRequest defines the request body for buying tickets.
+type purchaseTicketsRequest struct {
+	TierID   string `json:"tier_id" binding:"required,uuid"`
+	Quantity int    `json:"quantity" binding:"required,min=1,max=20"`
+}
+
+// purchaseTicketsResponse is a ticket sale together with the checkout paying for it.
+type purchaseTicketsResponse struct {
+	db.PurchaseTicketsTxResult
+	Checkout *payments.Checkout `json:"checkout,omitempty"`
+}
+
+// purchaseTickets handles buying tickets of one tier for the acting profile.
+// POST /events/:id/tickets
+func (server *Server) purchaseTickets(ctx *gin.Context) {
+	eventID, ok := bindEventID(ctx)
+	if !ok {
+		return
+	}
+
+	var req purchaseTicketsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	tierID, err := uuid.Parse(req.TierID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid tier ID format: %w", err)))
+		return
+	}
+
+	arg := db.PurchaseTicketsTxParams{
+		EventID:     eventID,
+		TierID:      tierID,
+		PurchasedBy: currentProfile(ctx).ID,
+		Quantity:    req.Quantity,
+		TaxRules:    server.taxRules,
+		PendingTTL:  server.config.PendingPurchaseTTL,
+		HoldFor:     server.config.WaitlistHold,
+	}
+
+	result, err := server.store.PurchaseTicketsTx(ctx, arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("event or ticket tier not found")))
+		case errors.Is(err, db.ErrEventSoldOut),
+			errors.Is(err, db.ErrTierSoldOut):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		case errors.Is(err, db.ErrEventNotOnSale),
+			errors.Is(err, db.ErrTierNotInEvent):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	purchases := make([]*db.Purchases, len(result.Tickets))
+	for i := range result.Tickets {
+		purchases[i] = &result.Tickets[i].Purchase
+	}
+
+	description := fmt.Sprintf("%d x %s for %s", req.Quantity, result.Tier.Name, result.Event.Name.String)
+	checkout, ok := server.startCheckout(ctx, description, purchases...)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, purchaseTicketsResponse{PurchaseTicketsTxResult: result, Checkout: checkout})
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/token"
+)
+
+// noStore fails the test on any store call, for requests that must be
+// rejected before reaching the database.
+type noStore struct {
+	db.Store
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	server := newTestServer(t, noStore{})
+	userID := uuid.New()
+
+	newToken := func(t *testing.T, purpose token.Purpose) string {
+		tokenString, _, err := server.tokenMaker.CreateToken(userID, purpose, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tokenString
+	}
+
+	testCases := []struct {
+		name     string
+		header   func(t *testing.T) string
+		wantCode int
+	}{
+		{
+			name:     "access token",
+			header:   func(t *testing.T) string { return "Bearer " + newToken(t, token.PurposeAccess) },
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "refresh token",
+			header:   func(t *testing.T) string { return "Bearer " + newToken(t, token.PurposeRefresh) },
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "no header",
+			header:   func(t *testing.T) string { return "" },
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "unsupported type",
+			header:   func(t *testing.T) string { return "Basic " + newToken(t, token.PurposeAccess) },
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/auth", authMiddleware(server.tokenMaker), func(ctx *gin.Context) {
+				if authPayload(ctx).UserID != userID {
+					t.Errorf("payload user = %s, want %s", authPayload(ctx).UserID, userID)
+				}
+				ctx.Status(http.StatusOK)
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/auth", nil)
+			if header := tc.header(t); header != "" {
+				request.Header.Set(authorizationHeaderKey, header)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tc.wantCode {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tc.wantCode, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestRenewAccessTokenRejectsAccessToken(t *testing.T) {
+	server := newTestServer(t, noStore{})
+
+	accessToken, _, err := server.tokenMaker.CreateToken(uuid.New(), token.PurposeAccess, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := json.Marshal(renewAccessTokenRequest{RefreshToken: accessToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "/tokens/renew_access", bytes.NewReader(body))
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusUnauthorized, recorder.Body.String())
+	}
+}
@@ -0,0 +1,519 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/geo"
+	"github.com/tedobanks/tabularasa_backend/money"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// venueRequest defines the request body for creating or replacing a venue.
+type venueRequest struct {
+	ImageLinks      []string   `json:"image_links" binding:"omitempty,dive,url"`
+	Name            string     `json:"name" binding:"required,max=255"`
+	Type            string     `json:"type" binding:"max=255"`
+	Description     string     `json:"description" binding:"max=255"`
+	Location        string     `json:"location" binding:"required,max=255"`
+	Dimension       string     `json:"dimension" binding:"max=255"`
+	Capacity        *int32     `json:"capacity" binding:"omitempty,min=0"`
+	Facilities      []string   `json:"facilities" binding:"omitempty,dive,required,max=255"`
+	HasAccomodation bool       `json:"has_accomodation"`
+	RoomType        string     `json:"room_type" binding:"max=255"`
+	NoOfRooms       *int32     `json:"no_of_rooms" binding:"omitempty,min=0"`
+	Sleeps          string     `json:"sleeps" binding:"max=255"`
+	BedType         string     `json:"bed_type" binding:"max=255"`
+	Rent            *int32     `json:"rent" binding:"omitempty,min=0"`
+	IsAvailable     bool       `json:"is_available"`
+	OpensAt         *time.Time `json:"opens_at"`
+	ClosesAt        *time.Time `json:"closes_at"`
+	RentalDays      string     `json:"rental_days" binding:"max=255"`
+	BookingPrice    *int32     `json:"booking_price" binding:"omitempty,min=0"`
+	Currency        string     `json:"currency" binding:"omitempty,len=3"` // of rent and booking_price, defaults to CURRENCY
+	Latitude        *float64   `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude       *float64   `json:"longitude" binding:"omitempty,min=-180,max=180"`
+}
+
+// apply copies every field of the request onto the venue.
+func (req venueRequest) apply(venue *db.Venues) {
+	venue.ImageLinks = req.ImageLinks
+	venue.Name = req.Name
+	venue.Type = newNullString(req.Type)
+	venue.Description = newNullString(req.Description)
+	venue.Location = req.Location
+	venue.Dimension = newNullString(req.Dimension)
+	venue.Capacity = newNullInt32(req.Capacity)
+	venue.Facilities = req.Facilities
+	venue.HasAccomodation = newNullBool(&req.HasAccomodation)
+	venue.RoomType = newNullString(req.RoomType)
+	venue.NoOfRooms = newNullInt32(req.NoOfRooms)
+	venue.Sleeps = newNullString(req.Sleeps)
+	venue.BedType = newNullString(req.BedType)
+	venue.Rent = newNullInt32(req.Rent)
+	venue.IsAvailable = newNullBool(&req.IsAvailable)
+	venue.OpensAt = newNullTime(req.OpensAt)
+	venue.ClosesAt = newNullTime(req.ClosesAt)
+	venue.RentalDays = newNullString(req.RentalDays)
+	venue.BookingPrice = newNullInt32(req.BookingPrice)
+	venue.Currency = req.Currency
+	venue.Latitude = newNullFloat64(req.Latitude)
+	venue.Longitude = newNullFloat64(req.Longitude)
+}
+
+// patchVenueRequest defines the request body for partially updating a venue.
+// Only the fields present in the body are changed.
+type patchVenueRequest struct {
+	ImageLinks      *[]string  `json:"image_links" binding:"omitempty,dive,url"`
+	Name            *string    `json:"name" binding:"omitempty,min=1,max=255"`
+	Type            *string    `json:"type" binding:"omitempty,max=255"`
+	Description     *string    `json:"description" binding:"omitempty,max=255"`
+	Location        *string    `json:"location" binding:"omitempty,min=1,max=255"`
+	Dimension       *string    `json:"dimension" binding:"omitempty,max=255"`
+	Capacity        *int32     `json:"capacity" binding:"omitempty,min=0"`
+	Facilities      *[]string  `json:"facilities" binding:"omitempty,dive,required,max=255"`
+	HasAccomodation *bool      `json:"has_accomodation"`
+	RoomType        *string    `json:"room_type" binding:"omitempty,max=255"`
+	NoOfRooms       *int32     `json:"no_of_rooms" binding:"omitempty,min=0"`
+	Sleeps          *string    `json:"sleeps" binding:"omitempty,max=255"`
+	BedType         *string    `json:"bed_type" binding:"omitempty,max=255"`
+	Rent            *int32     `json:"rent" binding:"omitempty,min=0"`
+	IsAvailable     *bool      `json:"is_available"`
+	OpensAt         *time.Time `json:"opens_at"`
+	ClosesAt        *time.Time `json:"closes_at"`
+	RentalDays      *string    `json:"rental_days" binding:"omitempty,max=255"`
+	BookingPrice    *int32     `json:"booking_price" binding:"omitempty,min=0"`
+	Currency        *string    `json:"currency" binding:"omitempty,len=3"`
+	Latitude        *float64   `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude       *float64   `json:"longitude" binding:"omitempty,min=-180,max=180"`
+}
+
+// apply copies the fields present in the request onto the venue.
+func (req patchVenueRequest) apply(venue *db.Venues) {
+	if req.ImageLinks != nil {
+		venue.ImageLinks = *req.ImageLinks
+	}
+	if req.Name != nil {
+		venue.Name = *req.Name
+	}
+	if req.Type != nil {
+		venue.Type = newNullString(*req.Type)
+	}
+	if req.Description != nil {
+		venue.Description = newNullString(*req.Description)
+	}
+	if req.Location != nil {
+		venue.Location = *req.Location
+	}
+	if req.Dimension != nil {
+		venue.Dimension = newNullString(*req.Dimension)
+	}
+	if req.Capacity != nil {
+		venue.Capacity = newNullInt32(req.Capacity)
+	}
+	if req.Facilities != nil {
+		venue.Facilities = *req.Facilities
+	}
+	if req.HasAccomodation != nil {
+		venue.HasAccomodation = newNullBool(req.HasAccomodation)
+	}
+	if req.RoomType != nil {
+		venue.RoomType = newNullString(*req.RoomType)
+	}
+	if req.NoOfRooms != nil {
+		venue.NoOfRooms = newNullInt32(req.NoOfRooms)
+	}
+	if req.Sleeps != nil {
+		venue.Sleeps = newNullString(*req.Sleeps)
+	}
+	if req.BedType != nil {
+		venue.BedType = newNullString(*req.BedType)
+	}
+	if req.Rent != nil {
+		venue.Rent = newNullInt32(req.Rent)
+	}
+	if req.IsAvailable != nil {
+		venue.IsAvailable = newNullBool(req.IsAvailable)
+	}
+	if req.OpensAt != nil {
+		venue.OpensAt = newNullTime(req.OpensAt)
+	}
+	if req.ClosesAt != nil {
+		venue.ClosesAt = newNullTime(req.ClosesAt)
+	}
+	if req.RentalDays != nil {
+		venue.RentalDays = newNullString(*req.RentalDays)
+	}
+	if req.BookingPrice != nil {
+		venue.BookingPrice = newNullInt32(req.BookingPrice)
+	}
+	if req.Currency != nil {
+		venue.Currency = *req.Currency
+	}
+	if req.Latitude != nil {
+		venue.Latitude = newNullFloat64(req.Latitude)
+	}
+	if req.Longitude != nil {
+		venue.Longitude = newNullFloat64(req.Longitude)
+	}
+}
+
+// validateVenue checks the fields that cannot be expressed as binding tags
+// and normalises the currency code.
+func validateVenue(venue *db.Venues) error {
+	currency, err := money.ParseCurrency(venue.Currency)
+	if err != nil {
+		return err
+	}
+	venue.Currency = string(currency)
+
+	if venue.OpensAt.Valid != venue.ClosesAt.Valid {
+		return fmt.Errorf("opens_at and closes_at must be set together")
+	}
+
+	hours := util.OpeningHours{
+		OpensAt:  venue.OpensAt.Time,
+		ClosesAt: venue.ClosesAt.Time,
+		Valid:    venue.OpensAt.Valid,
+	}
+	if err := hours.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := util.ParseWeekdays(venue.RentalDays.String); err != nil {
+		return fmt.Errorf("invalid rental_days: %w", err)
+	}
+
+	if venue.Latitude.Valid != venue.Longitude.Valid {
+		return fmt.Errorf("latitude and longitude must be set together")
+	}
+	return nil
+}
+
+// geocodeVenue sets a venue's coordinates from its location. A location the
+// geocoder cannot find leaves the venue without coordinates.
+func (server *Server) geocodeVenue(ctx *gin.Context, venue *db.Venues) bool {
+	point, err := server.geocoder.Geocode(ctx, venue.Location)
+	if err != nil {
+		if errors.Is(err, geo.ErrNotFound) {
+			venue.Latitude = sql.NullFloat64{}
+			venue.Longitude = sql.NullFloat64{}
+			return true
+		}
+		ctx.JSON(http.StatusBadGateway, errorResponse(err))
+		return false
+	}
+
+	venue.Latitude = sql.NullFloat64{Float64: point.Lat, Valid: true}
+	venue.Longitude = sql.NullFloat64{Float64: point.Lng, Valid: true}
+	return true
+}
+
+// updateVenueParams converts a venue into the parameters of UpdateVenue.
+func updateVenueParams(venue db.Venues) db.UpdateVenueParams {
+	return db.UpdateVenueParams{
+		ID:              venue.ID,
+		Name:            venue.Name,
+		ImageLinks:      venue.ImageLinks,
+		Type:            venue.Type,
+		Description:     venue.Description,
+		Location:        venue.Location,
+		Dimension:       venue.Dimension,
+		Capacity:        venue.Capacity,
+		Facilities:      venue.Facilities,
+		HasAccomodation: venue.HasAccomodation,
+		RoomType:        venue.RoomType,
+		NoOfRooms:       venue.NoOfRooms,
+		Sleeps:          venue.Sleeps,
+		BedType:         venue.BedType,
+		Rent:            venue.Rent,
+		OwnedBy:         venue.OwnedBy,
+		IsAvailable:     venue.IsAvailable,
+		OpensAt:         venue.OpensAt,
+		ClosesAt:        venue.ClosesAt,
+		RentalDays:      venue.RentalDays,
+		BookingPrice:    venue.BookingPrice,
+		Latitude:        venue.Latitude,
+		Longitude:       venue.Longitude,
+		Currency:        venue.Currency,
+	}
+}
+
+// createVenue handles the creation of a venue owned by the acting profile.
+// POST /venues
+func (server *Server) createVenue(ctx *gin.Context) {
+	var req venueRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var venue db.Venues
+	req.apply(&venue)
+	venue.Currency = server.priceCurrency(venue.Currency)
+	if err := validateVenue(&venue); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !venue.Latitude.Valid && !server.geocodeVenue(ctx, &venue) {
+		return
+	}
+
+	arg := db.CreateVenueParams{
+		ImageLinks:      venue.ImageLinks,
+		Name:            venue.Name,
+		Type:            venue.Type,
+		Description:     venue.Description,
+		Location:        venue.Location,
+		Dimension:       venue.Dimension,
+		Capacity:        venue.Capacity,
+		Facilities:      venue.Facilities,
+		HasAccomodation: venue.HasAccomodation,
+		RoomType:        venue.RoomType,
+		NoOfRooms:       venue.NoOfRooms,
+		Sleeps:          venue.Sleeps,
+		BedType:         venue.BedType,
+		Rent:            venue.Rent,
+		OwnedBy:         uuid.NullUUID{UUID: currentProfile(ctx).ID, Valid: true},
+		IsAvailable:     venue.IsAvailable,
+		OpensAt:         venue.OpensAt,
+		ClosesAt:        venue.ClosesAt,
+		RentalDays:      venue.RentalDays,
+		BookingPrice:    venue.BookingPrice,
+		Latitude:        venue.Latitude,
+		Longitude:       venue.Longitude,
+		Currency:        venue.Currency,
+	}
+
+	venue, err := server.store.CreateVenue(ctx, arg)
+	if err != nil {
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	rsp, ok := server.newVenueResponses(ctx, venue)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, rsp[0])
+}
+
+// venueURI defines the URI parameter for addressing a venue by ID.
+type venueURI struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// bindVenueID parses the venue ID from the URI.
+func bindVenueID(ctx *gin.Context) (uuid.UUID, bool) {
+	var uri venueURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return uuid.UUID{}, false
+	}
+
+	venueID, err := uuid.Parse(uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid venue ID format: %w", err)))
+		return uuid.UUID{}, false
+	}
+	return venueID, true
+}
+
+// loadVenue fetches a venue, writing a 404 if it does not exist.
+func (server *Server) loadVenue(ctx *gin.Context, venueID uuid.UUID) (db.Venues, bool) {
+	venue, err := server.store.GetVenue(ctx, venueID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("venue not found")))
+			return db.Venues{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return db.Venues{}, false
+	}
+	return venue, true
+}
+
+// getVenue handles fetching a single venue by ID.
+// GET /venues/:id
+func (server *Server) getVenue(ctx *gin.Context) {
+	venueID, ok := bindVenueID(ctx)
+	if !ok {
+		return
+	}
+
+	venue, ok := server.loadVenue(ctx, venueID)
+	if !ok {
+		return
+	}
+
+	rsp, ok := server.newVenueResponses(ctx, venue)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp[0])
+}
+
+var venueSorts = []sortOrder{{"name", stringKey}, {"newest", timeKey}}
+
+// venueKey returns the cursor key of a venue under the page's sort order.
+func venueKey(p page) func(venueResponse) (string, uuid.UUID) {
+	return func(venue venueResponse) (string, uuid.UUID) {
+		if p.sort.name == "newest" {
+			return encodeTime(venue.CreatedAt), venue.ID
+		}
+		return venue.Name, venue.ID
+	}
+}
+
+// listVenuesRequest defines the paging parameters and filters for listing venues.
+type listVenuesRequest struct {
+	pageRequest
+	Type        string `form:"type" binding:"max=255"`
+	IsAvailable *bool  `form:"is_available"`
+}
+
+// listVenues handles fetching a page of venues.
+// GET /venues?sort=name|newest&type=...&is_available=...
+func (server *Server) listVenues(ctx *gin.Context) {
+	var req listVenuesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.bindPage(ctx, req.pageRequest, venueSorts...)
+	if !ok {
+		return
+	}
+
+	venues, err := server.store.Listvenues(ctx, db.ListvenuesParams{
+		Type:            newNullString(req.Type),
+		IsAvailable:     newNullBool(req.IsAvailable),
+		Sort:            p.sort.name,
+		CursorID:        p.cursorID(),
+		CursorName:      p.cursorString(),
+		CursorCreatedAt: p.cursorTime(),
+		PageLimit:       p.fetch(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, ok := server.newVenueResponses(ctx, venues...)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(p, rsp, venueKey(p)))
+}
+
+// updateVenue handles replacing every field of a venue.
+// PUT /venues/:id
+func (server *Server) updateVenue(ctx *gin.Context) {
+	venueID, ok := bindVenueID(ctx)
+	if !ok {
+		return
+	}
+
+	var req venueRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	venue, ok := server.loadVenue(ctx, venueID)
+	if !ok || !server.authorizeOwner(ctx, venue.OwnedBy) {
+		return
+	}
+
+	req.apply(&venue)
+	if req.Latitude == nil && req.Longitude == nil && !server.geocodeVenue(ctx, &venue) {
+		return
+	}
+	server.saveVenue(ctx, venue)
+}
+
+// patchVenue handles updating only the fields present in the request.
+// PATCH /venues/:id
+func (server *Server) patchVenue(ctx *gin.Context) {
+	venueID, ok := bindVenueID(ctx)
+	if !ok {
+		return
+	}
+
+	var req patchVenueRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	venue, ok := server.loadVenue(ctx, venueID)
+	if !ok || !server.authorizeOwner(ctx, venue.OwnedBy) {
+		return
+	}
+
+	moved := req.Location != nil && *req.Location != venue.Location
+	req.apply(&venue)
+	if moved && req.Latitude == nil && req.Longitude == nil && !server.geocodeVenue(ctx, &venue) {
+		return
+	}
+	server.saveVenue(ctx, venue)
+}
+
+// saveVenue validates and stores an updated venue and writes it back.
+func (server *Server) saveVenue(ctx *gin.Context, venue db.Venues) {
+	venue.Currency = server.priceCurrency(venue.Currency)
+	if err := validateVenue(&venue); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	venue, err := server.store.UpdateVenue(ctx, updateVenueParams(venue))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("venue not found for update")))
+			return
+		}
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	rsp, ok := server.newVenueResponses(ctx, venue)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp[0])
+}
+
+// deleteVenue handles deleting a venue by ID.
+// DELETE /venues/:id
+func (server *Server) deleteVenue(ctx *gin.Context) {
+	venueID, ok := bindVenueID(ctx)
+	if !ok {
+		return
+	}
+
+	venue, ok := server.loadVenue(ctx, venueID)
+	if !ok || !server.authorizeOwner(ctx, venue.OwnedBy) {
+		return
+	}
+
+	err := server.store.DeleteVenue(ctx, venue.ID)
+	if err != nil {
+		ctx.JSON(dbErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/geo"
+)
+
+// searchVenuesRequest defines the filters of a venue search. Facilities may
+// be repeated and a venue must have all of them.
+type searchVenuesRequest struct {
+	pageRequest
+	Type            string   `form:"type" binding:"max=255"`
+	Location        string   `form:"location" binding:"max=255"`
+	MinCapacity     *int32   `form:"min_capacity" binding:"omitempty,min=0"`
+	Facilities      []string `form:"facilities" binding:"omitempty,dive,required,max=255"`
+	HasAccomodation *bool    `form:"has_accomodation"`
+	BedType         string   `form:"bed_type" binding:"max=255"`
+	RoomType        string   `form:"room_type" binding:"max=255"`
+	MinRent         *int32   `form:"min_rent" binding:"omitempty,min=0"`
+	MaxRent         *int32   `form:"max_rent" binding:"omitempty,min=0"`
+	MinBookingPrice *int32   `form:"min_booking_price" binding:"omitempty,min=0"`
+	MaxBookingPrice *int32   `form:"max_booking_price" binding:"omitempty,min=0"`
+	AvailableOn     string   `form:"available_on" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// params converts the filters into the arguments of the search queries.
+func (req searchVenuesRequest) params() (db.SearchVenuesParams, error) {
+	if req.MinRent != nil && req.MaxRent != nil && *req.MinRent > *req.MaxRent {
+		return db.SearchVenuesParams{}, errors.New("min_rent must not exceed max_rent")
+	}
+	if req.MinBookingPrice != nil && req.MaxBookingPrice != nil && *req.MinBookingPrice > *req.MaxBookingPrice {
+		return db.SearchVenuesParams{}, errors.New("min_booking_price must not exceed max_booking_price")
+	}
+
+	arg := db.SearchVenuesParams{
+		Type:            newNullString(req.Type),
+		Location:        newNullString(req.Location),
+		MinCapacity:     newNullInt32(req.MinCapacity),
+		Facilities:      req.Facilities,
+		HasAccomodation: newNullBool(req.HasAccomodation),
+		BedType:         newNullString(req.BedType),
+		RoomType:        newNullString(req.RoomType),
+		MinRent:         newNullInt32(req.MinRent),
+		MaxRent:         newNullInt32(req.MaxRent),
+		MinBookingPrice: newNullInt32(req.MinBookingPrice),
+		MaxBookingPrice: newNullInt32(req.MaxBookingPrice),
+	}
+	if req.AvailableOn != "" {
+		day, err := time.Parse(dateLayout, req.AvailableOn)
+		if err != nil {
+			return arg, err
+		}
+		arg.AvailableOn = newNullTime(&day)
+	}
+	return arg, nil
+}
+
+// facetCount is the number of matching venues with one value of a facet.
+type facetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// searchVenuesResponse is a page of matching venues together with facet
+// counts over every match, not just the current page.
+type searchVenuesResponse struct {
+	pageResponse[venueResponse]
+	Facets map[string][]facetCount `json:"facets"`
+}
+
+// searchVenues handles finding venues by type, location, capacity,
+// facilities, accommodation, price and availability on a date.
+// GET /venues/search
+func (server *Server) searchVenues(ctx *gin.Context) {
+	var req searchVenuesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg, err := req.params()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.bindPage(ctx, req.pageRequest, venueSorts...)
+	if !ok {
+		return
+	}
+	arg.Sort = p.sort.name
+	arg.CursorID = p.cursorID()
+	arg.CursorName = p.cursorString()
+	arg.CursorCreatedAt = p.cursorTime()
+	arg.PageLimit = p.fetch()
+
+	venues, err := server.store.SearchVenues(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rows, err := server.store.CountVenueFacets(ctx, db.CountVenueFacetsParams{
+		Type:            arg.Type,
+		Location:        arg.Location,
+		MinCapacity:     arg.MinCapacity,
+		Facilities:      arg.Facilities,
+		HasAccomodation: arg.HasAccomodation,
+		BedType:         arg.BedType,
+		RoomType:        arg.RoomType,
+		MinRent:         arg.MinRent,
+		MaxRent:         arg.MaxRent,
+		MinBookingPrice: arg.MinBookingPrice,
+		MaxBookingPrice: arg.MaxBookingPrice,
+		AvailableOn:     arg.AvailableOn,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	facets := map[string][]facetCount{
+		"type":             {},
+		"room_type":        {},
+		"bed_type":         {},
+		"has_accomodation": {},
+	}
+	for _, row := range rows {
+		facets[row.Facet] = append(facets[row.Facet], facetCount{Value: row.Value, Count: row.VenueCount})
+	}
+
+	items, ok := server.newVenueResponses(ctx, venues...)
+	if !ok {
+		return
+	}
+
+	rsp := searchVenuesResponse{
+		pageResponse: newPageResponse(p, items, venueKey(p)),
+		Facets:       facets,
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+var nearbySorts = []sortOrder{{"distance", floatKey}}
+
+// listVenuesNearbyRequest defines the centre and radius of a nearby search.
+type listVenuesNearbyRequest struct {
+	pageRequest
+	Lat      *float64 `form:"lat" binding:"required,min=-90,max=90"`
+	Lng      *float64 `form:"lng" binding:"required,min=-180,max=180"`
+	RadiusKm float64  `form:"radius_km" binding:"required,gt=0,max=1000"`
+}
+
+// nearbyVenueResponse is a venue and its distance from the search centre.
+type nearbyVenueResponse struct {
+	venueResponse
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// listVenuesNearby handles finding the venues within a radius of a point,
+// nearest first. Venues without coordinates are never included.
+// GET /venues/nearby?lat=...&lng=...&radius_km=...
+func (server *Server) listVenuesNearby(ctx *gin.Context) {
+	var req listVenuesNearbyRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.bindPage(ctx, req.pageRequest, nearbySorts...)
+	if !ok {
+		return
+	}
+
+	center := geo.Point{Lat: *req.Lat, Lng: *req.Lng}
+	sw, ne := geo.BoundingBox(center, req.RadiusKm)
+
+	rows, err := server.store.ListVenuesNearby(ctx, db.ListVenuesNearbyParams{
+		Lat:            center.Lat,
+		Lng:            center.Lng,
+		MinLat:         sw.Lat,
+		MaxLat:         ne.Lat,
+		MinLng:         sw.Lng,
+		MaxLng:         ne.Lng,
+		RadiusKm:       req.RadiusKm,
+		CursorID:       p.cursorID(),
+		CursorDistance: p.cursorFloat(),
+		PageLimit:      p.fetch(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	venues := make([]db.Venues, 0, len(rows))
+	for _, row := range rows {
+		venues = append(venues, row.Venues)
+	}
+
+	items, ok := server.newVenueResponses(ctx, venues...)
+	if !ok {
+		return
+	}
+
+	rsp := make([]nearbyVenueResponse, 0, len(rows))
+	for i, row := range rows {
+		rsp = append(rsp, nearbyVenueResponse{venueResponse: items[i], DistanceKm: row.DistanceKm})
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(p, rsp, func(venue nearbyVenueResponse) (string, uuid.UUID) {
+		return encodeFloat(venue.DistanceKm), venue.ID
+	}))
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/geo"
+)
+
+// nearbyStore answers ListVenuesNearby the way the query does: venues in
+// the bounding box and within the radius, ordered by haversine distance.
+type nearbyStore struct {
+	db.Store
+
+	venues []db.Venues
+}
+
+func (store *nearbyStore) ListVenuesNearby(ctx context.Context, arg db.ListVenuesNearbyParams) ([]db.ListVenuesNearbyRow, error) {
+	center := geo.Point{Lat: arg.Lat, Lng: arg.Lng}
+
+	var rows []db.ListVenuesNearbyRow
+	for _, venue := range store.venues {
+		if !venue.Latitude.Valid || !venue.Longitude.Valid {
+			continue
+		}
+		point := geo.Point{Lat: venue.Latitude.Float64, Lng: venue.Longitude.Float64}
+		if point.Lat < arg.MinLat || point.Lat > arg.MaxLat || point.Lng < arg.MinLng || point.Lng > arg.MaxLng {
+			continue
+		}
+
+		distance := geo.DistanceKm(center, point)
+		if distance > arg.RadiusKm {
+			continue
+		}
+		if arg.CursorID.Valid && (distance < arg.CursorDistance.Float64 ||
+			distance == arg.CursorDistance.Float64 && venue.ID.String() <= arg.CursorID.UUID.String()) {
+			continue
+		}
+		rows = append(rows, db.ListVenuesNearbyRow{Venues: venue, DistanceKm: distance})
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].DistanceKm != rows[j].DistanceKm {
+			return rows[i].DistanceKm < rows[j].DistanceKm
+		}
+		return rows[i].Venues.ID.String() < rows[j].Venues.ID.String()
+	})
+	if len(rows) > int(arg.PageLimit) {
+		rows = rows[:arg.PageLimit]
+	}
+	return rows, nil
+}
+
+func TestListVenuesNearby(t *testing.T) {
+	venue := func(name string, lat, lng float64) db.Venues {
+		return db.Venues{
+			ID:        uuid.New(),
+			Name:      name,
+			Latitude:  sql.NullFloat64{Float64: lat, Valid: true},
+			Longitude: sql.NullFloat64{Float64: lng, Valid: true},
+			Currency:  "USD",
+		}
+	}
+
+	store := &nearbyStore{venues: []db.Venues{
+		venue("Berlin", 52.5200, 13.4050),
+		venue("Amsterdam", 52.3676, 4.9041),
+		venue("Paris", 48.8566, 2.3522),
+		venue("Brussels", 50.8503, 4.3517),
+		venue("London", 51.5074, -0.1278),
+		{ID: uuid.New(), Name: "Nowhere", Currency: "USD"},
+	}}
+	server := newTestServer(t, store)
+
+	type page struct {
+		Items []struct {
+			Name       string  `json:"name"`
+			DistanceKm float64 `json:"distance_km"`
+		} `json:"items"`
+		NextCursor string `json:"next_cursor"`
+	}
+
+	get := func(t *testing.T, query url.Values) page {
+		request := httptest.NewRequest(http.MethodGet, "/venues/nearby?"+query.Encode(), nil)
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, request)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+		}
+
+		var rsp page
+		if err := json.Unmarshal(recorder.Body.Bytes(), &rsp); err != nil {
+			t.Fatalf("cannot decode response %q: %v", recorder.Body.String(), err)
+		}
+		return rsp
+	}
+
+	names := func(rsp page) string {
+		var names []string
+		for i, item := range rsp.Items {
+			if i > 0 && item.DistanceKm < rsp.Items[i-1].DistanceKm {
+				t.Errorf("%s at %.1f km listed after %.1f km", item.Name, item.DistanceKm, rsp.Items[i-1].DistanceKm)
+			}
+			names = append(names, item.Name)
+		}
+		return fmt.Sprint(names)
+	}
+
+	query := url.Values{
+		"lat":       {"51.5074"},
+		"lng":       {"-0.1278"},
+		"radius_km": {"400"},
+		"limit":     {"2"},
+	}
+
+	first := get(t, query)
+	if got, want := names(first), "[London Brussels]"; got != want {
+		t.Fatalf("first page = %s, want %s", got, want)
+	}
+	if first.NextCursor == "" {
+		t.Fatal("first page has no next cursor")
+	}
+
+	query.Set("cursor", first.NextCursor)
+	second := get(t, query)
+	if got, want := names(second), "[Paris Amsterdam]"; got != want {
+		t.Fatalf("second page = %s, want %s", got, want)
+	}
+	if second.NextCursor != "" {
+		t.Fatalf("last page has next cursor %q", second.NextCursor)
+	}
+
+	query = url.Values{"lat": {"52.5200"}, "lng": {"13.4050"}, "radius_km": {"1000"}}
+	all := get(t, query)
+	if got, want := names(all), "[Berlin Amsterdam Brussels Paris London]"; got != want {
+		t.Fatalf("from berlin = %s, want %s", got, want)
+	}
+}
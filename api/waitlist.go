@@ -0,0 +1,320 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// Errors returned when joining a waitlist is refused.
+var (
+	errAlreadyWaiting = errors.New("profile is already on the waitlist")
+	errNotSoldOut     = errors.New("event is not sold out; buy a ticket instead")
+	errVenueDayFree   = errors.New("venue is free on the requested day; book it instead")
+)
+
+// joinEventWaitlist queues the acting profile for a place at a sold out event.
+// POST /events/:id/waitlist
+func (server *Server) joinEventWaitlist(ctx *gin.Context) {
+	eventID, ok := bindEventID(ctx)
+	if !ok {
+		return
+	}
+
+	event, ok := server.loadEvent(ctx, eventID)
+	if !ok {
+		return
+	}
+
+	if util.EventStatus(event.Status) != util.EventPublished {
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(db.ErrEventNotOnSale))
+		return
+	}
+
+	soldOut, err := server.eventSoldOut(ctx, event)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if !soldOut {
+		ctx.JSON(http.StatusConflict, errorResponse(errNotSoldOut))
+		return
+	}
+
+	entry, err := server.store.CreateWaitlistEntry(ctx, db.CreateWaitlistEntryParams{
+		EventID:   uuid.NullUUID{UUID: event.ID, Valid: true},
+		ProfileID: currentProfile(ctx).ID,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			ctx.JSON(http.StatusConflict, errorResponse(errAlreadyWaiting))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, entry)
+}
+
+// eventSoldOut reports whether every place at an event is sold or held for
+// a promoted waitlist entry. Events without a participant limit never sell out.
+func (server *Server) eventSoldOut(ctx *gin.Context, event db.Events) (bool, error) {
+	if !event.TotalParticpant.Valid {
+		return false, nil
+	}
+
+	eventID := uuid.NullUUID{UUID: event.ID, Valid: true}
+	taken, err := server.store.CountPurchasesByEvent(ctx, eventID)
+	if err != nil {
+		return false, err
+	}
+	held, err := server.store.CountEventHolds(ctx, eventID)
+	if err != nil {
+		return false, err
+	}
+
+	return taken+held >= int64(event.TotalParticpant.Int32), nil
+}
+
+var waitlistSorts = []sortOrder{{"queue", timeKey}}
+
+func waitlistEntryKey(entry db.WaitlistEntries) (string, uuid.UUID) {
+	return encodeTime(entry.CreatedAt), entry.ID
+}
+
+// listEventWaitlistRequest defines the paging parameters for an event's waitlist.
+type listEventWaitlistRequest struct {
+	pageRequest
+}
+
+// listEventWaitlist lists the profiles waiting for an event, in queue order.
+// Only the event's organiser may see the queue.
+// GET /events/:id/waitlist
+func (server *Server) listEventWaitlist(ctx *gin.Context) {
+	eventID, ok := bindEventID(ctx)
+	if !ok {
+		return
+	}
+
+	var req listEventWaitlistRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.bindPage(ctx, req.pageRequest, waitlistSorts...)
+	if !ok {
+		return
+	}
+
+	event, ok := server.loadEvent(ctx, eventID)
+	if !ok || !server.authorizeOwner(ctx, event.CreatedBy) {
+		return
+	}
+
+	entries, err := server.store.ListWaitingByEvent(ctx, db.ListWaitingByEventParams{
+		EventID:         uuid.NullUUID{UUID: event.ID, Valid: true},
+		CursorID:        p.cursorID(),
+		CursorCreatedAt: p.cursorTime(),
+		PageLimit:       p.fetch(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(p, entries, waitlistEntryKey))
+}
+
+// leaveEventWaitlist withdraws the acting profile from an event's waitlist.
+// DELETE /events/:id/waitlist
+func (server *Server) leaveEventWaitlist(ctx *gin.Context) {
+	eventID, ok := bindEventID(ctx)
+	if !ok {
+		return
+	}
+
+	rows, err := server.store.WithdrawEventWaitlistEntry(ctx, db.WithdrawEventWaitlistEntryParams{
+		EventID:   uuid.NullUUID{UUID: eventID, Valid: true},
+		ProfileID: currentProfile(ctx).ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if rows == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("waitlist entry not found")))
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// venueWaitlistRequest identifies the venue day being waited for.
+type venueWaitlistRequest struct {
+	BookedFor string `json:"booked_for" form:"booked_for" binding:"required,datetime=2006-01-02"`
+}
+
+// day parses the requested day.
+func (req venueWaitlistRequest) day() sql.NullTime {
+	day, _ := time.Parse(dateLayout, req.BookedFor) // already validated by the binding
+	return sql.NullTime{Time: day, Valid: true}
+}
+
+// joinVenueWaitlist queues the acting profile for a day at a venue that is
+// already booked or held.
+// POST /venues/:id/waitlist
+func (server *Server) joinVenueWaitlist(ctx *gin.Context) {
+	venueID, ok := bindVenueID(ctx)
+	if !ok {
+		return
+	}
+
+	var req venueWaitlistRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	venue, ok := server.loadVenue(ctx, venueID)
+	if !ok {
+		return
+	}
+
+	free, err := server.venueDayFree(ctx, venue, req.day())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if free {
+		ctx.JSON(http.StatusConflict, errorResponse(errVenueDayFree))
+		return
+	}
+
+	entry, err := server.store.CreateWaitlistEntry(ctx, db.CreateWaitlistEntryParams{
+		VenueID:   uuid.NullUUID{UUID: venue.ID, Valid: true},
+		BookedFor: req.day(),
+		ProfileID: currentProfile(ctx).ID,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			ctx.JSON(http.StatusConflict, errorResponse(errAlreadyWaiting))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, entry)
+}
+
+// venueDayFree reports whether a venue day is neither booked nor held for
+// a promoted waitlist entry.
+func (server *Server) venueDayFree(ctx *gin.Context, venue db.Venues, day sql.NullTime) (bool, error) {
+	booked, err := server.store.CountBookedVenuesBetween(ctx, db.CountBookedVenuesBetweenParams{
+		VenueID:  venue.ID,
+		FromTime: day.Time,
+		ToTime:   day.Time.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		return false, err
+	}
+	held, err := server.store.CountVenueHolds(ctx, db.CountVenueHoldsParams{
+		VenueID:   uuid.NullUUID{UUID: venue.ID, Valid: true},
+		BookedFor: day,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return booked == 0 && held == 0, nil
+}
+
+// listVenueWaitlistRequest defines the optional day filter for a venue's waitlist.
+type listVenueWaitlistRequest struct {
+	pageRequest
+	BookedFor string `form:"booked_for" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// listVenueWaitlist lists the profiles waiting for a venue, in queue order.
+// Only the venue's owner may see the queue.
+// GET /venues/:id/waitlist?booked_for=...
+func (server *Server) listVenueWaitlist(ctx *gin.Context) {
+	venueID, ok := bindVenueID(ctx)
+	if !ok {
+		return
+	}
+
+	var req listVenueWaitlistRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	p, ok := server.bindPage(ctx, req.pageRequest, waitlistSorts...)
+	if !ok {
+		return
+	}
+
+	venue, ok := server.loadVenue(ctx, venueID)
+	if !ok || !server.authorizeOwner(ctx, venue.OwnedBy) {
+		return
+	}
+
+	arg := db.ListWaitingByVenueParams{
+		VenueID:         uuid.NullUUID{UUID: venue.ID, Valid: true},
+		CursorID:        p.cursorID(),
+		CursorCreatedAt: p.cursorTime(),
+		PageLimit:       p.fetch(),
+	}
+	if req.BookedFor != "" {
+		arg.BookedFor = venueWaitlistRequest{BookedFor: req.BookedFor}.day()
+	}
+
+	entries, err := server.store.ListWaitingByVenue(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPageResponse(p, entries, waitlistEntryKey))
+}
+
+// leaveVenueWaitlist withdraws the acting profile from a venue day's waitlist.
+// DELETE /venues/:id/waitlist?booked_for=...
+func (server *Server) leaveVenueWaitlist(ctx *gin.Context) {
+	venueID, ok := bindVenueID(ctx)
+	if !ok {
+		return
+	}
+
+	var req venueWaitlistRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rows, err := server.store.WithdrawVenueWaitlistEntry(ctx, db.WithdrawVenueWaitlistEntryParams{
+		VenueID:   uuid.NullUUID{UUID: venueID, Valid: true},
+		BookedFor: req.day(),
+		ProfileID: currentProfile(ctx).ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if rows == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("waitlist entry not found")))
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,107 @@
+// Package availability computes free booking slots for venues and
+// practitioners from their opening hours, bookable weekdays and existing
+// bookings.
+package availability
+
+import (
+	"sort"
+	"time"
+
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// Interval is a half-open time range [Start, End).
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Overlaps reports whether two intervals share any instant.
+func (i Interval) Overlaps(other Interval) bool {
+	return i.Start.Before(other.End) && other.Start.Before(i.End)
+}
+
+// Schedule describes when a resource can be booked.
+type Schedule struct {
+	Days  util.Weekdays
+	Hours util.OpeningHours
+}
+
+// FreeSlots splits every opening window of the schedule between from and to
+// into consecutive slots of length step, starting at the opening time, and
+// returns the slots that lie entirely inside [from, to) and do not overlap
+// any busy interval. Slots are returned in chronological order.
+func FreeSlots(schedule Schedule, busy []Interval, from, to time.Time, step time.Duration) []Interval {
+	return FreeSlotsEvery(schedule, busy, from, to, step, step)
+}
+
+// FreeSlotsEvery works like FreeSlots but returns slots of the given length
+// starting every step from the opening time, so slots overlap when length is
+// longer than step. Each slot fits inside its opening window, so every
+// returned start can be booked for length.
+func FreeSlotsEvery(schedule Schedule, busy []Interval, from, to time.Time, length, step time.Duration) []Interval {
+	if length <= 0 || step <= 0 || !from.Before(to) {
+		return nil
+	}
+
+	busy = append([]Interval(nil), busy...)
+	sort.Slice(busy, func(i, j int) bool { return busy[i].Start.Before(busy[j].Start) })
+
+	slots := []Interval{}
+	next := 0 // index of the first busy interval that may still overlap a slot
+	for day := util.StartOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !schedule.Days.Contains(day.Weekday()) {
+			continue
+		}
+
+		opens, closes := schedule.Hours.Window(day)
+		for start := opens; !start.Add(length).After(closes); start = start.Add(step) {
+			slot := Interval{Start: start, End: start.Add(length)}
+			if slot.Start.Before(from) || slot.End.After(to) {
+				continue
+			}
+
+			for next < len(busy) && !busy[next].End.After(slot.Start) {
+				next++
+			}
+			if !overlapsAny(busy[next:], slot) {
+				slots = append(slots, slot)
+			}
+		}
+	}
+	return slots
+}
+
+// overlapsAny reports whether slot overlaps one of the busy intervals,
+// which must be sorted by start time.
+func overlapsAny(busy []Interval, slot Interval) bool {
+	for _, b := range busy {
+		if !b.Start.Before(slot.End) {
+			return false
+		}
+		if b.Overlaps(slot) {
+			return true
+		}
+	}
+	return false
+}
+
+// WholeDays returns one busy interval per booking covering the whole
+// calendar day of each booking, for resources rented by the day.
+func WholeDays(bookings []time.Time) []Interval {
+	busy := make([]Interval, 0, len(bookings))
+	for _, t := range bookings {
+		day := util.StartOfDay(t)
+		busy = append(busy, Interval{Start: day, End: day.AddDate(0, 0, 1)})
+	}
+	return busy
+}
+
+// Fixed returns one busy interval of the given length per booking start.
+func Fixed(bookings []time.Time, length time.Duration) []Interval {
+	busy := make([]Interval, 0, len(bookings))
+	for _, t := range bookings {
+		busy = append(busy, Interval{Start: t, End: t.Add(length)})
+	}
+	return busy
+}
@@ -0,0 +1,346 @@
+package availability
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// at returns the given time on 2024-01-01 (a Monday) plus days.
+func at(days, hour, minute int) time.Time {
+	return time.Date(2024, time.January, 1+days, hour, minute, 0, 0, time.UTC)
+}
+
+// hours returns an opening window from opens to closes o'clock.
+func hours(opens, closes int) util.OpeningHours {
+	return util.OpeningHours{OpensAt: at(0, opens, 0), ClosesAt: at(0, closes, 0), Valid: true}
+}
+
+// slots returns consecutive intervals of length step starting at start.
+func slots(start time.Time, step time.Duration, n int) []Interval {
+	result := make([]Interval, 0, n)
+	for i := 0; i < n; i++ {
+		result = append(result, Interval{Start: start, End: start.Add(step)})
+		start = start.Add(step)
+	}
+	return result
+}
+
+func concat(lists ...[]Interval) []Interval {
+	result := []Interval{}
+	for _, list := range lists {
+		result = append(result, list...)
+	}
+	return result
+}
+
+func TestFreeSlots(t *testing.T) {
+	weekdays, err := util.ParseWeekdays("mon-fri")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		schedule Schedule
+		busy     []Interval
+		from     time.Time
+		to       time.Time
+		step     time.Duration
+		want     []Interval
+	}{
+		{
+			name:     "empty day",
+			schedule: Schedule{Days: util.AllWeekdays, Hours: hours(9, 12)},
+			from:     at(0, 0, 0),
+			to:       at(1, 0, 0),
+			step:     time.Hour,
+			want:     slots(at(0, 9, 0), time.Hour, 3),
+		},
+		{
+			name:     "booking removes overlapping slots",
+			schedule: Schedule{Days: util.AllWeekdays, Hours: hours(9, 13)},
+			busy:     []Interval{{Start: at(0, 10, 30), End: at(0, 11, 30)}},
+			from:     at(0, 0, 0),
+			to:       at(1, 0, 0),
+			step:     time.Hour,
+			want:     concat(slots(at(0, 9, 0), time.Hour, 1), slots(at(0, 12, 0), time.Hour, 1)),
+		},
+		{
+			name:     "booking ending at slot start leaves it free",
+			schedule: Schedule{Days: util.AllWeekdays, Hours: hours(9, 11)},
+			busy:     []Interval{{Start: at(0, 8, 0), End: at(0, 9, 0)}},
+			from:     at(0, 0, 0),
+			to:       at(1, 0, 0),
+			step:     time.Hour,
+			want:     slots(at(0, 9, 0), time.Hour, 2),
+		},
+		{
+			name:     "booking overlapping start of range",
+			schedule: Schedule{Days: util.AllWeekdays, Hours: hours(9, 12)},
+			busy:     []Interval{{Start: at(-1, 23, 0), End: at(0, 10, 0)}},
+			from:     at(0, 0, 0),
+			to:       at(1, 0, 0),
+			step:     time.Hour,
+			want:     slots(at(0, 10, 0), time.Hour, 2),
+		},
+		{
+			name:     "booking overlapping end of range",
+			schedule: Schedule{Days: util.AllWeekdays, Hours: hours(9, 12)},
+			busy:     []Interval{{Start: at(0, 11, 0), End: at(1, 2, 0)}},
+			from:     at(0, 0, 0),
+			to:       at(1, 0, 0),
+			step:     time.Hour,
+			want:     slots(at(0, 9, 0), time.Hour, 2),
+		},
+		{
+			name:     "range cuts through opening window",
+			schedule: Schedule{Days: util.AllWeekdays, Hours: hours(9, 12)},
+			from:     at(0, 9, 30),
+			to:       at(0, 11, 30),
+			step:     time.Hour,
+			want:     slots(at(0, 10, 0), time.Hour, 1),
+		},
+		{
+			name:     "slots across midnight",
+			schedule: Schedule{Days: util.AllWeekdays},
+			from:     at(0, 22, 0),
+			to:       at(1, 2, 0),
+			step:     time.Hour,
+			want:     slots(at(0, 22, 0), time.Hour, 4),
+		},
+		{
+			name:     "non-working days are skipped",
+			schedule: Schedule{Days: weekdays, Hours: hours(9, 10)},
+			from:     at(4, 0, 0), // Friday
+			to:       at(8, 0, 0), // Tuesday
+			step:     time.Hour,
+			want:     concat(slots(at(4, 9, 0), time.Hour, 1), slots(at(7, 9, 0), time.Hour, 1)),
+		},
+		{
+			name:     "partial slot at closing is dropped",
+			schedule: Schedule{Days: util.AllWeekdays, Hours: hours(9, 11)},
+			from:     at(0, 0, 0),
+			to:       at(1, 0, 0),
+			step:     90 * time.Minute,
+			want:     slots(at(0, 9, 0), 90*time.Minute, 1),
+		},
+		{
+			name:     "unsorted bookings",
+			schedule: Schedule{Days: util.AllWeekdays, Hours: hours(9, 13)},
+			busy: []Interval{
+				{Start: at(0, 12, 0), End: at(0, 13, 0)},
+				{Start: at(0, 9, 0), End: at(0, 10, 0)},
+			},
+			from: at(0, 0, 0),
+			to:   at(1, 0, 0),
+			step: time.Hour,
+			want: slots(at(0, 10, 0), time.Hour, 2),
+		},
+		{
+			name:     "fully booked",
+			schedule: Schedule{Days: util.AllWeekdays, Hours: hours(9, 12)},
+			busy:     WholeDays([]time.Time{at(0, 15, 0)}),
+			from:     at(0, 0, 0),
+			to:       at(1, 0, 0),
+			step:     time.Hour,
+			want:     []Interval{},
+		},
+		{
+			name:     "empty range",
+			schedule: Schedule{Days: util.AllWeekdays},
+			from:     at(1, 0, 0),
+			to:       at(0, 0, 0),
+			step:     time.Hour,
+			want:     nil,
+		},
+		{
+			name:     "invalid step",
+			schedule: Schedule{Days: util.AllWeekdays},
+			from:     at(0, 0, 0),
+			to:       at(1, 0, 0),
+			step:     0,
+			want:     nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := FreeSlots(tc.schedule, tc.busy, tc.from, tc.to, tc.step)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("FreeSlots() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFreeSlotsEvery(t *testing.T) {
+	testCases := []struct {
+		name     string
+		schedule Schedule
+		busy     []Interval
+		length   time.Duration
+		step     time.Duration
+		want     []Interval
+	}{
+		{
+			name:     "slots longer than the step overlap",
+			schedule: Schedule{Days: util.AllWeekdays, Hours: hours(9, 11)},
+			length:   time.Hour,
+			step:     30 * time.Minute,
+			want: []Interval{
+				{Start: at(0, 9, 0), End: at(0, 10, 0)},
+				{Start: at(0, 9, 30), End: at(0, 10, 30)},
+				{Start: at(0, 10, 0), End: at(0, 11, 0)},
+			},
+		},
+		{
+			name:     "booking removes every start it overlaps",
+			schedule: Schedule{Days: util.AllWeekdays, Hours: hours(9, 12)},
+			busy:     Fixed([]time.Time{at(0, 10, 0)}, time.Hour),
+			length:   time.Hour,
+			step:     30 * time.Minute,
+			want: []Interval{
+				{Start: at(0, 9, 0), End: at(0, 10, 0)},
+				{Start: at(0, 11, 0), End: at(0, 12, 0)},
+			},
+		},
+		{
+			name:     "slots shorter than the step leave gaps",
+			schedule: Schedule{Days: util.AllWeekdays, Hours: hours(9, 11)},
+			length:   30 * time.Minute,
+			step:     time.Hour,
+			want: []Interval{
+				{Start: at(0, 9, 0), End: at(0, 9, 30)},
+				{Start: at(0, 10, 0), End: at(0, 10, 30)},
+			},
+		},
+		{
+			name:     "slot longer than the opening window",
+			schedule: Schedule{Days: util.AllWeekdays, Hours: hours(9, 10)},
+			length:   2 * time.Hour,
+			step:     30 * time.Minute,
+			want:     []Interval{},
+		},
+		{
+			name:     "invalid length",
+			schedule: Schedule{Days: util.AllWeekdays},
+			length:   0,
+			step:     time.Hour,
+			want:     nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := FreeSlotsEvery(tc.schedule, tc.busy, at(0, 0, 0), at(1, 0, 0), tc.length, tc.step)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("FreeSlotsEvery() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWholeDays(t *testing.T) {
+	testCases := []struct {
+		name     string
+		bookings []time.Time
+		want     []Interval
+	}{
+		{
+			name: "no bookings",
+			want: []Interval{},
+		},
+		{
+			name:     "bookings cover their calendar day",
+			bookings: []time.Time{at(0, 14, 30), at(2, 0, 0)},
+			want: []Interval{
+				{Start: at(0, 0, 0), End: at(1, 0, 0)},
+				{Start: at(2, 0, 0), End: at(3, 0, 0)},
+			},
+		},
+		{
+			name:     "booking just before midnight",
+			bookings: []time.Time{at(0, 23, 59)},
+			want:     []Interval{{Start: at(0, 0, 0), End: at(1, 0, 0)}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := WholeDays(tc.bookings)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("WholeDays() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFixed(t *testing.T) {
+	testCases := []struct {
+		name     string
+		bookings []time.Time
+		length   time.Duration
+		want     []Interval
+	}{
+		{
+			name:   "no bookings",
+			length: time.Hour,
+			want:   []Interval{},
+		},
+		{
+			name:     "one interval per booking",
+			bookings: []time.Time{at(0, 9, 0), at(0, 13, 15)},
+			length:   45 * time.Minute,
+			want: []Interval{
+				{Start: at(0, 9, 0), End: at(0, 9, 45)},
+				{Start: at(0, 13, 15), End: at(0, 14, 0)},
+			},
+		},
+		{
+			name:     "interval across midnight",
+			bookings: []time.Time{at(0, 23, 30)},
+			length:   time.Hour,
+			want:     []Interval{{Start: at(0, 23, 30), End: at(1, 0, 30)}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Fixed(tc.bookings, tc.length)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Fixed() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIntervalOverlaps(t *testing.T) {
+	base := Interval{Start: at(0, 10, 0), End: at(0, 11, 0)}
+
+	testCases := []struct {
+		name  string
+		other Interval
+		want  bool
+	}{
+		{"inside", Interval{Start: at(0, 10, 15), End: at(0, 10, 45)}, true},
+		{"covering", Interval{Start: at(0, 9, 0), End: at(0, 12, 0)}, true},
+		{"overlapping start", Interval{Start: at(0, 9, 30), End: at(0, 10, 30)}, true},
+		{"overlapping end", Interval{Start: at(0, 10, 30), End: at(0, 11, 30)}, true},
+		{"touching before", Interval{Start: at(0, 9, 0), End: at(0, 10, 0)}, false},
+		{"touching after", Interval{Start: at(0, 11, 0), End: at(0, 12, 0)}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := base.Overlaps(tc.other); got != tc.want {
+				t.Errorf("Overlaps() = %v, want %v", got, tc.want)
+			}
+			if got := tc.other.Overlaps(base); got != tc.want {
+				t.Errorf("reversed Overlaps() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
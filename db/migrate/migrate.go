@@ -0,0 +1,319 @@
+// Package migrate applies the versioned SQL migrations in db/migrations.
+//
+// Applied versions are tracked in a schema_migrations table laid out like the
+// one used by golang-migrate, so databases migrated by hand with that tool can
+// be taken over. A Postgres advisory lock serialises runners, so several
+// instances starting at once apply each migration exactly once.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// lockID is the key of the advisory lock held while migrating.
+const lockID int64 = 0x7461627261736101
+
+var (
+	ErrDirty          = errors.New("database is dirty; fix the failed migration and force its version")
+	ErrUnknownVersion = errors.New("unknown migration version")
+	ErrNoChange       = errors.New("no change")
+)
+
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration is one schema version with the SQL that applies and reverts it.
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Migrator applies migrations to a database.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New reads the migrations in fsys, which must contain only files named
+// NNNNNN_name.up.sql and NNNNNN_name.down.sql at its root.
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("cannot read migrations: %w", err)
+	}
+
+	byVersion := map[uint64]*Migration{}
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q: %w", entry.Name(), err)
+		}
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("cannot read migration %q: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if match[3] == "up" {
+			migration.Up = string(data)
+		} else {
+			migration.Down = string(data)
+		}
+	}
+
+	migrator := &Migrator{db: db}
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d has no up file", migration.Version)
+		}
+		migrator.migrations = append(migrator.migrations, *migration)
+	}
+	sort.Slice(migrator.migrations, func(i, j int) bool {
+		return migrator.migrations[i].Version < migrator.migrations[j].Version
+	})
+	return migrator, nil
+}
+
+// Status describes the schema version of a database.
+type Status struct {
+	Version    uint64 // 0 when no migration has been applied
+	Dirty      bool
+	Migrations []Migration
+}
+
+// Pending returns the migrations newer than the current version.
+func (status Status) Pending() []Migration {
+	var pending []Migration
+	for _, migration := range status.Migrations {
+		if migration.Version > status.Version {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
+
+// Status reports the current version and the known migrations.
+func (migrator *Migrator) Status(ctx context.Context) (Status, error) {
+	status := Status{Migrations: migrator.migrations}
+	err := migrator.withLock(ctx, func(conn *sql.Conn) error {
+		var err error
+		status.Version, status.Dirty, err = version(ctx, conn)
+		return err
+	})
+	return status, err
+}
+
+// Up applies every pending migration.
+func (migrator *Migrator) Up(ctx context.Context) error {
+	if len(migrator.migrations) == 0 {
+		return ErrNoChange
+	}
+	return migrator.Goto(ctx, migrator.migrations[len(migrator.migrations)-1].Version)
+}
+
+// Down reverts the given number of applied migrations.
+func (migrator *Migrator) Down(ctx context.Context, steps int) error {
+	return migrator.withLock(ctx, func(conn *sql.Conn) error {
+		current, dirty, err := version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return ErrDirty
+		}
+
+		index := migrator.index(current)
+		if current != 0 && index < 0 {
+			return fmt.Errorf("%w: database is at %d", ErrUnknownVersion, current)
+		}
+
+		target := uint64(0)
+		if index-steps >= 0 {
+			target = migrator.migrations[index-steps].Version
+		}
+		return migrator.migrate(ctx, conn, current, target)
+	})
+}
+
+// Goto migrates up or down to the given version. Version 0 reverts every
+// migration.
+func (migrator *Migrator) Goto(ctx context.Context, target uint64) error {
+	if target != 0 && migrator.index(target) < 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, target)
+	}
+
+	return migrator.withLock(ctx, func(conn *sql.Conn) error {
+		current, dirty, err := version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return ErrDirty
+		}
+		if current != 0 && migrator.index(current) < 0 {
+			return fmt.Errorf("%w: database is at %d", ErrUnknownVersion, current)
+		}
+		return migrator.migrate(ctx, conn, current, target)
+	})
+}
+
+// Force records the given version as applied and clean without running any
+// migration. It is used to recover after fixing a failed migration by hand.
+func (migrator *Migrator) Force(ctx context.Context, target uint64) error {
+	if target != 0 && migrator.index(target) < 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, target)
+	}
+
+	return migrator.withLock(ctx, func(conn *sql.Conn) error {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if err := setVersion(ctx, tx, target, false); err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
+			}
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// migrate applies the migrations between current and target, one transaction
+// per migration, recording each new version as it goes.
+func (migrator *Migrator) migrate(ctx context.Context, conn *sql.Conn, current, target uint64) error {
+	if current == target {
+		return ErrNoChange
+	}
+
+	if target > current {
+		for _, migration := range migrator.migrations {
+			if migration.Version <= current || migration.Version > target {
+				continue
+			}
+			if err := apply(ctx, conn, migration.Up, migration.Version); err != nil {
+				return fmt.Errorf("migration %d_%s up failed: %w", migration.Version, migration.Name, err)
+			}
+		}
+		return nil
+	}
+
+	for i := migrator.index(current); i >= 0 && migrator.migrations[i].Version > target; i-- {
+		migration := migrator.migrations[i]
+		if migration.Down == "" {
+			return fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+		}
+
+		previous := uint64(0)
+		if i > 0 {
+			previous = migrator.migrations[i-1].Version
+		}
+		if err := apply(ctx, conn, migration.Down, previous); err != nil {
+			return fmt.Errorf("migration %d_%s down failed: %w", migration.Version, migration.Name, err)
+		}
+	}
+	return nil
+}
+
+// index returns the position of a version in the sorted migrations, or -1.
+func (migrator *Migrator) index(version uint64) int {
+	for i, migration := range migrator.migrations {
+		if migration.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// withLock runs fn on a single connection holding the migration lock.
+func (migrator *Migrator) withLock(ctx context.Context, fn func(*sql.Conn) error) error {
+	conn, err := migrator.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return fmt.Errorf("cannot acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockID)
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS "schema_migrations" (
+  "version" bigint PRIMARY KEY,
+  "dirty" boolean NOT NULL
+)`)
+	if err != nil {
+		return fmt.Errorf("cannot create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// version reads the current schema version.
+func version(ctx context.Context, conn *sql.Conn) (uint64, bool, error) {
+	var version int64
+	var dirty bool
+	err := conn.QueryRowContext(ctx, `SELECT "version", "dirty" FROM "schema_migrations" LIMIT 1`).Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("cannot read schema version: %w", err)
+	}
+	return uint64(version), dirty, nil
+}
+
+// apply runs a migration script and records the resulting version in the
+// same transaction, so a failed script leaves the version untouched.
+func apply(ctx context.Context, conn *sql.Conn, script string, version uint64) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = exec(ctx, tx, script, version)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func exec(ctx context.Context, tx *sql.Tx, script string, version uint64) error {
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	return setVersion(ctx, tx, version, false)
+}
+
+// setVersion replaces the recorded schema version. Version 0 clears it.
+func setVersion(ctx context.Context, tx *sql.Tx, version uint64, dirty bool) error {
+	if _, err := tx.ExecContext(ctx, `TRUNCATE "schema_migrations"`); err != nil {
+		return err
+	}
+	if version == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO "schema_migrations" ("version", "dirty") VALUES ($1, $2)`, int64(version), dirty)
+	return err
+}
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "bookedVenues_venue_id_booked_for_idx";
+
+ALTER TABLE "purchases" DROP COLUMN IF EXISTS "booked_venue_id";
+ALTER TABLE "purchases" DROP COLUMN IF EXISTS "amount";
@@ -0,0 +1,7 @@
+-- Purchases record what was paid and which booking they pay for
+ALTER TABLE "purchases" ADD COLUMN "amount" integer;
+ALTER TABLE "purchases" ADD COLUMN "booked_venue_id" uuid; -- This is the foreign key column in 'purchases'
+
+ALTER TABLE "purchases" ADD FOREIGN KEY ("booked_venue_id") REFERENCES "bookedVenues" ("id");
+
+CREATE INDEX ON "bookedVenues" ("venue_id", "booked_for");
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "bookedPractitioners_service_id_booked_for_idx";
+
+ALTER TABLE "purchases" DROP COLUMN IF EXISTS "booked_practitioner_id";
@@ -0,0 +1,6 @@
+-- Purchases can pay for a practitioner appointment
+ALTER TABLE "purchases" ADD COLUMN "booked_practitioner_id" uuid; -- This is the foreign key column in 'purchases'
+
+ALTER TABLE "purchases" ADD FOREIGN KEY ("booked_practitioner_id") REFERENCES "bookedPractitioners" ("id");
+
+CREATE INDEX ON "bookedPractitioners" ("service_id", "booked_for");
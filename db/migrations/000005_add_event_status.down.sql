@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "events_status_start_date_idx";
+
+ALTER TABLE "events" DROP CONSTRAINT IF EXISTS "events_status_check";
+ALTER TABLE "events" DROP COLUMN IF EXISTS "status";
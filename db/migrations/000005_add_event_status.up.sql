@@ -0,0 +1,7 @@
+-- Events move through draft -> published -> completed, or are cancelled
+ALTER TABLE "events" ADD COLUMN "status" varchar(20) NOT NULL DEFAULT ('draft');
+
+ALTER TABLE "events" ADD CONSTRAINT "events_status_check"
+  CHECK ("status" IN ('draft', 'published', 'cancelled', 'completed'));
+
+CREATE INDEX ON "events" ("status", "start_date");
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "purchases_event_id_idx";
+
+DROP TABLE IF EXISTS "tickets";
+DROP TABLE IF EXISTS "ticket_tiers";
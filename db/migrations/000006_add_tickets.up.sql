@@ -0,0 +1,29 @@
+CREATE TABLE "ticket_tiers" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid ()),
+  "event_id" uuid NOT NULL, -- This is the foreign key column in 'ticket_tiers'
+  "name" varchar(255) NOT NULL,
+  "price" integer NOT NULL DEFAULT (0),
+  "quantity" integer NOT NULL,
+  "created_at" timestamp DEFAULT (now()),
+  UNIQUE ("event_id", "name"),
+  CHECK ("price" >= 0),
+  CHECK ("quantity" >= 0)
+);
+
+CREATE TABLE "tickets" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid ()),
+  "purchase_id" uuid NOT NULL, -- This is the foreign key column in 'tickets'
+  "tier_id" uuid NOT NULL,     -- This is the foreign key column in 'tickets'
+  "code" varchar(32) UNIQUE NOT NULL,
+  "created_at" timestamp DEFAULT (now())
+);
+
+-- A ticket tier belongs to an event
+ALTER TABLE "ticket_tiers" ADD FOREIGN KEY ("event_id") REFERENCES "events" ("id");
+
+-- A ticket is paid for by a purchase and admits to a tier
+ALTER TABLE "tickets" ADD FOREIGN KEY ("purchase_id") REFERENCES "purchases" ("id");
+ALTER TABLE "tickets" ADD FOREIGN KEY ("tier_id") REFERENCES "ticket_tiers" ("id");
+
+CREATE INDEX ON "tickets" ("tier_id");
+CREATE INDEX ON "purchases" ("event_id");
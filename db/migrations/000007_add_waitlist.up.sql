@@ -0,0 +1,40 @@
+CREATE TABLE "waitlist_entries" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid ()),
+  "event_id" uuid,            -- This is the foreign key column in 'waitlist_entries'
+  "venue_id" uuid,            -- This is the foreign key column in 'waitlist_entries'
+  "booked_for" date,          -- The venue day being waited for
+  "profile_id" uuid NOT NULL, -- This is the foreign key column in 'waitlist_entries'
+  "status" varchar(20) NOT NULL DEFAULT ('waiting'),
+  "promoted_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  CHECK ("status" IN ('waiting', 'promoted', 'withdrawn')),
+  CHECK (("event_id" IS NULL) <> ("venue_id" IS NULL)),
+  CHECK ("venue_id" IS NULL OR "booked_for" IS NOT NULL)
+);
+
+CREATE TABLE "notifications" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid ()),
+  "profile_id" uuid NOT NULL, -- This is the foreign key column in 'notifications'
+  "kind" varchar(50) NOT NULL,
+  "message" varchar(255) NOT NULL,
+  "waitlist_entry_id" uuid,   -- This is the foreign key column in 'notifications'
+  "read_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+-- Waitlist entries queue a profile for an event or for a venue day
+ALTER TABLE "waitlist_entries" ADD FOREIGN KEY ("event_id") REFERENCES "events" ("id");
+ALTER TABLE "waitlist_entries" ADD FOREIGN KEY ("venue_id") REFERENCES "venues" ("id");
+ALTER TABLE "waitlist_entries" ADD FOREIGN KEY ("profile_id") REFERENCES "profiles" ("id");
+
+-- Notifications are addressed to a profile
+ALTER TABLE "notifications" ADD FOREIGN KEY ("profile_id") REFERENCES "profiles" ("id");
+ALTER TABLE "notifications" ADD FOREIGN KEY ("waitlist_entry_id") REFERENCES "waitlist_entries" ("id");
+
+-- A profile waits at most once per event or venue day
+CREATE UNIQUE INDEX "waitlist_entries_event_waiting_idx" ON "waitlist_entries" ("event_id", "profile_id")
+  WHERE "status" = 'waiting' AND "event_id" IS NOT NULL;
+CREATE UNIQUE INDEX "waitlist_entries_venue_waiting_idx" ON "waitlist_entries" ("venue_id", "booked_for", "profile_id")
+  WHERE "status" = 'waiting' AND "venue_id" IS NOT NULL;
+
+CREATE INDEX ON "notifications" ("profile_id", "created_at");
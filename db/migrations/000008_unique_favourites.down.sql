@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "favourites_added_by_created_at_idx";
+
+ALTER TABLE "favourites" DROP CONSTRAINT IF EXISTS "favourites_event_id_added_by_key";
@@ -0,0 +1,10 @@
+-- Keep a single favourite per profile and event before adding the constraint
+DELETE FROM "favourites" a
+USING "favourites" b
+WHERE a.event_id = b.event_id
+  AND a.added_by = b.added_by
+  AND (a.created_at, a.id) > (b.created_at, b.id);
+
+ALTER TABLE "favourites" ADD CONSTRAINT "favourites_event_id_added_by_key" UNIQUE ("event_id", "added_by");
+
+CREATE INDEX ON "favourites" ("added_by", "created_at");
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "profiles_users_users_id_idx";
+
+ALTER TABLE "profiles_users" DROP CONSTRAINT IF EXISTS "profiles_users_member_role_check";
+ALTER TABLE "profiles_users" DROP COLUMN IF EXISTS "created_at";
+ALTER TABLE "profiles_users" DROP COLUMN IF EXISTS "member_role";
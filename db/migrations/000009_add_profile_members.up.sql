@@ -0,0 +1,8 @@
+-- Business profiles can be shared by several users with different member roles
+ALTER TABLE "profiles_users" ADD COLUMN "member_role" varchar(20) NOT NULL DEFAULT ('owner');
+ALTER TABLE "profiles_users" ADD COLUMN "created_at" timestamp NOT NULL DEFAULT (now());
+
+ALTER TABLE "profiles_users" ADD CONSTRAINT "profiles_users_member_role_check"
+  CHECK ("member_role" IN ('owner', 'manager', 'staff'));
+
+CREATE INDEX ON "profiles_users" ("users_id");
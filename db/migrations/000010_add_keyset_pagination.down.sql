@@ -0,0 +1,20 @@
+DROP INDEX IF EXISTS "purchases_purchased_by_created_at_id_idx";
+DROP INDEX IF EXISTS "favourites_added_by_created_at_id_idx";
+DROP INDEX IF EXISTS "events_created_at_id_idx";
+DROP INDEX IF EXISTS "events_start_date_id_idx";
+DROP INDEX IF EXISTS "profiles_created_at_id_idx";
+DROP INDEX IF EXISTS "practitioners_created_at_id_idx";
+DROP INDEX IF EXISTS "practitioners_name_id_idx";
+DROP INDEX IF EXISTS "venues_created_at_id_idx";
+DROP INDEX IF EXISTS "venues_name_id_idx";
+DROP INDEX IF EXISTS "users_created_at_id_idx";
+
+ALTER TABLE "ticket_tiers" ALTER COLUMN "created_at" DROP NOT NULL;
+ALTER TABLE "purchases" ALTER COLUMN "created_at" DROP NOT NULL;
+ALTER TABLE "favourites" ALTER COLUMN "created_at" DROP NOT NULL;
+ALTER TABLE "events" ALTER COLUMN "start_date" DROP NOT NULL;
+ALTER TABLE "events" ALTER COLUMN "created_at" DROP NOT NULL;
+ALTER TABLE "profiles" ALTER COLUMN "created_at" DROP NOT NULL;
+ALTER TABLE "practitioners" ALTER COLUMN "created_at" DROP NOT NULL;
+ALTER TABLE "venues" ALTER COLUMN "created_at" DROP NOT NULL;
+ALTER TABLE "users" ALTER COLUMN "created_at" DROP NOT NULL;
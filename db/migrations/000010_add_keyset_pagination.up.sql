@@ -0,0 +1,31 @@
+-- Keyset pagination orders by (sort key, id), so the sort keys must not be NULL
+UPDATE "users" SET "created_at" = now() WHERE "created_at" IS NULL;
+UPDATE "venues" SET "created_at" = now() WHERE "created_at" IS NULL;
+UPDATE "practitioners" SET "created_at" = now() WHERE "created_at" IS NULL;
+UPDATE "profiles" SET "created_at" = now() WHERE "created_at" IS NULL;
+UPDATE "events" SET "created_at" = now() WHERE "created_at" IS NULL;
+UPDATE "events" SET "start_date" = COALESCE("start_time"::date, "created_at"::date) WHERE "start_date" IS NULL;
+UPDATE "favourites" SET "created_at" = now() WHERE "created_at" IS NULL;
+UPDATE "purchases" SET "created_at" = now() WHERE "created_at" IS NULL;
+UPDATE "ticket_tiers" SET "created_at" = now() WHERE "created_at" IS NULL;
+
+ALTER TABLE "users" ALTER COLUMN "created_at" SET NOT NULL;
+ALTER TABLE "venues" ALTER COLUMN "created_at" SET NOT NULL;
+ALTER TABLE "practitioners" ALTER COLUMN "created_at" SET NOT NULL;
+ALTER TABLE "profiles" ALTER COLUMN "created_at" SET NOT NULL;
+ALTER TABLE "events" ALTER COLUMN "created_at" SET NOT NULL;
+ALTER TABLE "events" ALTER COLUMN "start_date" SET NOT NULL;
+ALTER TABLE "favourites" ALTER COLUMN "created_at" SET NOT NULL;
+ALTER TABLE "purchases" ALTER COLUMN "created_at" SET NOT NULL;
+ALTER TABLE "ticket_tiers" ALTER COLUMN "created_at" SET NOT NULL;
+
+CREATE INDEX ON "users" ("created_at", "id");
+CREATE INDEX ON "venues" ("name", "id");
+CREATE INDEX ON "venues" ("created_at", "id");
+CREATE INDEX ON "practitioners" ("name", "id");
+CREATE INDEX ON "practitioners" ("created_at", "id");
+CREATE INDEX ON "profiles" ("created_at", "id");
+CREATE INDEX ON "events" ("start_date", "id");
+CREATE INDEX ON "events" ("created_at", "id");
+CREATE INDEX ON "favourites" ("added_by", "created_at", "id");
+CREATE INDEX ON "purchases" ("purchased_by", "created_at", "id");
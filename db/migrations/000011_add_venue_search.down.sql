@@ -0,0 +1,8 @@
+DROP FUNCTION IF EXISTS "weekdays_include"(text, date);
+
+DROP INDEX IF EXISTS "venues_facilities_idx";
+DROP INDEX IF EXISTS "venues_has_accomodation_room_type_bed_type_idx";
+DROP INDEX IF EXISTS "venues_booking_price_idx";
+DROP INDEX IF EXISTS "venues_rent_idx";
+DROP INDEX IF EXISTS "venues_capacity_idx";
+DROP INDEX IF EXISTS "venues_type_idx";
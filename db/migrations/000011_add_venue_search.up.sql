@@ -0,0 +1,37 @@
+-- Indexes backing the faceted venue search
+CREATE INDEX ON "venues" ("type");
+CREATE INDEX ON "venues" ("capacity");
+CREATE INDEX ON "venues" ("rent");
+CREATE INDEX ON "venues" ("booking_price");
+CREATE INDEX ON "venues" ("has_accomodation", "room_type", "bed_type");
+CREATE INDEX ON "venues" USING GIN ("facilities");
+
+-- weekdays_include mirrors util.ParseWeekdays: it reports whether a day list
+-- such as 'mon,wed', 'Monday-Friday' or 'fri-mon' includes the weekday of a date.
+-- An empty list or 'daily' means every day.
+CREATE FUNCTION "weekdays_include"("days" text, "day" date) RETURNS boolean
+LANGUAGE plpgsql IMMUTABLE AS $$
+DECLARE
+  names text[] := ARRAY['sun', 'mon', 'tue', 'wed', 'thu', 'fri', 'sat'];
+  target int := extract(dow FROM day);
+  field text;
+  from_day int;
+  to_day int;
+BEGIN
+  days := lower(trim(coalesce(days, '')));
+  IF days IN ('', 'daily', 'everyday') THEN
+    RETURN true;
+  END IF;
+
+  FOREACH field IN ARRAY regexp_split_to_array(days, '[,; ]+') LOOP
+    CONTINUE WHEN field = '';
+    from_day := array_position(names, left(split_part(field, '-', 1), 3)) - 1;
+    to_day := array_position(names, left(coalesce(nullif(split_part(field, '-', 2), ''), field), 3)) - 1;
+    CONTINUE WHEN from_day IS NULL OR to_day IS NULL;
+    IF (target - from_day + 7) % 7 <= (to_day - from_day + 7) % 7 THEN
+      RETURN true;
+    END IF;
+  END LOOP;
+  RETURN false;
+END
+$$;
@@ -0,0 +1,5 @@
+ALTER TABLE "events" DROP COLUMN IF EXISTS "search_vector";
+ALTER TABLE "practitioners" DROP COLUMN IF EXISTS "search_vector";
+ALTER TABLE "venues" DROP COLUMN IF EXISTS "search_vector";
+
+DROP FUNCTION IF EXISTS "immutable_array_to_string"(varchar[]);
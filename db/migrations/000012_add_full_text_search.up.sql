@@ -0,0 +1,26 @@
+-- array_to_string is only STABLE, so generated columns need an IMMUTABLE wrapper
+CREATE FUNCTION "immutable_array_to_string"("arr" varchar[]) RETURNS text
+LANGUAGE sql IMMUTABLE AS $$
+  SELECT array_to_string(arr, ' ')
+$$;
+
+ALTER TABLE "venues" ADD COLUMN "search_vector" tsvector NOT NULL GENERATED ALWAYS AS (
+  setweight(to_tsvector('english', coalesce("name", '')), 'A') ||
+  setweight(to_tsvector('english', coalesce("type", '') || ' ' || coalesce("location", '')), 'B') ||
+  setweight(to_tsvector('english', coalesce("description", '') || ' ' || coalesce(immutable_array_to_string("facilities"), '')), 'C')
+) STORED;
+
+ALTER TABLE "practitioners" ADD COLUMN "search_vector" tsvector NOT NULL GENERATED ALWAYS AS (
+  setweight(to_tsvector('english', coalesce("name", '')), 'A') ||
+  setweight(to_tsvector('english', coalesce("description", '')), 'C')
+) STORED;
+
+ALTER TABLE "events" ADD COLUMN "search_vector" tsvector NOT NULL GENERATED ALWAYS AS (
+  setweight(to_tsvector('english', coalesce("name", '')), 'A') ||
+  setweight(to_tsvector('english', coalesce("theme", '') || ' ' || coalesce(immutable_array_to_string("activities"), '')), 'B') ||
+  setweight(to_tsvector('english', coalesce("description", '') || ' ' || coalesce("audience", '')), 'C')
+) STORED;
+
+CREATE INDEX ON "venues" USING GIN ("search_vector");
+CREATE INDEX ON "practitioners" USING GIN ("search_vector");
+CREATE INDEX ON "events" USING GIN ("search_vector");
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "venues_latitude_longitude_idx";
+
+ALTER TABLE "venues" DROP CONSTRAINT IF EXISTS "venues_coordinates_check";
+ALTER TABLE "venues" DROP COLUMN IF EXISTS "longitude";
+ALTER TABLE "venues" DROP COLUMN IF EXISTS "latitude";
@@ -0,0 +1,11 @@
+-- Venues are located by latitude/longitude in decimal degrees
+ALTER TABLE "venues" ADD COLUMN "latitude" double precision;
+ALTER TABLE "venues" ADD COLUMN "longitude" double precision;
+
+ALTER TABLE "venues" ADD CONSTRAINT "venues_coordinates_check" CHECK (
+  ("latitude" IS NULL) = ("longitude" IS NULL)
+  AND ("latitude" IS NULL OR "latitude" BETWEEN -90 AND 90)
+  AND ("longitude" IS NULL OR "longitude" BETWEEN -180 AND 180)
+);
+
+CREATE INDEX ON "venues" ("latitude", "longitude");
@@ -0,0 +1,71 @@
+ALTER TABLE "purchases" DROP CONSTRAINT IF EXISTS "purchases_amount_check";
+ALTER TABLE "events" DROP CONSTRAINT IF EXISTS "events_dates_check";
+ALTER TABLE "events" DROP CONSTRAINT IF EXISTS "events_total_particpant_check";
+ALTER TABLE "practitioners" DROP CONSTRAINT IF EXISTS "practitioners_hours_check";
+ALTER TABLE "venues" DROP CONSTRAINT IF EXISTS "venues_hours_check";
+ALTER TABLE "venues" DROP CONSTRAINT IF EXISTS "venues_booking_price_check";
+ALTER TABLE "venues" DROP CONSTRAINT IF EXISTS "venues_rent_check";
+ALTER TABLE "venues" DROP CONSTRAINT IF EXISTS "venues_no_of_rooms_check";
+ALTER TABLE "venues" DROP CONSTRAINT IF EXISTS "venues_capacity_check";
+
+ALTER TABLE "venues" DROP CONSTRAINT "venues_owned_by_fkey";
+ALTER TABLE "venues" ADD CONSTRAINT "venues_owned_by_fkey" FOREIGN KEY ("owned_by") REFERENCES "profiles" ("id");
+ALTER TABLE "events" DROP CONSTRAINT "events_created_by_fkey";
+ALTER TABLE "events" ADD CONSTRAINT "events_created_by_fkey" FOREIGN KEY ("created_by") REFERENCES "profiles" ("id");
+ALTER TABLE "events" DROP CONSTRAINT "events_venue_id_fkey";
+ALTER TABLE "events" ADD CONSTRAINT "events_venue_id_fkey" FOREIGN KEY ("venue_id") REFERENCES "venues" ("id");
+ALTER TABLE "profiles_users" DROP CONSTRAINT "profiles_users_profiles_id_fkey";
+ALTER TABLE "profiles_users" ADD CONSTRAINT "profiles_users_profiles_id_fkey" FOREIGN KEY ("profiles_id") REFERENCES "profiles" ("id");
+ALTER TABLE "profiles_users" DROP CONSTRAINT "profiles_users_users_id_fkey";
+ALTER TABLE "profiles_users" ADD CONSTRAINT "profiles_users_users_id_fkey" FOREIGN KEY ("users_id") REFERENCES "users" ("id");
+ALTER TABLE "favourites" DROP CONSTRAINT "favourites_event_id_fkey";
+ALTER TABLE "favourites" ADD CONSTRAINT "favourites_event_id_fkey" FOREIGN KEY ("event_id") REFERENCES "events" ("id");
+ALTER TABLE "favourites" DROP CONSTRAINT "favourites_added_by_fkey";
+ALTER TABLE "favourites" ADD CONSTRAINT "favourites_added_by_fkey" FOREIGN KEY ("added_by") REFERENCES "profiles" ("id");
+ALTER TABLE "bookedVenues" DROP CONSTRAINT "bookedVenues_venue_id_fkey";
+ALTER TABLE "bookedVenues" ADD CONSTRAINT "bookedVenues_venue_id_fkey" FOREIGN KEY ("venue_id") REFERENCES "venues" ("id");
+ALTER TABLE "bookedVenues" DROP CONSTRAINT "bookedVenues_booked_by_fkey";
+ALTER TABLE "bookedVenues" ADD CONSTRAINT "bookedVenues_booked_by_fkey" FOREIGN KEY ("booked_by") REFERENCES "profiles" ("id");
+ALTER TABLE "bookedPractitioners" DROP CONSTRAINT "bookedPractitioners_service_id_fkey";
+ALTER TABLE "bookedPractitioners" ADD CONSTRAINT "bookedPractitioners_service_id_fkey" FOREIGN KEY ("service_id") REFERENCES "practitioners" ("id");
+ALTER TABLE "bookedPractitioners" DROP CONSTRAINT "bookedPractitioners_booked_by_fkey";
+ALTER TABLE "bookedPractitioners" ADD CONSTRAINT "bookedPractitioners_booked_by_fkey" FOREIGN KEY ("booked_by") REFERENCES "profiles" ("id");
+ALTER TABLE "practitioners" DROP CONSTRAINT "practitioners_created_by_fkey";
+ALTER TABLE "practitioners" ADD CONSTRAINT "practitioners_created_by_fkey" FOREIGN KEY ("created_by") REFERENCES "profiles" ("id");
+ALTER TABLE "purchases" DROP CONSTRAINT "purchases_event_id_fkey";
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_event_id_fkey" FOREIGN KEY ("event_id") REFERENCES "events" ("id");
+ALTER TABLE "purchases" DROP CONSTRAINT "purchases_venue_id_fkey";
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_venue_id_fkey" FOREIGN KEY ("venue_id") REFERENCES "venues" ("id");
+ALTER TABLE "purchases" DROP CONSTRAINT "purchases_service_id_fkey";
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_service_id_fkey" FOREIGN KEY ("service_id") REFERENCES "practitioners" ("id");
+ALTER TABLE "purchases" DROP CONSTRAINT "purchases_purchased_by_fkey";
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_purchased_by_fkey" FOREIGN KEY ("purchased_by") REFERENCES "profiles" ("id");
+ALTER TABLE "purchases" DROP CONSTRAINT "purchases_booked_venue_id_fkey";
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_booked_venue_id_fkey" FOREIGN KEY ("booked_venue_id") REFERENCES "bookedVenues" ("id");
+ALTER TABLE "purchases" DROP CONSTRAINT "purchases_booked_practitioner_id_fkey";
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_booked_practitioner_id_fkey" FOREIGN KEY ("booked_practitioner_id") REFERENCES "bookedPractitioners" ("id");
+ALTER TABLE "sessions" DROP CONSTRAINT "sessions_user_id_fkey";
+ALTER TABLE "sessions" ADD CONSTRAINT "sessions_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+ALTER TABLE "ticket_tiers" DROP CONSTRAINT "ticket_tiers_event_id_fkey";
+ALTER TABLE "ticket_tiers" ADD CONSTRAINT "ticket_tiers_event_id_fkey" FOREIGN KEY ("event_id") REFERENCES "events" ("id");
+ALTER TABLE "tickets" DROP CONSTRAINT "tickets_purchase_id_fkey";
+ALTER TABLE "tickets" ADD CONSTRAINT "tickets_purchase_id_fkey" FOREIGN KEY ("purchase_id") REFERENCES "purchases" ("id");
+ALTER TABLE "tickets" DROP CONSTRAINT "tickets_tier_id_fkey";
+ALTER TABLE "tickets" ADD CONSTRAINT "tickets_tier_id_fkey" FOREIGN KEY ("tier_id") REFERENCES "ticket_tiers" ("id");
+ALTER TABLE "waitlist_entries" DROP CONSTRAINT "waitlist_entries_event_id_fkey";
+ALTER TABLE "waitlist_entries" ADD CONSTRAINT "waitlist_entries_event_id_fkey" FOREIGN KEY ("event_id") REFERENCES "events" ("id");
+ALTER TABLE "waitlist_entries" DROP CONSTRAINT "waitlist_entries_venue_id_fkey";
+ALTER TABLE "waitlist_entries" ADD CONSTRAINT "waitlist_entries_venue_id_fkey" FOREIGN KEY ("venue_id") REFERENCES "venues" ("id");
+ALTER TABLE "waitlist_entries" DROP CONSTRAINT "waitlist_entries_profile_id_fkey";
+ALTER TABLE "waitlist_entries" ADD CONSTRAINT "waitlist_entries_profile_id_fkey" FOREIGN KEY ("profile_id") REFERENCES "profiles" ("id");
+ALTER TABLE "notifications" DROP CONSTRAINT "notifications_profile_id_fkey";
+ALTER TABLE "notifications" ADD CONSTRAINT "notifications_profile_id_fkey" FOREIGN KEY ("profile_id") REFERENCES "profiles" ("id");
+ALTER TABLE "notifications" DROP CONSTRAINT "notifications_waitlist_entry_id_fkey";
+ALTER TABLE "notifications" ADD CONSTRAINT "notifications_waitlist_entry_id_fkey" FOREIGN KEY ("waitlist_entry_id") REFERENCES "waitlist_entries" ("id");
+
+ALTER TABLE "bookedPractitioners" ALTER COLUMN "booked_for" DROP NOT NULL;
+ALTER TABLE "bookedPractitioners" ALTER COLUMN "booked_by" DROP NOT NULL;
+ALTER TABLE "bookedPractitioners" ALTER COLUMN "service_id" DROP NOT NULL;
+ALTER TABLE "bookedVenues" ALTER COLUMN "booked_for" DROP NOT NULL;
+ALTER TABLE "bookedVenues" ALTER COLUMN "booked_by" DROP NOT NULL;
+ALTER TABLE "bookedVenues" ALTER COLUMN "venue_id" DROP NOT NULL;
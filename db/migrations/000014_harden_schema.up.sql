@@ -0,0 +1,104 @@
+-- Bookings without a subject, a booker or a date are unusable; drop them before
+-- making those columns mandatory
+DELETE FROM "bookedVenues" WHERE "venue_id" IS NULL OR "booked_by" IS NULL OR "booked_for" IS NULL;
+DELETE FROM "bookedPractitioners" WHERE "service_id" IS NULL OR "booked_by" IS NULL OR "booked_for" IS NULL;
+
+ALTER TABLE "bookedVenues" ALTER COLUMN "venue_id" SET NOT NULL;
+ALTER TABLE "bookedVenues" ALTER COLUMN "booked_by" SET NOT NULL;
+ALTER TABLE "bookedVenues" ALTER COLUMN "booked_for" SET NOT NULL;
+ALTER TABLE "bookedPractitioners" ALTER COLUMN "service_id" SET NOT NULL;
+ALTER TABLE "bookedPractitioners" ALTER COLUMN "booked_by" SET NOT NULL;
+ALTER TABLE "bookedPractitioners" ALTER COLUMN "booked_for" SET NOT NULL;
+
+-- ON DELETE behaviour per relation:
+--   CASCADE for rows that only make sense with their parent (memberships,
+--   sessions, favourites, waitlist places, notifications, tiers, tickets),
+--   RESTRICT for anything carrying money or bookings, which must be cancelled first,
+--   SET NULL for optional links.
+
+ALTER TABLE "venues" DROP CONSTRAINT "venues_owned_by_fkey";
+ALTER TABLE "venues" ADD CONSTRAINT "venues_owned_by_fkey" FOREIGN KEY ("owned_by") REFERENCES "profiles" ("id") ON DELETE RESTRICT;
+ALTER TABLE "events" DROP CONSTRAINT "events_created_by_fkey";
+ALTER TABLE "events" ADD CONSTRAINT "events_created_by_fkey" FOREIGN KEY ("created_by") REFERENCES "profiles" ("id") ON DELETE RESTRICT;
+ALTER TABLE "events" DROP CONSTRAINT "events_venue_id_fkey";
+ALTER TABLE "events" ADD CONSTRAINT "events_venue_id_fkey" FOREIGN KEY ("venue_id") REFERENCES "venues" ("id") ON DELETE SET NULL;
+ALTER TABLE "profiles_users" DROP CONSTRAINT "profiles_users_profiles_id_fkey";
+ALTER TABLE "profiles_users" ADD CONSTRAINT "profiles_users_profiles_id_fkey" FOREIGN KEY ("profiles_id") REFERENCES "profiles" ("id") ON DELETE CASCADE;
+ALTER TABLE "profiles_users" DROP CONSTRAINT "profiles_users_users_id_fkey";
+ALTER TABLE "profiles_users" ADD CONSTRAINT "profiles_users_users_id_fkey" FOREIGN KEY ("users_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+ALTER TABLE "favourites" DROP CONSTRAINT "favourites_event_id_fkey";
+ALTER TABLE "favourites" ADD CONSTRAINT "favourites_event_id_fkey" FOREIGN KEY ("event_id") REFERENCES "events" ("id") ON DELETE CASCADE;
+ALTER TABLE "favourites" DROP CONSTRAINT "favourites_added_by_fkey";
+ALTER TABLE "favourites" ADD CONSTRAINT "favourites_added_by_fkey" FOREIGN KEY ("added_by") REFERENCES "profiles" ("id") ON DELETE CASCADE;
+ALTER TABLE "bookedVenues" DROP CONSTRAINT "bookedVenues_venue_id_fkey";
+ALTER TABLE "bookedVenues" ADD CONSTRAINT "bookedVenues_venue_id_fkey" FOREIGN KEY ("venue_id") REFERENCES "venues" ("id") ON DELETE RESTRICT;
+ALTER TABLE "bookedVenues" DROP CONSTRAINT "bookedVenues_booked_by_fkey";
+ALTER TABLE "bookedVenues" ADD CONSTRAINT "bookedVenues_booked_by_fkey" FOREIGN KEY ("booked_by") REFERENCES "profiles" ("id") ON DELETE RESTRICT;
+ALTER TABLE "bookedPractitioners" DROP CONSTRAINT "bookedPractitioners_service_id_fkey";
+ALTER TABLE "bookedPractitioners" ADD CONSTRAINT "bookedPractitioners_service_id_fkey" FOREIGN KEY ("service_id") REFERENCES "practitioners" ("id") ON DELETE RESTRICT;
+ALTER TABLE "bookedPractitioners" DROP CONSTRAINT "bookedPractitioners_booked_by_fkey";
+ALTER TABLE "bookedPractitioners" ADD CONSTRAINT "bookedPractitioners_booked_by_fkey" FOREIGN KEY ("booked_by") REFERENCES "profiles" ("id") ON DELETE RESTRICT;
+ALTER TABLE "practitioners" DROP CONSTRAINT "practitioners_created_by_fkey";
+ALTER TABLE "practitioners" ADD CONSTRAINT "practitioners_created_by_fkey" FOREIGN KEY ("created_by") REFERENCES "profiles" ("id") ON DELETE RESTRICT;
+ALTER TABLE "purchases" DROP CONSTRAINT "purchases_event_id_fkey";
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_event_id_fkey" FOREIGN KEY ("event_id") REFERENCES "events" ("id") ON DELETE RESTRICT;
+ALTER TABLE "purchases" DROP CONSTRAINT "purchases_venue_id_fkey";
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_venue_id_fkey" FOREIGN KEY ("venue_id") REFERENCES "venues" ("id") ON DELETE RESTRICT;
+ALTER TABLE "purchases" DROP CONSTRAINT "purchases_service_id_fkey";
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_service_id_fkey" FOREIGN KEY ("service_id") REFERENCES "practitioners" ("id") ON DELETE RESTRICT;
+ALTER TABLE "purchases" DROP CONSTRAINT "purchases_purchased_by_fkey";
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_purchased_by_fkey" FOREIGN KEY ("purchased_by") REFERENCES "profiles" ("id") ON DELETE RESTRICT;
+ALTER TABLE "purchases" DROP CONSTRAINT "purchases_booked_venue_id_fkey";
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_booked_venue_id_fkey" FOREIGN KEY ("booked_venue_id") REFERENCES "bookedVenues" ("id") ON DELETE SET NULL;
+ALTER TABLE "purchases" DROP CONSTRAINT "purchases_booked_practitioner_id_fkey";
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_booked_practitioner_id_fkey" FOREIGN KEY ("booked_practitioner_id") REFERENCES "bookedPractitioners" ("id") ON DELETE SET NULL;
+ALTER TABLE "sessions" DROP CONSTRAINT "sessions_user_id_fkey";
+ALTER TABLE "sessions" ADD CONSTRAINT "sessions_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+ALTER TABLE "ticket_tiers" DROP CONSTRAINT "ticket_tiers_event_id_fkey";
+ALTER TABLE "ticket_tiers" ADD CONSTRAINT "ticket_tiers_event_id_fkey" FOREIGN KEY ("event_id") REFERENCES "events" ("id") ON DELETE CASCADE;
+ALTER TABLE "tickets" DROP CONSTRAINT "tickets_purchase_id_fkey";
+ALTER TABLE "tickets" ADD CONSTRAINT "tickets_purchase_id_fkey" FOREIGN KEY ("purchase_id") REFERENCES "purchases" ("id") ON DELETE CASCADE;
+ALTER TABLE "tickets" DROP CONSTRAINT "tickets_tier_id_fkey";
+ALTER TABLE "tickets" ADD CONSTRAINT "tickets_tier_id_fkey" FOREIGN KEY ("tier_id") REFERENCES "ticket_tiers" ("id") ON DELETE RESTRICT;
+ALTER TABLE "waitlist_entries" DROP CONSTRAINT "waitlist_entries_event_id_fkey";
+ALTER TABLE "waitlist_entries" ADD CONSTRAINT "waitlist_entries_event_id_fkey" FOREIGN KEY ("event_id") REFERENCES "events" ("id") ON DELETE CASCADE;
+ALTER TABLE "waitlist_entries" DROP CONSTRAINT "waitlist_entries_venue_id_fkey";
+ALTER TABLE "waitlist_entries" ADD CONSTRAINT "waitlist_entries_venue_id_fkey" FOREIGN KEY ("venue_id") REFERENCES "venues" ("id") ON DELETE CASCADE;
+ALTER TABLE "waitlist_entries" DROP CONSTRAINT "waitlist_entries_profile_id_fkey";
+ALTER TABLE "waitlist_entries" ADD CONSTRAINT "waitlist_entries_profile_id_fkey" FOREIGN KEY ("profile_id") REFERENCES "profiles" ("id") ON DELETE CASCADE;
+ALTER TABLE "notifications" DROP CONSTRAINT "notifications_profile_id_fkey";
+ALTER TABLE "notifications" ADD CONSTRAINT "notifications_profile_id_fkey" FOREIGN KEY ("profile_id") REFERENCES "profiles" ("id") ON DELETE CASCADE;
+ALTER TABLE "notifications" DROP CONSTRAINT "notifications_waitlist_entry_id_fkey";
+ALTER TABLE "notifications" ADD CONSTRAINT "notifications_waitlist_entry_id_fkey" FOREIGN KEY ("waitlist_entry_id") REFERENCES "waitlist_entries" ("id") ON DELETE SET NULL;
+
+-- Sanity checks on quantities, prices and time ranges. Opening hours only use
+-- the time of day of opens_at/closes_at. Existing rows that would fail them
+-- are repaired first: impossible quantities and prices become unknown,
+-- venues and practitioners with inverted hours lose them and are taken off
+-- booking until their owner sets them again, and events ending before they
+-- start end on their first day.
+UPDATE "venues" SET "capacity" = NULL WHERE "capacity" < 0;
+UPDATE "venues" SET "no_of_rooms" = NULL WHERE "no_of_rooms" < 0;
+UPDATE "venues" SET "rent" = NULL WHERE "rent" < 0;
+UPDATE "venues" SET "booking_price" = NULL WHERE "booking_price" < 0;
+UPDATE "venues" SET "opens_at" = NULL, "closes_at" = NULL, "is_available" = false
+WHERE "opens_at"::time >= "closes_at"::time;
+UPDATE "practitioners" SET "opens_at" = NULL, "closes_at" = NULL, "is_available" = false
+WHERE "opens_at"::time >= "closes_at"::time;
+UPDATE "events" SET "total_particpant" = NULL WHERE "total_particpant" < 0;
+UPDATE "events" SET "end_date" = "start_date" WHERE "end_date" < "start_date";
+
+ALTER TABLE "venues" ADD CONSTRAINT "venues_capacity_check" CHECK ("capacity" >= 0);
+ALTER TABLE "venues" ADD CONSTRAINT "venues_no_of_rooms_check" CHECK ("no_of_rooms" >= 0);
+ALTER TABLE "venues" ADD CONSTRAINT "venues_rent_check" CHECK ("rent" >= 0);
+ALTER TABLE "venues" ADD CONSTRAINT "venues_booking_price_check" CHECK ("booking_price" >= 0);
+ALTER TABLE "venues" ADD CONSTRAINT "venues_hours_check" CHECK ("opens_at"::time < "closes_at"::time);
+ALTER TABLE "practitioners" ADD CONSTRAINT "practitioners_hours_check" CHECK ("opens_at"::time < "closes_at"::time);
+ALTER TABLE "events" ADD CONSTRAINT "events_total_particpant_check" CHECK ("total_particpant" >= 0);
+ALTER TABLE "events" ADD CONSTRAINT "events_dates_check" CHECK ("end_date" >= "start_date");
+
+-- Purchases are financial records and are not rewritten. The amount check
+-- only applies to new rows until the old ones have been reviewed, after which
+-- it can be validated with
+--   ALTER TABLE "purchases" VALIDATE CONSTRAINT "purchases_amount_check";
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_amount_check" CHECK ("amount" >= 0) NOT VALID;
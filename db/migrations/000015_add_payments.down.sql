@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS "payment_events";
+
+DROP INDEX IF EXISTS "purchases_provider_provider_checkout_id_idx";
+
+ALTER TABLE "purchases" DROP CONSTRAINT IF EXISTS "purchases_provider_check";
+ALTER TABLE "purchases" DROP CONSTRAINT IF EXISTS "purchases_status_check";
+ALTER TABLE "purchases" DROP COLUMN IF EXISTS "paid_at";
+ALTER TABLE "purchases" DROP COLUMN IF EXISTS "provider_payment_id";
+ALTER TABLE "purchases" DROP COLUMN IF EXISTS "provider_checkout_id";
+ALTER TABLE "purchases" DROP COLUMN IF EXISTS "provider";
+ALTER TABLE "purchases" DROP COLUMN IF EXISTS "status";
+ALTER TABLE "purchases" DROP COLUMN IF EXISTS "currency";
@@ -0,0 +1,27 @@
+-- Purchases are paid through a payment provider. Purchases made before
+-- payments existed are treated as paid.
+ALTER TABLE "purchases" ADD COLUMN "currency" char(3) NOT NULL DEFAULT ('USD');
+ALTER TABLE "purchases" ADD COLUMN "status" varchar(20) NOT NULL DEFAULT ('paid');
+ALTER TABLE "purchases" ALTER COLUMN "status" SET DEFAULT ('pending');
+ALTER TABLE "purchases" ADD COLUMN "provider" varchar(50);
+ALTER TABLE "purchases" ADD COLUMN "provider_checkout_id" varchar(255);
+ALTER TABLE "purchases" ADD COLUMN "provider_payment_id" varchar(255);
+ALTER TABLE "purchases" ADD COLUMN "paid_at" timestamp;
+
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_status_check"
+  CHECK ("status" IN ('pending', 'paid', 'failed'));
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_provider_check"
+  CHECK ("provider_checkout_id" IS NULL OR "provider" IS NOT NULL);
+
+-- Webhooks look purchases up by the checkout they were paid through
+CREATE INDEX ON "purchases" ("provider", "provider_checkout_id");
+
+-- Every webhook event applied, so redelivered events are ignored
+CREATE TABLE "payment_events" (
+  "provider" varchar(50) NOT NULL,
+  "event_id" varchar(255) NOT NULL,
+  "type" varchar(50) NOT NULL,
+  "checkout_id" varchar(255) NOT NULL,
+  "received_at" timestamp NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("provider", "event_id")
+);
@@ -0,0 +1,36 @@
+DROP INDEX IF EXISTS "purchases_booked_practitioner_id_idx";
+DROP INDEX IF EXISTS "purchases_booked_venue_id_idx";
+DROP TABLE IF EXISTS "cancellations";
+
+ALTER TABLE "events" DROP CONSTRAINT IF EXISTS "events_cancellation_policy_check";
+ALTER TABLE "events" DROP COLUMN IF EXISTS "partial_refund_percent";
+ALTER TABLE "events" DROP COLUMN IF EXISTS "no_refund_hours";
+ALTER TABLE "events" DROP COLUMN IF EXISTS "full_refund_hours";
+
+ALTER TABLE "practitioners" DROP CONSTRAINT IF EXISTS "practitioners_cancellation_policy_check";
+ALTER TABLE "practitioners" DROP COLUMN IF EXISTS "partial_refund_percent";
+ALTER TABLE "practitioners" DROP COLUMN IF EXISTS "no_refund_hours";
+ALTER TABLE "practitioners" DROP COLUMN IF EXISTS "full_refund_hours";
+
+ALTER TABLE "venues" DROP CONSTRAINT IF EXISTS "venues_cancellation_policy_check";
+ALTER TABLE "venues" DROP COLUMN IF EXISTS "partial_refund_percent";
+ALTER TABLE "venues" DROP COLUMN IF EXISTS "no_refund_hours";
+ALTER TABLE "venues" DROP COLUMN IF EXISTS "full_refund_hours";
+
+-- Cancelled purchases cannot be represented any more; drop them as the old code did
+DELETE FROM "tickets" WHERE "purchase_id" IN (SELECT "id" FROM "purchases" WHERE "status" = 'cancelled');
+DELETE FROM "purchases" WHERE "status" = 'cancelled';
+ALTER TABLE "purchases" DROP CONSTRAINT IF EXISTS "purchases_status_check";
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_status_check"
+  CHECK ("status" IN ('pending', 'paid', 'failed'));
+ALTER TABLE "purchases" DROP COLUMN IF EXISTS "cancelled_at";
+
+DELETE FROM "bookedPractitioners" WHERE "status" = 'cancelled';
+ALTER TABLE "bookedPractitioners" DROP CONSTRAINT IF EXISTS "bookedPractitioners_status_check";
+ALTER TABLE "bookedPractitioners" DROP COLUMN IF EXISTS "cancelled_at";
+ALTER TABLE "bookedPractitioners" DROP COLUMN IF EXISTS "status";
+
+DELETE FROM "bookedVenues" WHERE "status" = 'cancelled';
+ALTER TABLE "bookedVenues" DROP CONSTRAINT IF EXISTS "bookedVenues_status_check";
+ALTER TABLE "bookedVenues" DROP COLUMN IF EXISTS "cancelled_at";
+ALTER TABLE "bookedVenues" DROP COLUMN IF EXISTS "status";
@@ -0,0 +1,67 @@
+-- Cancelled bookings and purchases are kept for their history
+ALTER TABLE "bookedVenues" ADD COLUMN "status" varchar(20) NOT NULL DEFAULT ('confirmed');
+ALTER TABLE "bookedVenues" ADD COLUMN "cancelled_at" timestamp;
+ALTER TABLE "bookedVenues" ADD CONSTRAINT "bookedVenues_status_check"
+  CHECK ("status" IN ('confirmed', 'cancelled'));
+
+ALTER TABLE "bookedPractitioners" ADD COLUMN "status" varchar(20) NOT NULL DEFAULT ('confirmed');
+ALTER TABLE "bookedPractitioners" ADD COLUMN "cancelled_at" timestamp;
+ALTER TABLE "bookedPractitioners" ADD CONSTRAINT "bookedPractitioners_status_check"
+  CHECK ("status" IN ('confirmed', 'cancelled'));
+
+ALTER TABLE "purchases" ADD COLUMN "cancelled_at" timestamp;
+ALTER TABLE "purchases" DROP CONSTRAINT "purchases_status_check";
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_status_check"
+  CHECK ("status" IN ('pending', 'paid', 'failed', 'cancelled'));
+
+-- Cancellation policies: cancelling at least full_refund_hours before the
+-- start refunds everything, less than no_refund_hours before refunds nothing,
+-- and in between refunds partial_refund_percent
+ALTER TABLE "venues" ADD COLUMN "full_refund_hours" integer NOT NULL DEFAULT (0);
+ALTER TABLE "venues" ADD COLUMN "no_refund_hours" integer NOT NULL DEFAULT (0);
+ALTER TABLE "venues" ADD COLUMN "partial_refund_percent" integer NOT NULL DEFAULT (100);
+ALTER TABLE "venues" ADD CONSTRAINT "venues_cancellation_policy_check"
+  CHECK ("no_refund_hours" >= 0 AND "full_refund_hours" >= "no_refund_hours"
+    AND "partial_refund_percent" BETWEEN 0 AND 100);
+
+ALTER TABLE "practitioners" ADD COLUMN "full_refund_hours" integer NOT NULL DEFAULT (0);
+ALTER TABLE "practitioners" ADD COLUMN "no_refund_hours" integer NOT NULL DEFAULT (0);
+ALTER TABLE "practitioners" ADD COLUMN "partial_refund_percent" integer NOT NULL DEFAULT (100);
+ALTER TABLE "practitioners" ADD CONSTRAINT "practitioners_cancellation_policy_check"
+  CHECK ("no_refund_hours" >= 0 AND "full_refund_hours" >= "no_refund_hours"
+    AND "partial_refund_percent" BETWEEN 0 AND 100);
+
+ALTER TABLE "events" ADD COLUMN "full_refund_hours" integer NOT NULL DEFAULT (0);
+ALTER TABLE "events" ADD COLUMN "no_refund_hours" integer NOT NULL DEFAULT (0);
+ALTER TABLE "events" ADD COLUMN "partial_refund_percent" integer NOT NULL DEFAULT (100);
+ALTER TABLE "events" ADD CONSTRAINT "events_cancellation_policy_check"
+  CHECK ("no_refund_hours" >= 0 AND "full_refund_hours" >= "no_refund_hours"
+    AND "partial_refund_percent" BETWEEN 0 AND 100);
+
+-- Audit trail of every cancellation and the refund it gave
+CREATE TABLE "cancellations" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid ()),
+  "purchase_id" uuid NOT NULL,     -- This is the foreign key column in 'cancellations'
+  "booked_venue_id" uuid,          -- This is the foreign key column in 'cancellations'
+  "booked_practitioner_id" uuid,   -- This is the foreign key column in 'cancellations'
+  "cancelled_by" uuid NOT NULL,    -- This is the foreign key column in 'cancellations'
+  "reason" varchar(255),
+  "notice_hours" integer NOT NULL, -- hours between the cancellation and the start, negative once started
+  "refund_amount" integer NOT NULL DEFAULT (0),
+  "currency" char(3) NOT NULL,
+  "refund_status" varchar(20) NOT NULL DEFAULT ('none'),
+  "provider_refund_id" varchar(255),
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  CHECK ("refund_amount" >= 0),
+  CHECK ("refund_status" IN ('none', 'pending', 'issued', 'failed'))
+);
+
+ALTER TABLE "cancellations" ADD FOREIGN KEY ("purchase_id") REFERENCES "purchases" ("id");
+ALTER TABLE "cancellations" ADD FOREIGN KEY ("booked_venue_id") REFERENCES "bookedVenues" ("id");
+ALTER TABLE "cancellations" ADD FOREIGN KEY ("booked_practitioner_id") REFERENCES "bookedPractitioners" ("id");
+ALTER TABLE "cancellations" ADD FOREIGN KEY ("cancelled_by") REFERENCES "profiles" ("id");
+
+-- A purchase is cancelled at most once
+CREATE UNIQUE INDEX ON "cancellations" ("purchase_id");
+CREATE INDEX ON "purchases" ("booked_venue_id");
+CREATE INDEX ON "purchases" ("booked_practitioner_id");
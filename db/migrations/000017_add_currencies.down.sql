@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "exchange_rates";
+
+ALTER TABLE "ticket_tiers" DROP COLUMN IF EXISTS "currency";
+ALTER TABLE "venues" DROP COLUMN IF EXISTS "currency";
@@ -0,0 +1,14 @@
+-- Prices are amounts in minor units of an ISO 4217 currency
+ALTER TABLE "venues" ADD COLUMN "currency" char(3) NOT NULL DEFAULT ('USD');
+ALTER TABLE "ticket_tiers" ADD COLUMN "currency" char(3) NOT NULL DEFAULT ('USD');
+
+-- Price of one unit of base in quote, loaded from a CSV file
+CREATE TABLE "exchange_rates" (
+  "base" char(3) NOT NULL,
+  "quote" char(3) NOT NULL,
+  "rate" numeric(20, 10) NOT NULL,
+  "updated_at" timestamp NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("base", "quote"),
+  CHECK ("rate" > 0),
+  CHECK ("base" <> "quote")
+);
@@ -0,0 +1,62 @@
+-- Last invoice number issued by each seller profile. The row is locked while
+-- an invoice is issued, so numbers are handed out without gaps.
+CREATE TABLE "invoice_sequences" (
+  "profile_id" uuid PRIMARY KEY, -- This is the foreign key column in 'invoice_sequences'
+  "last_number" bigint NOT NULL DEFAULT (0),
+  CHECK ("last_number" >= 0)
+);
+
+ALTER TABLE "invoice_sequences" ADD FOREIGN KEY ("profile_id") REFERENCES "profiles" ("id") ON DELETE RESTRICT;
+
+-- Invoices are issued once a purchase is paid. Seller and buyer details are
+-- copied in, so an invoice never changes after it is issued.
+CREATE TABLE "invoices" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid ()),
+  "purchase_id" uuid NOT NULL, -- This is the foreign key column in 'invoices'
+  "seller_id" uuid NOT NULL,   -- This is the foreign key column in 'invoices'
+  "buyer_id" uuid,             -- This is the foreign key column in 'invoices'
+  "sequence" bigint NOT NULL,
+  "number" varchar(64) NOT NULL,
+  "seller_name" varchar(255) NOT NULL,
+  "seller_address" varchar(255),
+  "seller_country" varchar(255),
+  "buyer_name" varchar(255) NOT NULL,
+  "buyer_address" varchar(255),
+  "buyer_country" varchar(255),
+  "currency" char(3) NOT NULL,
+  "subtotal" integer NOT NULL,
+  "tax_amount" integer NOT NULL DEFAULT (0),
+  "total" integer NOT NULL,
+  "issued_at" timestamp NOT NULL DEFAULT (now()),
+  CHECK ("sequence" > 0),
+  CHECK ("subtotal" >= 0),
+  CHECK ("tax_amount" >= 0),
+  CHECK ("total" = "subtotal" + "tax_amount")
+);
+
+ALTER TABLE "invoices" ADD FOREIGN KEY ("purchase_id") REFERENCES "purchases" ("id") ON DELETE RESTRICT;
+ALTER TABLE "invoices" ADD FOREIGN KEY ("seller_id") REFERENCES "profiles" ("id") ON DELETE RESTRICT;
+ALTER TABLE "invoices" ADD FOREIGN KEY ("buyer_id") REFERENCES "profiles" ("id") ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX ON "invoices" ("purchase_id");
+CREATE UNIQUE INDEX ON "invoices" ("seller_id", "sequence");
+
+-- Items sold and the taxes charged on them, in the order they are printed
+CREATE TABLE "invoice_lines" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid ()),
+  "invoice_id" uuid NOT NULL, -- This is the foreign key column in 'invoice_lines'
+  "position" integer NOT NULL,
+  "kind" varchar(20) NOT NULL DEFAULT ('item'),
+  "description" varchar(255) NOT NULL,
+  "quantity" integer NOT NULL DEFAULT (1),
+  "unit_amount" integer NOT NULL,
+  "amount" integer NOT NULL,
+  "tax_rate" numeric(7, 4), -- percent, set on tax lines
+  CHECK ("kind" IN ('item', 'tax')),
+  CHECK ("quantity" > 0),
+  CHECK ("amount" = "unit_amount" * "quantity")
+);
+
+ALTER TABLE "invoice_lines" ADD FOREIGN KEY ("invoice_id") REFERENCES "invoices" ("id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX ON "invoice_lines" ("invoice_id", "position");
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "purchase_taxes";
+
+ALTER TABLE "purchases" DROP CONSTRAINT IF EXISTS "purchases_tax_amount_check";
+ALTER TABLE "purchases" DROP COLUMN IF EXISTS "tax_amount";
@@ -0,0 +1,21 @@
+-- Purchase amounts include tax; tax_amount is the part of it that is tax
+ALTER TABLE "purchases" ADD COLUMN "tax_amount" integer NOT NULL DEFAULT (0);
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_tax_amount_check"
+  CHECK ("tax_amount" >= 0 AND "tax_amount" <= coalesce("amount", 0));
+
+-- Each tax charged on a purchase, in the order they are shown
+CREATE TABLE "purchase_taxes" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid ()),
+  "purchase_id" uuid NOT NULL, -- This is the foreign key column in 'purchase_taxes'
+  "position" integer NOT NULL,
+  "name" varchar(64) NOT NULL,
+  "rate" numeric(7, 4) NOT NULL, -- percent
+  "inclusive" boolean NOT NULL,  -- already included in the price rather than added to it
+  "amount" integer NOT NULL,
+  CHECK ("rate" BETWEEN 0 AND 100),
+  CHECK ("amount" >= 0)
+);
+
+ALTER TABLE "purchase_taxes" ADD FOREIGN KEY ("purchase_id") REFERENCES "purchases" ("id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX ON "purchase_taxes" ("purchase_id", "position");
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "purchases_pending_until_idx";
+
+ALTER TABLE "purchases" DROP CONSTRAINT IF EXISTS "purchases_pending_until_check";
+ALTER TABLE "purchases" DROP COLUMN IF EXISTS "pending_until";
@@ -0,0 +1,11 @@
+-- Pending purchases hold their place until they expire unpaid. Purchases
+-- that were already pending get the default checkout time from now.
+ALTER TABLE "purchases" ADD COLUMN "pending_until" timestamp;
+UPDATE "purchases" SET "pending_until" = now() + interval '30 minutes'
+WHERE "status" = 'pending';
+
+ALTER TABLE "purchases" ADD CONSTRAINT "purchases_pending_until_check"
+  CHECK ("status" <> 'pending' OR "pending_until" IS NOT NULL);
+
+-- Expired purchases are looked up by when they expire
+CREATE INDEX ON "purchases" ("pending_until") WHERE "status" = 'pending';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "waitlist_entries_venue_id_booked_for_hold_until_idx";
+DROP INDEX IF EXISTS "waitlist_entries_event_id_hold_until_idx";
+
+ALTER TABLE "waitlist_entries" DROP COLUMN IF EXISTS "hold_until";
@@ -0,0 +1,6 @@
+-- A promoted profile is held the place it waited for until hold_until.
+-- Holds are cleared once the place is bought.
+ALTER TABLE "waitlist_entries" ADD COLUMN "hold_until" timestamp;
+
+CREATE INDEX ON "waitlist_entries" ("event_id", "hold_until") WHERE "status" = 'promoted';
+CREATE INDEX ON "waitlist_entries" ("venue_id", "booked_for", "hold_until") WHERE "status" = 'promoted';
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS "waitlist_entries_hold_until_idx";
+
+UPDATE "waitlist_entries" SET "status" = 'promoted' WHERE "status" = 'expired';
+
+ALTER TABLE "waitlist_entries" DROP CONSTRAINT "waitlist_entries_status_check";
+ALTER TABLE "waitlist_entries" ADD CONSTRAINT "waitlist_entries_status_check"
+  CHECK ("status" IN ('waiting', 'promoted', 'withdrawn'));
@@ -0,0 +1,8 @@
+-- A hold that lapses before its place is bought expires, and the place goes
+-- to the next profile waiting.
+ALTER TABLE "waitlist_entries" DROP CONSTRAINT "waitlist_entries_status_check";
+ALTER TABLE "waitlist_entries" ADD CONSTRAINT "waitlist_entries_status_check"
+  CHECK ("status" IN ('waiting', 'promoted', 'withdrawn', 'expired'));
+
+-- Lapsed holds are looked up by when they end
+CREATE INDEX ON "waitlist_entries" ("hold_until") WHERE "status" = 'promoted';
@@ -4,13 +4,34 @@ WHERE id = $1 LIMIT 1;
 
 -- name: ListBookedPractitionersByService :many
 SELECT * FROM "bookedPractitioners"
-WHERE service_id = $1
+WHERE service_id = sqlc.arg(service_id)
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (booked_for, id) > (sqlc.narg(cursor_booked_for)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY booked_for, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountBookedPractitionersBetween :one
+SELECT count(*) FROM "bookedPractitioners"
+WHERE service_id = sqlc.arg(service_id)
+  AND booked_for > sqlc.arg(from_time)
+  AND booked_for < sqlc.arg(to_time)
+  AND status = 'confirmed';
+
+-- name: ListBookedPractitionersBetween :many
+SELECT * FROM "bookedPractitioners"
+WHERE service_id = sqlc.arg(service_id)
+  AND booked_for > sqlc.arg(from_time)
+  AND booked_for < sqlc.arg(to_time)
+  AND status = 'confirmed'
 ORDER BY booked_for;
 
 -- name: ListBookedPractitionersByUser :many
 SELECT * FROM "bookedPractitioners"
-WHERE booked_by = $1
-ORDER BY booked_for;
+WHERE booked_by = sqlc.arg(booked_by)
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (booked_for, id) > (sqlc.narg(cursor_booked_for)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY booked_for, id
+LIMIT sqlc.arg(page_limit);
 
 -- name: CreateBookedPractitioner :one
 INSERT INTO "bookedPractitioners" (
@@ -32,6 +53,9 @@ UPDATE "bookedPractitioners"
 WHERE id = $1
 RETURNING *;
 
--- name: DeleteBookedPractitioner :exec
-DELETE FROM "bookedPractitioners"
-WHERE id = $1;
+-- name: CancelBookedPractitioner :one
+UPDATE "bookedPractitioners"
+SET status = 'cancelled',
+    cancelled_at = now()
+WHERE id = $1 AND status = 'confirmed'
+RETURNING *;
@@ -4,13 +4,41 @@ WHERE id = $1 LIMIT 1;
 
 -- name: ListBookedVenuesByVenue :many
 SELECT * FROM "bookedVenues"
-WHERE venue_id = $1
+WHERE venue_id = sqlc.arg(venue_id)
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (booked_for, id) > (sqlc.narg(cursor_booked_for)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY booked_for, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountBookedVenuesBetween :one
+SELECT count(*) FROM "bookedVenues"
+WHERE venue_id = sqlc.arg(venue_id)
+  AND booked_for >= sqlc.arg(from_time)
+  AND booked_for < sqlc.arg(to_time)
+  AND status = 'confirmed';
+
+-- name: ListBookedVenuesBetween :many
+SELECT * FROM "bookedVenues"
+WHERE venue_id = sqlc.arg(venue_id)
+  AND booked_for >= sqlc.arg(from_time)
+  AND booked_for < sqlc.arg(to_time)
+  AND status = 'confirmed'
 ORDER BY booked_for;
 
+-- name: CountBookedVenueDays :one
+SELECT count(DISTINCT booked_for::date) FROM "bookedVenues"
+WHERE venue_id = sqlc.arg(venue_id)
+  AND booked_by = sqlc.arg(booked_by)
+  AND status = 'confirmed'
+  AND booked_for::date BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date;
+
 -- name: ListBookedVenuesByUser :many
 SELECT * FROM "bookedVenues"
-WHERE booked_by = $1
-ORDER BY booked_for;
+WHERE booked_by = sqlc.arg(booked_by)
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (booked_for, id) > (sqlc.narg(cursor_booked_for)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY booked_for, id
+LIMIT sqlc.arg(page_limit);
 
 -- name: CreateBookedVenue :one
 INSERT INTO "bookedVenues" (
@@ -32,6 +60,9 @@ UPDATE "bookedVenues"
 WHERE id = $1
 RETURNING *;
 
--- name: DeleteBookedVenue :exec
-DELETE FROM "bookedVenues"
-WHERE id = $1;
+-- name: CancelBookedVenue :one
+UPDATE "bookedVenues"
+SET status = 'cancelled',
+    cancelled_at = now()
+WHERE id = $1 AND status = 'confirmed'
+RETURNING *;
@@ -0,0 +1,26 @@
+-- name: CreateCancellation :one
+INSERT INTO "cancellations" (
+  purchase_id,
+  booked_venue_id,
+  booked_practitioner_id,
+  cancelled_by,
+  reason,
+  notice_hours,
+  refund_amount,
+  currency,
+  refund_status
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: GetCancellationByPurchase :one
+SELECT * FROM "cancellations"
+WHERE purchase_id = $1 LIMIT 1;
+
+-- name: RecordCancellationRefund :one
+UPDATE "cancellations"
+SET refund_status = sqlc.arg(refund_status),
+    provider_refund_id = sqlc.narg(provider_refund_id)
+WHERE id = sqlc.arg(id) AND refund_status = 'pending'
+RETURNING *;
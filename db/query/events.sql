@@ -1,3 +1,77 @@
+-- name: GetEvent :one
+SELECT * FROM events
+WHERE id = $1 LIMIT 1;
+
+-- name: GetEventForUpdate :one
+SELECT * FROM events
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListEvents :many
+SELECT * FROM events
+WHERE status <> 'draft'
+  AND (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (sqlc.arg(sort)::text = 'newest'
+      AND (created_at, id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+    OR (sqlc.arg(sort)::text = 'start'
+      AND (start_date, id) > (sqlc.narg(cursor_start_date)::date, sqlc.narg(cursor_id)::uuid)))
+ORDER BY
+  CASE WHEN sqlc.arg(sort)::text = 'newest' THEN created_at END DESC,
+  CASE WHEN sqlc.arg(sort)::text = 'newest' THEN id END DESC,
+  start_date, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListEventsByCreator :many
+SELECT * FROM events
+WHERE created_by = $1
+ORDER BY start_date, start_time;
+
+-- name: CreateEvent :one
+INSERT INTO "events" (
+  venue_id,
+  image_links,
+  name,
+  theme,
+  description,
+  audience,
+  activities,
+  created_by,
+  start_time,
+  start_date,
+  end_date,
+  total_particpant
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+)
+RETURNING *;
+
+-- name: UpdateEvent :one
+UPDATE events
+  set venue_id = $2,
+  image_links = $3,
+  name = $4,
+  theme = $5,
+  description = $6,
+  audience = $7,
+  activities = $8,
+  start_time = $9,
+  start_date = $10,
+  end_date = $11,
+  total_particpant = $12
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateEventStatus :one
+UPDATE events
+  set status = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteEvent :exec
+DELETE FROM events
+WHERE id = $1;
+
 -- name: GetFavourite :one
 SELECT * FROM favourites
 WHERE id = $1 LIMIT 1;
@@ -12,13 +86,31 @@ SELECT * FROM favourites
 WHERE added_by = $1
 ORDER BY created_at DESC;
 
+-- name: ListFavouriteEventsByUser :many
+SELECT sqlc.embed(events), favourites.id AS favourite_id, favourites.created_at AS favourited_at FROM favourites
+JOIN events ON events.id = favourites.event_id
+WHERE favourites.added_by = sqlc.arg(added_by)
+  AND (events.status <> 'draft' OR events.created_by = favourites.added_by)
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (favourites.created_at, favourites.id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY favourites.created_at DESC, favourites.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountFavouritesByEvents :many
+SELECT event_id, count(*) AS favourite_count FROM favourites
+WHERE event_id = ANY(sqlc.arg(event_ids)::uuid[])
+GROUP BY event_id;
+
 -- name: CreateFavourite :one
+-- Favouriting is idempotent: an existing favourite is returned unchanged.
 INSERT INTO "favourites" (
   event_id,
   added_by
 ) VALUES (
   $1, $2
 )
+ON CONFLICT (event_id, added_by) DO UPDATE
+  set event_id = EXCLUDED.event_id
 RETURNING *;
 
 -- name: DeleteFavourite :exec
@@ -28,3 +120,11 @@ WHERE id = $1;
 -- name: DeleteFavouriteByUserAndEvent :exec
 DELETE FROM favourites
 WHERE event_id = $1 AND added_by = $2;
+
+-- name: UpdateEventCancellationPolicy :one
+UPDATE events
+SET full_refund_hours = sqlc.arg(full_refund_hours),
+    no_refund_hours = sqlc.arg(no_refund_hours),
+    partial_refund_percent = sqlc.arg(partial_refund_percent)
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -0,0 +1,20 @@
+-- name: GetExchangeRate :one
+SELECT * FROM "exchange_rates"
+WHERE base = $1 AND quote = $2 LIMIT 1;
+
+-- name: ListExchangeRates :many
+SELECT * FROM "exchange_rates"
+ORDER BY base, quote;
+
+-- name: UpsertExchangeRate :one
+INSERT INTO "exchange_rates" (
+  base,
+  quote,
+  rate
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (base, quote) DO UPDATE
+SET rate = EXCLUDED.rate,
+    updated_at = now()
+RETURNING *;
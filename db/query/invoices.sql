@@ -0,0 +1,60 @@
+-- name: NextInvoiceNumber :one
+INSERT INTO "invoice_sequences" (
+  profile_id,
+  last_number
+) VALUES (
+  $1, 1
+)
+ON CONFLICT (profile_id) DO UPDATE
+SET last_number = "invoice_sequences".last_number + 1
+RETURNING last_number;
+
+-- name: CreateInvoice :one
+INSERT INTO "invoices" (
+  purchase_id,
+  seller_id,
+  buyer_id,
+  sequence,
+  number,
+  seller_name,
+  seller_address,
+  seller_country,
+  buyer_name,
+  buyer_address,
+  buyer_country,
+  currency,
+  subtotal,
+  tax_amount,
+  total
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+)
+RETURNING *;
+
+-- name: CreateInvoiceLine :one
+INSERT INTO "invoice_lines" (
+  invoice_id,
+  position,
+  kind,
+  description,
+  quantity,
+  unit_amount,
+  amount,
+  tax_rate
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING *;
+
+-- name: GetInvoiceByPurchase :one
+SELECT * FROM "invoices"
+WHERE purchase_id = $1 LIMIT 1;
+
+-- name: ListInvoiceLines :many
+SELECT * FROM "invoice_lines"
+WHERE invoice_id = $1
+ORDER BY position;
+
+-- name: GetTicketByPurchase :one
+SELECT * FROM "tickets"
+WHERE purchase_id = $1 LIMIT 1;
@@ -0,0 +1,19 @@
+-- name: ListNotificationsByProfile :many
+SELECT * FROM notifications
+WHERE profile_id = sqlc.arg(profile_id)
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CreateNotification :one
+INSERT INTO "notifications" (
+  profile_id,
+  kind,
+  message,
+  waitlist_entry_id
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
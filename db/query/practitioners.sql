@@ -2,9 +2,24 @@
 SELECT * FROM practitioners
 WHERE id = $1 LIMIT 1;
 
+-- name: GetPractitionerForUpdate :one
+SELECT * FROM practitioners
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
 -- name: ListPractitioners :many
 SELECT * FROM practitioners
-ORDER BY name;
+WHERE (sqlc.narg(is_available)::boolean IS NULL OR is_available = sqlc.narg(is_available))
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (sqlc.arg(sort)::text = 'newest'
+      AND (created_at, id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+    OR (sqlc.arg(sort)::text = 'name'
+      AND (name, id) > (sqlc.narg(cursor_name)::varchar, sqlc.narg(cursor_id)::uuid)))
+ORDER BY
+  CASE WHEN sqlc.arg(sort)::text = 'newest' THEN created_at END DESC,
+  CASE WHEN sqlc.arg(sort)::text = 'newest' THEN id END DESC,
+  name, id
+LIMIT sqlc.arg(page_limit);
 
 -- name: CreatePractitioner :one
 INSERT INTO "practitioners" (
@@ -37,3 +52,11 @@ RETURNING *;
 -- name: DeletePractitioner :exec
 DELETE FROM practitioners
 WHERE id = $1;
+
+-- name: UpdatePractitionerCancellationPolicy :one
+UPDATE practitioners
+SET full_refund_hours = sqlc.arg(full_refund_hours),
+    no_refund_hours = sqlc.arg(no_refund_hours),
+    partial_refund_percent = sqlc.arg(partial_refund_percent)
+WHERE id = sqlc.arg(id)
+RETURNING *;
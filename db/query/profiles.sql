@@ -4,7 +4,11 @@ WHERE id = $1 LIMIT 1;
 
 -- name: ListProfiles :many
 SELECT * FROM profiles
-ORDER BY business_name; -- Or any other relevant field for ordering
+WHERE (sqlc.narg(country)::varchar IS NULL OR country = sqlc.narg(country))
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
 
 -- name: CreateProfile :one
 INSERT INTO "profiles" (
@@ -38,3 +42,68 @@ RETURNING *;
 -- name: DeleteProfile :exec
 DELETE FROM profiles
 WHERE id = $1;
+
+-- name: ListProfilesByUser :many
+SELECT sqlc.embed(profiles), profiles_users.member_role FROM profiles
+JOIN profiles_users ON profiles_users.profiles_id = profiles.id
+WHERE profiles_users.users_id = $1
+ORDER BY profiles.created_at;
+
+-- name: ListProfileMembershipsByUser :many
+SELECT sqlc.embed(profiles), profiles_users.member_role FROM profiles
+JOIN profiles_users ON profiles_users.profiles_id = profiles.id
+WHERE profiles_users.users_id = sqlc.arg(users_id)
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (profiles.created_at, profiles.id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY profiles.created_at, profiles.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: GetProfileMember :one
+SELECT * FROM profiles_users
+WHERE profiles_id = $1 AND users_id = $2 LIMIT 1;
+
+-- name: ListProfileMembers :many
+SELECT profiles_users.*, users.email, users.firstname, users.lastname FROM profiles_users
+JOIN users ON users.id = profiles_users.users_id
+WHERE profiles_users.profiles_id = sqlc.arg(profiles_id)
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (profiles_users.created_at, profiles_users.users_id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY profiles_users.created_at, profiles_users.users_id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListProfileOwnersForUpdate :many
+SELECT * FROM profiles_users
+WHERE profiles_id = $1 AND member_role = 'owner'
+ORDER BY users_id
+FOR UPDATE;
+
+-- name: LinkUserToProfile :one
+INSERT INTO "profiles_users" (
+  profiles_id,
+  users_id,
+  member_role
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: UpdateProfileMemberRole :one
+UPDATE profiles_users
+  set member_role = $3
+WHERE profiles_id = $1 AND users_id = $2
+RETURNING *;
+
+-- name: UnlinkUserFromProfile :execrows
+DELETE FROM profiles_users
+WHERE profiles_id = $1 AND users_id = $2;
+
+-- name: UnlinkAllUsersFromProfile :exec
+DELETE FROM profiles_users
+WHERE profiles_id = $1;
+
+-- name: GetProfileOwner :one
+SELECT users.* FROM users
+JOIN profiles_users ON profiles_users.users_id = users.id
+WHERE profiles_users.profiles_id = $1 AND profiles_users.member_role = 'owner'
+ORDER BY profiles_users.created_at
+LIMIT 1;
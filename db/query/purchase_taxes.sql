@@ -0,0 +1,17 @@
+-- name: CreatePurchaseTax :one
+INSERT INTO "purchase_taxes" (
+  purchase_id,
+  position,
+  name,
+  rate,
+  inclusive,
+  amount
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: ListPurchaseTaxes :many
+SELECT * FROM "purchase_taxes"
+WHERE purchase_id = $1
+ORDER BY position;
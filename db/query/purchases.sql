@@ -2,37 +2,148 @@
 SELECT * FROM purchases
 WHERE id = $1 LIMIT 1;
 
+-- name: GetPurchaseForUpdate :one
+SELECT * FROM purchases
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: GetPurchaseByBookedVenue :one
+SELECT * FROM purchases
+WHERE booked_venue_id = $1 LIMIT 1;
+
+-- name: GetPurchaseByBookedPractitioner :one
+SELECT * FROM purchases
+WHERE booked_practitioner_id = $1 LIMIT 1;
+
 -- name: ListPurchasesByUser :many
 SELECT * FROM purchases
-WHERE purchased_by = $1
-ORDER BY created_at DESC;
+WHERE purchased_by = sqlc.arg(purchased_by)
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
 
 -- name: ListPurchasesByEvent :many
 SELECT * FROM purchases
-WHERE event_id = $1
-ORDER BY created_at DESC;
+WHERE event_id = sqlc.arg(event_id)
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountPurchasesByEvent :one
+SELECT count(*) FROM purchases
+WHERE event_id = $1 AND status IN ('pending', 'paid');
+
+-- name: ListLivePurchasesByEventForUpdate :many
+SELECT * FROM purchases
+WHERE event_id = $1 AND status IN ('pending', 'paid')
+ORDER BY created_at, id
+FOR UPDATE;
 
 -- name: ListPurchasesByVenue :many
 SELECT * FROM purchases
-WHERE venue_id = $1
-ORDER BY created_at DESC;
+WHERE venue_id = sqlc.arg(venue_id)
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
 
 -- name: ListPurchasesByService :many
 SELECT * FROM purchases
-WHERE service_id = $1
-ORDER BY created_at DESC;
+WHERE service_id = sqlc.arg(service_id)
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
 
 -- name: CreatePurchase :one
 INSERT INTO "purchases" (
   event_id,
   venue_id,
   service_id,
-  purchased_by
+  purchased_by,
+  amount,
+  booked_venue_id,
+  booked_practitioner_id,
+  currency,
+  status,
+  paid_at,
+  tax_amount,
+  pending_until
 ) VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3, $4, $5, $6, $7, $8, $9,
+  CASE WHEN $9::varchar = 'paid' THEN now() END,
+  $10, $11
 )
 RETURNING *;
 
--- name: DeletePurchase :exec
-DELETE FROM purchases
-WHERE id = $1;
+-- name: SetPurchaseCheckout :one
+UPDATE purchases
+SET provider = sqlc.arg(provider)::varchar,
+    provider_checkout_id = sqlc.arg(provider_checkout_id)::varchar
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING *;
+
+-- name: FailPurchase :exec
+UPDATE purchases
+SET status = 'failed'
+WHERE id = $1 AND status = 'pending';
+
+-- name: SettleCheckoutPurchases :many
+UPDATE purchases
+SET status = sqlc.arg(status)::varchar,
+    provider_payment_id = sqlc.narg(provider_payment_id),
+    paid_at = CASE WHEN sqlc.arg(status)::varchar = 'paid' THEN now() END
+WHERE provider = sqlc.arg(provider)::varchar
+  AND provider_checkout_id = sqlc.arg(provider_checkout_id)::varchar
+  AND status = 'pending'
+RETURNING *;
+
+-- name: CountPaidCheckoutPurchases :one
+SELECT count(*) FROM purchases
+WHERE provider = $1 AND provider_checkout_id = $2 AND status = 'paid';
+
+-- name: ListExpiredPurchases :many
+-- Lists the pending purchases whose checkout has expired, optionally only
+-- those of one event, venue or service.
+SELECT * FROM purchases
+WHERE status = 'pending'
+  AND pending_until <= now()
+  AND (sqlc.narg(event_id)::uuid IS NULL OR event_id = sqlc.narg(event_id))
+  AND (sqlc.narg(venue_id)::uuid IS NULL OR venue_id = sqlc.narg(venue_id))
+  AND (sqlc.narg(service_id)::uuid IS NULL OR service_id = sqlc.narg(service_id));
+
+-- name: ListCheckoutPurchases :many
+SELECT * FROM purchases
+WHERE provider = $1 AND provider_checkout_id = $2;
+
+-- name: FailExpiredPurchases :many
+-- Fails the pending purchases whose checkout has expired, optionally only
+-- those of one event, venue or service.
+UPDATE purchases
+SET status = 'failed'
+WHERE status = 'pending'
+  AND pending_until <= now()
+  AND (sqlc.narg(event_id)::uuid IS NULL OR event_id = sqlc.narg(event_id))
+  AND (sqlc.narg(venue_id)::uuid IS NULL OR venue_id = sqlc.narg(venue_id))
+  AND (sqlc.narg(service_id)::uuid IS NULL OR service_id = sqlc.narg(service_id))
+RETURNING *;
+
+-- name: RecordPaymentEvent :execrows
+INSERT INTO payment_events (
+  provider,
+  event_id,
+  type,
+  checkout_id
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT DO NOTHING;
+
+-- name: CancelPurchase :one
+UPDATE purchases
+SET status = 'cancelled',
+    cancelled_at = now()
+WHERE id = $1 AND status IN ('pending', 'paid')
+RETURNING *;
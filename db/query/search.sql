@@ -0,0 +1,43 @@
+-- name: SearchVenuesText :many
+WITH matches AS (
+  SELECT venues.id, venues.name, ts_rank(venues.search_vector, query) AS rank,
+    concat_ws(' ', venues.name, venues.type, venues.location, venues.description) AS document, query
+  FROM venues, websearch_to_tsquery('english', sqlc.arg(query)::text) AS query
+  WHERE venues.search_vector @@ query
+)
+SELECT id, name, rank, ts_headline('english', document, query, 'MaxWords=30, MinWords=10')::text AS snippet
+FROM matches
+WHERE sqlc.narg(cursor_id)::uuid IS NULL
+  OR (rank, id) < (sqlc.narg(cursor_rank)::real, sqlc.narg(cursor_id)::uuid)
+ORDER BY rank DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: SearchPractitionersText :many
+WITH matches AS (
+  SELECT practitioners.id, practitioners.name, ts_rank(practitioners.search_vector, query) AS rank,
+    concat_ws(' ', practitioners.name, practitioners.description) AS document, query
+  FROM practitioners, websearch_to_tsquery('english', sqlc.arg(query)::text) AS query
+  WHERE practitioners.search_vector @@ query
+)
+SELECT id, name, rank, ts_headline('english', document, query, 'MaxWords=30, MinWords=10')::text AS snippet
+FROM matches
+WHERE sqlc.narg(cursor_id)::uuid IS NULL
+  OR (rank, id) < (sqlc.narg(cursor_rank)::real, sqlc.narg(cursor_id)::uuid)
+ORDER BY rank DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: SearchEventsText :many
+-- Drafts are private to their organiser and never appear in search results.
+WITH matches AS (
+  SELECT events.id, coalesce(events.name, '')::varchar AS name, ts_rank(events.search_vector, query) AS rank,
+    concat_ws(' ', events.name, events.theme, events.description, events.audience) AS document, query
+  FROM events, websearch_to_tsquery('english', sqlc.arg(query)::text) AS query
+  WHERE events.search_vector @@ query
+    AND events.status <> 'draft'
+)
+SELECT id, name, rank, ts_headline('english', document, query, 'MaxWords=30, MinWords=10')::text AS snippet
+FROM matches
+WHERE sqlc.narg(cursor_id)::uuid IS NULL
+  OR (rank, id) < (sqlc.narg(cursor_rank)::real, sqlc.narg(cursor_id)::uuid)
+ORDER BY rank DESC, id DESC
+LIMIT sqlc.arg(page_limit);
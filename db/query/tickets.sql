@@ -0,0 +1,43 @@
+-- name: GetTicketTier :one
+SELECT * FROM ticket_tiers
+WHERE id = $1 LIMIT 1;
+
+-- name: ListTicketTiersByEvent :many
+SELECT * FROM ticket_tiers
+WHERE event_id = sqlc.arg(event_id)
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (price, id) > (sqlc.narg(cursor_price)::integer, sqlc.narg(cursor_id)::uuid))
+ORDER BY price, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: CreateTicketTier :one
+INSERT INTO "ticket_tiers" (
+  event_id,
+  name,
+  price,
+  quantity,
+  currency
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: CountTicketsByTier :one
+SELECT count(*) FROM tickets
+JOIN purchases ON purchases.id = tickets.purchase_id
+WHERE tickets.tier_id = $1 AND purchases.status IN ('pending', 'paid');
+
+-- name: CountTicketsByEvent :one
+SELECT count(*) FROM tickets
+JOIN purchases ON purchases.id = tickets.purchase_id
+WHERE purchases.event_id = $1 AND purchases.status IN ('pending', 'paid');
+
+-- name: CreateTicket :one
+INSERT INTO "tickets" (
+  purchase_id,
+  tier_id,
+  code
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
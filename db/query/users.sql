@@ -8,7 +8,16 @@ WHERE email = $1 LIMIT 1;
 
 -- name: ListUsers :many
 SELECT * FROM users
-ORDER BY lastname, firstname;
+WHERE sqlc.narg(cursor_id)::uuid IS NULL
+  OR (sqlc.arg(sort)::text = 'newest'
+    AND (created_at, id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+  OR (sqlc.arg(sort)::text = 'email'
+    AND (email, id) > (sqlc.narg(cursor_email)::varchar, sqlc.narg(cursor_id)::uuid))
+ORDER BY
+  CASE WHEN sqlc.arg(sort)::text = 'newest' THEN created_at END DESC,
+  CASE WHEN sqlc.arg(sort)::text = 'newest' THEN id END DESC,
+  email, id
+LIMIT sqlc.arg(page_limit);
 
 -- name: CreateUser :one
 INSERT INTO "users" (
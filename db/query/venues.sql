@@ -2,9 +2,125 @@
 SELECT * FROM venues
 WHERE id = $1 LIMIT 1;
 
+-- name: GetVenueForUpdate :one
+SELECT * FROM venues
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
 -- name: Listvenues :many
 SELECT * FROM venues
-ORDER BY name;
+WHERE (sqlc.narg(type)::varchar IS NULL OR type = sqlc.narg(type))
+  AND (sqlc.narg(is_available)::boolean IS NULL OR is_available = sqlc.narg(is_available))
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (sqlc.arg(sort)::text = 'newest'
+      AND (created_at, id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+    OR (sqlc.arg(sort)::text = 'name'
+      AND (name, id) > (sqlc.narg(cursor_name)::varchar, sqlc.narg(cursor_id)::uuid)))
+ORDER BY
+  CASE WHEN sqlc.arg(sort)::text = 'newest' THEN created_at END DESC,
+  CASE WHEN sqlc.arg(sort)::text = 'newest' THEN id END DESC,
+  name, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: SearchVenues :many
+SELECT * FROM venues
+WHERE (sqlc.narg(type)::varchar IS NULL OR type = sqlc.narg(type))
+  AND (sqlc.narg(location)::varchar IS NULL OR location ILIKE '%' || sqlc.narg(location)::varchar || '%')
+  AND (sqlc.narg(min_capacity)::integer IS NULL OR capacity >= sqlc.narg(min_capacity))
+  AND (sqlc.narg(facilities)::varchar[] IS NULL OR facilities @> sqlc.narg(facilities)::varchar[])
+  AND (sqlc.narg(has_accomodation)::boolean IS NULL OR has_accomodation = sqlc.narg(has_accomodation))
+  AND (sqlc.narg(bed_type)::varchar IS NULL OR bed_type = sqlc.narg(bed_type))
+  AND (sqlc.narg(room_type)::varchar IS NULL OR room_type = sqlc.narg(room_type))
+  AND (sqlc.narg(min_rent)::integer IS NULL OR rent >= sqlc.narg(min_rent))
+  AND (sqlc.narg(max_rent)::integer IS NULL OR rent <= sqlc.narg(max_rent))
+  AND (sqlc.narg(min_booking_price)::integer IS NULL OR booking_price >= sqlc.narg(min_booking_price))
+  AND (sqlc.narg(max_booking_price)::integer IS NULL OR booking_price <= sqlc.narg(max_booking_price))
+  AND (sqlc.narg(available_on)::date IS NULL OR (
+    is_available
+    AND weekdays_include(rental_days, sqlc.narg(available_on)::date)
+    AND NOT EXISTS (
+      SELECT 1 FROM "bookedVenues"
+      WHERE "bookedVenues".venue_id = venues.id
+        AND "bookedVenues".booked_for >= sqlc.narg(available_on)::date
+        AND "bookedVenues".booked_for < sqlc.narg(available_on)::date + 1
+        AND "bookedVenues".status = 'confirmed')))
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (sqlc.arg(sort)::text = 'newest'
+      AND (created_at, id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+    OR (sqlc.arg(sort)::text = 'name'
+      AND (name, id) > (sqlc.narg(cursor_name)::varchar, sqlc.narg(cursor_id)::uuid)))
+ORDER BY
+  CASE WHEN sqlc.arg(sort)::text = 'newest' THEN created_at END DESC,
+  CASE WHEN sqlc.arg(sort)::text = 'newest' THEN id END DESC,
+  name, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountVenueFacets :many
+-- Counts the venues matching a search by each value of the facet columns.
+SELECT
+  (CASE
+    WHEN GROUPING(type) = 0 THEN 'type'
+    WHEN GROUPING(room_type) = 0 THEN 'room_type'
+    WHEN GROUPING(bed_type) = 0 THEN 'bed_type'
+    ELSE 'has_accomodation'
+  END)::text AS facet,
+  (CASE
+    WHEN GROUPING(type) = 0 THEN type
+    WHEN GROUPING(room_type) = 0 THEN room_type
+    WHEN GROUPING(bed_type) = 0 THEN bed_type
+    ELSE has_accomodation::text
+  END)::text AS value,
+  count(*) AS venue_count
+FROM venues
+WHERE (sqlc.narg(type)::varchar IS NULL OR type = sqlc.narg(type))
+  AND (sqlc.narg(location)::varchar IS NULL OR location ILIKE '%' || sqlc.narg(location)::varchar || '%')
+  AND (sqlc.narg(min_capacity)::integer IS NULL OR capacity >= sqlc.narg(min_capacity))
+  AND (sqlc.narg(facilities)::varchar[] IS NULL OR facilities @> sqlc.narg(facilities)::varchar[])
+  AND (sqlc.narg(has_accomodation)::boolean IS NULL OR has_accomodation = sqlc.narg(has_accomodation))
+  AND (sqlc.narg(bed_type)::varchar IS NULL OR bed_type = sqlc.narg(bed_type))
+  AND (sqlc.narg(room_type)::varchar IS NULL OR room_type = sqlc.narg(room_type))
+  AND (sqlc.narg(min_rent)::integer IS NULL OR rent >= sqlc.narg(min_rent))
+  AND (sqlc.narg(max_rent)::integer IS NULL OR rent <= sqlc.narg(max_rent))
+  AND (sqlc.narg(min_booking_price)::integer IS NULL OR booking_price >= sqlc.narg(min_booking_price))
+  AND (sqlc.narg(max_booking_price)::integer IS NULL OR booking_price <= sqlc.narg(max_booking_price))
+  AND (sqlc.narg(available_on)::date IS NULL OR (
+    is_available
+    AND weekdays_include(rental_days, sqlc.narg(available_on)::date)
+    AND NOT EXISTS (
+      SELECT 1 FROM "bookedVenues"
+      WHERE "bookedVenues".venue_id = venues.id
+        AND "bookedVenues".booked_for >= sqlc.narg(available_on)::date
+        AND "bookedVenues".booked_for < sqlc.narg(available_on)::date + 1
+        AND "bookedVenues".status = 'confirmed')))
+GROUP BY GROUPING SETS ((type), (room_type), (bed_type), (has_accomodation))
+HAVING (CASE
+    WHEN GROUPING(type) = 0 THEN type
+    WHEN GROUPING(room_type) = 0 THEN room_type
+    WHEN GROUPING(bed_type) = 0 THEN bed_type
+    ELSE has_accomodation::text
+  END) IS NOT NULL
+ORDER BY facet, venue_count DESC, value;
+
+-- name: ListVenuesNearby :many
+-- The bounding box narrows the candidates down using the index before the
+-- haversine distance is computed.
+WITH nearby AS (
+  SELECT id, (2 * 6371 * asin(least(1, sqrt(
+    power(sin(radians(latitude - sqlc.arg(lat)::float8) / 2), 2) +
+    cos(radians(sqlc.arg(lat)::float8)) * cos(radians(latitude)) *
+    power(sin(radians(longitude - sqlc.arg(lng)::float8) / 2), 2)
+  ))))::float8 AS distance_km
+  FROM venues
+  WHERE latitude BETWEEN sqlc.arg(min_lat)::float8 AND sqlc.arg(max_lat)::float8
+    AND longitude BETWEEN sqlc.arg(min_lng)::float8 AND sqlc.arg(max_lng)::float8
+)
+SELECT sqlc.embed(venues), nearby.distance_km FROM nearby
+JOIN venues ON venues.id = nearby.id
+WHERE nearby.distance_km <= sqlc.arg(radius_km)::float8
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (nearby.distance_km, nearby.id) > (sqlc.narg(cursor_distance)::float8, sqlc.narg(cursor_id)::uuid))
+ORDER BY nearby.distance_km, nearby.id
+LIMIT sqlc.arg(page_limit);
 
 -- name: CreateVenue :one
 INSERT INTO "venues" (
@@ -27,22 +143,25 @@ INSERT INTO "venues" (
   opens_at,
   closes_at,
   rental_days,
-  booking_price
+  booking_price,
+  latitude,
+  longitude,
+  currency
 ) VALUES (
-  $1::varchar[], $2, $3, $4, $5, $6, $7, $8::varchar[], $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23
 )
 RETURNING *;
 
--- name: UpdateVenue :exec
+-- name: UpdateVenue :one
 UPDATE venues
   set name = $2,
-  image_links = $3::varchar[],
+  image_links = $3,
   type = $4,
   description = $5,
   location = $6,
   dimension = $7,
   capacity = $8,
-  facilities = $9::varchar[],
+  facilities = $9,
   has_accomodation = $10,
   room_type = $11,
   no_of_rooms = $12,
@@ -54,9 +173,21 @@ UPDATE venues
   opens_at = $18,
   closes_at = $19,
   rental_days = $20,
-  booking_price = $21
-WHERE id = $1;
+  booking_price = $21,
+  latitude = $22,
+  longitude = $23,
+  currency = $24
+WHERE id = $1
+RETURNING *;
 
 -- name: DeleteVenue :exec
 DELETE FROM venues
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: UpdateVenueCancellationPolicy :one
+UPDATE venues
+SET full_refund_hours = sqlc.arg(full_refund_hours),
+    no_refund_hours = sqlc.arg(no_refund_hours),
+    partial_refund_percent = sqlc.arg(partial_refund_percent)
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -0,0 +1,99 @@
+-- name: GetWaitlistEntry :one
+SELECT * FROM waitlist_entries
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWaitingByEvent :many
+SELECT * FROM waitlist_entries
+WHERE event_id = sqlc.arg(event_id)
+  AND status = 'waiting'
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (created_at, id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListWaitingByVenue :many
+SELECT * FROM waitlist_entries
+WHERE venue_id = sqlc.arg(venue_id)
+  AND status = 'waiting'
+  AND (sqlc.narg(booked_for)::date IS NULL OR booked_for = sqlc.narg(booked_for))
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (created_at, id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: NextWaitingForEvent :one
+SELECT * FROM waitlist_entries
+WHERE event_id = $1 AND status = 'waiting'
+ORDER BY created_at, id
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: NextWaitingForVenue :one
+SELECT * FROM waitlist_entries
+WHERE venue_id = $1 AND booked_for = $2 AND status = 'waiting'
+ORDER BY created_at, id
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: CreateWaitlistEntry :one
+INSERT INTO "waitlist_entries" (
+  event_id,
+  venue_id,
+  booked_for,
+  profile_id
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: PromoteWaitlistEntry :one
+UPDATE waitlist_entries
+  set status = 'promoted',
+  promoted_at = now(),
+  hold_until = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: CountEventHolds :one
+SELECT count(*) FROM waitlist_entries
+WHERE event_id = $1 AND status = 'promoted' AND hold_until > now();
+
+-- name: CountVenueHolds :one
+SELECT count(*) FROM waitlist_entries
+WHERE venue_id = $1 AND booked_for = $2 AND status = 'promoted' AND hold_until > now();
+
+-- name: ClaimEventHold :execrows
+UPDATE waitlist_entries
+  set hold_until = NULL
+WHERE event_id = $1 AND profile_id = $2 AND status = 'promoted' AND hold_until > now();
+
+-- name: ClaimVenueHold :execrows
+UPDATE waitlist_entries
+  set hold_until = NULL
+WHERE venue_id = $1 AND booked_for = $2 AND profile_id = $3 AND status = 'promoted' AND hold_until > now();
+
+-- name: ListLapsedHolds :many
+-- Lists the holds that ended before their place was bought, optionally only
+-- those of one event or venue.
+SELECT * FROM waitlist_entries
+WHERE status = 'promoted'
+  AND hold_until <= now()
+  AND (sqlc.narg(event_id)::uuid IS NULL OR event_id = sqlc.narg(event_id))
+  AND (sqlc.narg(venue_id)::uuid IS NULL OR venue_id = sqlc.narg(venue_id))
+ORDER BY hold_until, id;
+
+-- name: ExpireWaitlistHold :one
+UPDATE waitlist_entries
+  set status = 'expired'
+WHERE id = $1 AND status = 'promoted' AND hold_until <= now()
+RETURNING *;
+
+-- name: WithdrawEventWaitlistEntry :execrows
+UPDATE waitlist_entries
+  set status = 'withdrawn'
+WHERE event_id = $1 AND profile_id = $2 AND status = 'waiting';
+
+-- name: WithdrawVenueWaitlistEntry :execrows
+UPDATE waitlist_entries
+  set status = 'withdrawn'
+WHERE venue_id = $1 AND booked_for = $2 AND profile_id = $3 AND status = 'waiting';
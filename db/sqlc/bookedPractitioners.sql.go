@@ -8,10 +8,56 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const cancelBookedPractitioner = `-- name: CancelBookedPractitioner :one
+UPDATE "bookedPractitioners"
+SET status = 'cancelled',
+    cancelled_at = now()
+WHERE id = $1 AND status = 'confirmed'
+RETURNING id, type, service_id, booked_for, booked_by, created_at, status, cancelled_at
+`
+
+func (q *Queries) CancelBookedPractitioner(ctx context.Context, id uuid.UUID) (BookedPractitioners, error) {
+	row := q.db.QueryRowContext(ctx, cancelBookedPractitioner, id)
+	var i BookedPractitioners
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.ServiceID,
+		&i.BookedFor,
+		&i.BookedBy,
+		&i.CreatedAt,
+		&i.Status,
+		&i.CancelledAt,
+	)
+	return i, err
+}
+
+const countBookedPractitionersBetween = `-- name: CountBookedPractitionersBetween :one
+SELECT count(*) FROM "bookedPractitioners"
+WHERE service_id = $1
+  AND booked_for > $2
+  AND booked_for < $3
+  AND status = 'confirmed'
+`
+
+type CountBookedPractitionersBetweenParams struct {
+	ServiceID uuid.UUID `json:"service_id"`
+	FromTime  time.Time `json:"from_time"`
+	ToTime    time.Time `json:"to_time"`
+}
+
+func (q *Queries) CountBookedPractitionersBetween(ctx context.Context, arg CountBookedPractitionersBetweenParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countBookedPractitionersBetween, arg.ServiceID, arg.FromTime, arg.ToTime)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createBookedPractitioner = `-- name: CreateBookedPractitioner :one
 INSERT INTO "bookedPractitioners" (
   type,
@@ -21,14 +67,14 @@ INSERT INTO "bookedPractitioners" (
 ) VALUES (
   $1, $2, $3, $4
 )
-RETURNING id, type, service_id, booked_for, booked_by, created_at
+RETURNING id, type, service_id, booked_for, booked_by, created_at, status, cancelled_at
 `
 
 type CreateBookedPractitionerParams struct {
 	Type      sql.NullString `json:"type"`
-	ServiceID uuid.NullUUID  `json:"service_id"`
-	BookedFor sql.NullTime   `json:"booked_for"`
-	BookedBy  uuid.NullUUID  `json:"booked_by"`
+	ServiceID uuid.UUID      `json:"service_id"`
+	BookedFor time.Time      `json:"booked_for"`
+	BookedBy  uuid.UUID      `json:"booked_by"`
 }
 
 func (q *Queries) CreateBookedPractitioner(ctx context.Context, arg CreateBookedPractitionerParams) (BookedPractitioners, error) {
@@ -46,22 +92,14 @@ func (q *Queries) CreateBookedPractitioner(ctx context.Context, arg CreateBooked
 		&i.BookedFor,
 		&i.BookedBy,
 		&i.CreatedAt,
+		&i.Status,
+		&i.CancelledAt,
 	)
 	return i, err
 }
 
-const deleteBookedPractitioner = `-- name: DeleteBookedPractitioner :exec
-DELETE FROM "bookedPractitioners"
-WHERE id = $1
-`
-
-func (q *Queries) DeleteBookedPractitioner(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteBookedPractitioner, id)
-	return err
-}
-
 const getBookedPractitioner = `-- name: GetBookedPractitioner :one
-SELECT id, type, service_id, booked_for, booked_by, created_at FROM "bookedPractitioners"
+SELECT id, type, service_id, booked_for, booked_by, created_at, status, cancelled_at FROM "bookedPractitioners"
 WHERE id = $1 LIMIT 1
 `
 
@@ -75,18 +113,29 @@ func (q *Queries) GetBookedPractitioner(ctx context.Context, id uuid.UUID) (Book
 		&i.BookedFor,
 		&i.BookedBy,
 		&i.CreatedAt,
+		&i.Status,
+		&i.CancelledAt,
 	)
 	return i, err
 }
 
-const listBookedPractitionersByService = `-- name: ListBookedPractitionersByService :many
-SELECT id, type, service_id, booked_for, booked_by, created_at FROM "bookedPractitioners"
+const listBookedPractitionersBetween = `-- name: ListBookedPractitionersBetween :many
+SELECT id, type, service_id, booked_for, booked_by, created_at, status, cancelled_at FROM "bookedPractitioners"
 WHERE service_id = $1
+  AND booked_for > $2
+  AND booked_for < $3
+  AND status = 'confirmed'
 ORDER BY booked_for
 `
 
-func (q *Queries) ListBookedPractitionersByService(ctx context.Context, serviceID uuid.NullUUID) ([]BookedPractitioners, error) {
-	rows, err := q.db.QueryContext(ctx, listBookedPractitionersByService, serviceID)
+type ListBookedPractitionersBetweenParams struct {
+	ServiceID uuid.UUID `json:"service_id"`
+	FromTime  time.Time `json:"from_time"`
+	ToTime    time.Time `json:"to_time"`
+}
+
+func (q *Queries) ListBookedPractitionersBetween(ctx context.Context, arg ListBookedPractitionersBetweenParams) ([]BookedPractitioners, error) {
+	rows, err := q.db.QueryContext(ctx, listBookedPractitionersBetween, arg.ServiceID, arg.FromTime, arg.ToTime)
 	if err != nil {
 		return nil, err
 	}
@@ -101,6 +150,61 @@ func (q *Queries) ListBookedPractitionersByService(ctx context.Context, serviceI
 			&i.BookedFor,
 			&i.BookedBy,
 			&i.CreatedAt,
+			&i.Status,
+			&i.CancelledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookedPractitionersByService = `-- name: ListBookedPractitionersByService :many
+SELECT id, type, service_id, booked_for, booked_by, created_at, status, cancelled_at FROM "bookedPractitioners"
+WHERE service_id = $1
+  AND ($2::uuid IS NULL
+    OR (booked_for, id) > ($3::timestamp, $2::uuid))
+ORDER BY booked_for, id
+LIMIT $4
+`
+
+type ListBookedPractitionersByServiceParams struct {
+	ServiceID       uuid.UUID     `json:"service_id"`
+	CursorID        uuid.NullUUID `json:"cursor_id"`
+	CursorBookedFor sql.NullTime  `json:"cursor_booked_for"`
+	PageLimit       int32         `json:"page_limit"`
+}
+
+func (q *Queries) ListBookedPractitionersByService(ctx context.Context, arg ListBookedPractitionersByServiceParams) ([]BookedPractitioners, error) {
+	rows, err := q.db.QueryContext(ctx, listBookedPractitionersByService,
+		arg.ServiceID,
+		arg.CursorID,
+		arg.CursorBookedFor,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BookedPractitioners
+	for rows.Next() {
+		var i BookedPractitioners
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.ServiceID,
+			&i.BookedFor,
+			&i.BookedBy,
+			&i.CreatedAt,
+			&i.Status,
+			&i.CancelledAt,
 		); err != nil {
 			return nil, err
 		}
@@ -116,13 +220,28 @@ func (q *Queries) ListBookedPractitionersByService(ctx context.Context, serviceI
 }
 
 const listBookedPractitionersByUser = `-- name: ListBookedPractitionersByUser :many
-SELECT id, type, service_id, booked_for, booked_by, created_at FROM "bookedPractitioners"
+SELECT id, type, service_id, booked_for, booked_by, created_at, status, cancelled_at FROM "bookedPractitioners"
 WHERE booked_by = $1
-ORDER BY booked_for
+  AND ($2::uuid IS NULL
+    OR (booked_for, id) > ($3::timestamp, $2::uuid))
+ORDER BY booked_for, id
+LIMIT $4
 `
 
-func (q *Queries) ListBookedPractitionersByUser(ctx context.Context, bookedBy uuid.NullUUID) ([]BookedPractitioners, error) {
-	rows, err := q.db.QueryContext(ctx, listBookedPractitionersByUser, bookedBy)
+type ListBookedPractitionersByUserParams struct {
+	BookedBy        uuid.UUID     `json:"booked_by"`
+	CursorID        uuid.NullUUID `json:"cursor_id"`
+	CursorBookedFor sql.NullTime  `json:"cursor_booked_for"`
+	PageLimit       int32         `json:"page_limit"`
+}
+
+func (q *Queries) ListBookedPractitionersByUser(ctx context.Context, arg ListBookedPractitionersByUserParams) ([]BookedPractitioners, error) {
+	rows, err := q.db.QueryContext(ctx, listBookedPractitionersByUser,
+		arg.BookedBy,
+		arg.CursorID,
+		arg.CursorBookedFor,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -137,6 +256,8 @@ func (q *Queries) ListBookedPractitionersByUser(ctx context.Context, bookedBy uu
 			&i.BookedFor,
 			&i.BookedBy,
 			&i.CreatedAt,
+			&i.Status,
+			&i.CancelledAt,
 		); err != nil {
 			return nil, err
 		}
@@ -158,15 +279,15 @@ UPDATE "bookedPractitioners"
   booked_for = $4,
   booked_by = $5
 WHERE id = $1
-RETURNING id, type, service_id, booked_for, booked_by, created_at
+RETURNING id, type, service_id, booked_for, booked_by, created_at, status, cancelled_at
 `
 
 type UpdateBookedPractitionerParams struct {
 	ID        uuid.UUID      `json:"id"`
 	Type      sql.NullString `json:"type"`
-	ServiceID uuid.NullUUID  `json:"service_id"`
-	BookedFor sql.NullTime   `json:"booked_for"`
-	BookedBy  uuid.NullUUID  `json:"booked_by"`
+	ServiceID uuid.UUID      `json:"service_id"`
+	BookedFor time.Time      `json:"booked_for"`
+	BookedBy  uuid.UUID      `json:"booked_by"`
 }
 
 func (q *Queries) UpdateBookedPractitioner(ctx context.Context, arg UpdateBookedPractitionerParams) (BookedPractitioners, error) {
@@ -185,6 +306,8 @@ func (q *Queries) UpdateBookedPractitioner(ctx context.Context, arg UpdateBooked
 		&i.BookedFor,
 		&i.BookedBy,
 		&i.CreatedAt,
+		&i.Status,
+		&i.CancelledAt,
 	)
 	return i, err
 }
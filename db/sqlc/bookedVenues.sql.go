@@ -8,10 +8,83 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const cancelBookedVenue = `-- name: CancelBookedVenue :one
+UPDATE "bookedVenues"
+SET status = 'cancelled',
+    cancelled_at = now()
+WHERE id = $1 AND status = 'confirmed'
+RETURNING id, type, venue_id, booked_for, booked_by, created_at, status, cancelled_at
+`
+
+func (q *Queries) CancelBookedVenue(ctx context.Context, id uuid.UUID) (BookedVenues, error) {
+	row := q.db.QueryRowContext(ctx, cancelBookedVenue, id)
+	var i BookedVenues
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.VenueID,
+		&i.BookedFor,
+		&i.BookedBy,
+		&i.CreatedAt,
+		&i.Status,
+		&i.CancelledAt,
+	)
+	return i, err
+}
+
+const countBookedVenueDays = `-- name: CountBookedVenueDays :one
+SELECT count(DISTINCT booked_for::date) FROM "bookedVenues"
+WHERE venue_id = $1
+  AND booked_by = $2
+  AND status = 'confirmed'
+  AND booked_for::date BETWEEN $3::date AND $4::date
+`
+
+type CountBookedVenueDaysParams struct {
+	VenueID  uuid.UUID `json:"venue_id"`
+	BookedBy uuid.UUID `json:"booked_by"`
+	FromDate time.Time `json:"from_date"`
+	ToDate   time.Time `json:"to_date"`
+}
+
+func (q *Queries) CountBookedVenueDays(ctx context.Context, arg CountBookedVenueDaysParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countBookedVenueDays,
+		arg.VenueID,
+		arg.BookedBy,
+		arg.FromDate,
+		arg.ToDate,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countBookedVenuesBetween = `-- name: CountBookedVenuesBetween :one
+SELECT count(*) FROM "bookedVenues"
+WHERE venue_id = $1
+  AND booked_for >= $2
+  AND booked_for < $3
+  AND status = 'confirmed'
+`
+
+type CountBookedVenuesBetweenParams struct {
+	VenueID  uuid.UUID `json:"venue_id"`
+	FromTime time.Time `json:"from_time"`
+	ToTime   time.Time `json:"to_time"`
+}
+
+func (q *Queries) CountBookedVenuesBetween(ctx context.Context, arg CountBookedVenuesBetweenParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countBookedVenuesBetween, arg.VenueID, arg.FromTime, arg.ToTime)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createBookedVenue = `-- name: CreateBookedVenue :one
 INSERT INTO "bookedVenues" (
   type,
@@ -21,14 +94,14 @@ INSERT INTO "bookedVenues" (
 ) VALUES (
   $1, $2, $3, $4
 )
-RETURNING id, type, venue_id, booked_for, booked_by, created_at
+RETURNING id, type, venue_id, booked_for, booked_by, created_at, status, cancelled_at
 `
 
 type CreateBookedVenueParams struct {
 	Type      sql.NullString `json:"type"`
-	VenueID   uuid.NullUUID  `json:"venue_id"`
-	BookedFor sql.NullTime   `json:"booked_for"`
-	BookedBy  uuid.NullUUID  `json:"booked_by"`
+	VenueID   uuid.UUID      `json:"venue_id"`
+	BookedFor time.Time      `json:"booked_for"`
+	BookedBy  uuid.UUID      `json:"booked_by"`
 }
 
 func (q *Queries) CreateBookedVenue(ctx context.Context, arg CreateBookedVenueParams) (BookedVenues, error) {
@@ -46,22 +119,14 @@ func (q *Queries) CreateBookedVenue(ctx context.Context, arg CreateBookedVenuePa
 		&i.BookedFor,
 		&i.BookedBy,
 		&i.CreatedAt,
+		&i.Status,
+		&i.CancelledAt,
 	)
 	return i, err
 }
 
-const deleteBookedVenue = `-- name: DeleteBookedVenue :exec
-DELETE FROM "bookedVenues"
-WHERE id = $1
-`
-
-func (q *Queries) DeleteBookedVenue(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteBookedVenue, id)
-	return err
-}
-
 const getBookedVenue = `-- name: GetBookedVenue :one
-SELECT id, type, venue_id, booked_for, booked_by, created_at FROM "bookedVenues"
+SELECT id, type, venue_id, booked_for, booked_by, created_at, status, cancelled_at FROM "bookedVenues"
 WHERE id = $1 LIMIT 1
 `
 
@@ -75,18 +140,82 @@ func (q *Queries) GetBookedVenue(ctx context.Context, id uuid.UUID) (BookedVenue
 		&i.BookedFor,
 		&i.BookedBy,
 		&i.CreatedAt,
+		&i.Status,
+		&i.CancelledAt,
 	)
 	return i, err
 }
 
+const listBookedVenuesBetween = `-- name: ListBookedVenuesBetween :many
+SELECT id, type, venue_id, booked_for, booked_by, created_at, status, cancelled_at FROM "bookedVenues"
+WHERE venue_id = $1
+  AND booked_for >= $2
+  AND booked_for < $3
+  AND status = 'confirmed'
+ORDER BY booked_for
+`
+
+type ListBookedVenuesBetweenParams struct {
+	VenueID  uuid.UUID `json:"venue_id"`
+	FromTime time.Time `json:"from_time"`
+	ToTime   time.Time `json:"to_time"`
+}
+
+func (q *Queries) ListBookedVenuesBetween(ctx context.Context, arg ListBookedVenuesBetweenParams) ([]BookedVenues, error) {
+	rows, err := q.db.QueryContext(ctx, listBookedVenuesBetween, arg.VenueID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BookedVenues
+	for rows.Next() {
+		var i BookedVenues
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.VenueID,
+			&i.BookedFor,
+			&i.BookedBy,
+			&i.CreatedAt,
+			&i.Status,
+			&i.CancelledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listBookedVenuesByUser = `-- name: ListBookedVenuesByUser :many
-SELECT id, type, venue_id, booked_for, booked_by, created_at FROM "bookedVenues"
+SELECT id, type, venue_id, booked_for, booked_by, created_at, status, cancelled_at FROM "bookedVenues"
 WHERE booked_by = $1
-ORDER BY booked_for
+  AND ($2::uuid IS NULL
+    OR (booked_for, id) > ($3::timestamp, $2::uuid))
+ORDER BY booked_for, id
+LIMIT $4
 `
 
-func (q *Queries) ListBookedVenuesByUser(ctx context.Context, bookedBy uuid.NullUUID) ([]BookedVenues, error) {
-	rows, err := q.db.QueryContext(ctx, listBookedVenuesByUser, bookedBy)
+type ListBookedVenuesByUserParams struct {
+	BookedBy        uuid.UUID     `json:"booked_by"`
+	CursorID        uuid.NullUUID `json:"cursor_id"`
+	CursorBookedFor sql.NullTime  `json:"cursor_booked_for"`
+	PageLimit       int32         `json:"page_limit"`
+}
+
+func (q *Queries) ListBookedVenuesByUser(ctx context.Context, arg ListBookedVenuesByUserParams) ([]BookedVenues, error) {
+	rows, err := q.db.QueryContext(ctx, listBookedVenuesByUser,
+		arg.BookedBy,
+		arg.CursorID,
+		arg.CursorBookedFor,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -101,6 +230,8 @@ func (q *Queries) ListBookedVenuesByUser(ctx context.Context, bookedBy uuid.Null
 			&i.BookedFor,
 			&i.BookedBy,
 			&i.CreatedAt,
+			&i.Status,
+			&i.CancelledAt,
 		); err != nil {
 			return nil, err
 		}
@@ -116,13 +247,28 @@ func (q *Queries) ListBookedVenuesByUser(ctx context.Context, bookedBy uuid.Null
 }
 
 const listBookedVenuesByVenue = `-- name: ListBookedVenuesByVenue :many
-SELECT id, type, venue_id, booked_for, booked_by, created_at FROM "bookedVenues"
+SELECT id, type, venue_id, booked_for, booked_by, created_at, status, cancelled_at FROM "bookedVenues"
 WHERE venue_id = $1
-ORDER BY booked_for
+  AND ($2::uuid IS NULL
+    OR (booked_for, id) > ($3::timestamp, $2::uuid))
+ORDER BY booked_for, id
+LIMIT $4
 `
 
-func (q *Queries) ListBookedVenuesByVenue(ctx context.Context, venueID uuid.NullUUID) ([]BookedVenues, error) {
-	rows, err := q.db.QueryContext(ctx, listBookedVenuesByVenue, venueID)
+type ListBookedVenuesByVenueParams struct {
+	VenueID         uuid.UUID     `json:"venue_id"`
+	CursorID        uuid.NullUUID `json:"cursor_id"`
+	CursorBookedFor sql.NullTime  `json:"cursor_booked_for"`
+	PageLimit       int32         `json:"page_limit"`
+}
+
+func (q *Queries) ListBookedVenuesByVenue(ctx context.Context, arg ListBookedVenuesByVenueParams) ([]BookedVenues, error) {
+	rows, err := q.db.QueryContext(ctx, listBookedVenuesByVenue,
+		arg.VenueID,
+		arg.CursorID,
+		arg.CursorBookedFor,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -137,6 +283,8 @@ func (q *Queries) ListBookedVenuesByVenue(ctx context.Context, venueID uuid.Null
 			&i.BookedFor,
 			&i.BookedBy,
 			&i.CreatedAt,
+			&i.Status,
+			&i.CancelledAt,
 		); err != nil {
 			return nil, err
 		}
@@ -158,15 +306,15 @@ UPDATE "bookedVenues"
   booked_for = $4,
   booked_by = $5
 WHERE id = $1
-RETURNING id, type, venue_id, booked_for, booked_by, created_at
+RETURNING id, type, venue_id, booked_for, booked_by, created_at, status, cancelled_at
 `
 
 type UpdateBookedVenueParams struct {
 	ID        uuid.UUID      `json:"id"`
 	Type      sql.NullString `json:"type"`
-	VenueID   uuid.NullUUID  `json:"venue_id"`
-	BookedFor sql.NullTime   `json:"booked_for"`
-	BookedBy  uuid.NullUUID  `json:"booked_by"`
+	VenueID   uuid.UUID      `json:"venue_id"`
+	BookedFor time.Time      `json:"booked_for"`
+	BookedBy  uuid.UUID      `json:"booked_by"`
 }
 
 func (q *Queries) UpdateBookedVenue(ctx context.Context, arg UpdateBookedVenueParams) (BookedVenues, error) {
@@ -185,6 +333,8 @@ func (q *Queries) UpdateBookedVenue(ctx context.Context, arg UpdateBookedVenuePa
 		&i.BookedFor,
 		&i.BookedBy,
 		&i.CreatedAt,
+		&i.Status,
+		&i.CancelledAt,
 	)
 	return i, err
 }
@@ -0,0 +1,131 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: cancellations.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const createCancellation = `-- name: CreateCancellation :one
+INSERT INTO "cancellations" (
+  purchase_id,
+  booked_venue_id,
+  booked_practitioner_id,
+  cancelled_by,
+  reason,
+  notice_hours,
+  refund_amount,
+  currency,
+  refund_status
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING id, purchase_id, booked_venue_id, booked_practitioner_id, cancelled_by, reason, notice_hours, refund_amount, currency, refund_status, provider_refund_id, created_at
+`
+
+type CreateCancellationParams struct {
+	PurchaseID           uuid.UUID      `json:"purchase_id"`
+	BookedVenueID        uuid.NullUUID  `json:"booked_venue_id"`
+	BookedPractitionerID uuid.NullUUID  `json:"booked_practitioner_id"`
+	CancelledBy          uuid.UUID      `json:"cancelled_by"`
+	Reason               sql.NullString `json:"reason"`
+	NoticeHours          int32          `json:"notice_hours"`
+	RefundAmount         int32          `json:"refund_amount"`
+	Currency             string         `json:"currency"`
+	RefundStatus         string         `json:"refund_status"`
+}
+
+func (q *Queries) CreateCancellation(ctx context.Context, arg CreateCancellationParams) (Cancellations, error) {
+	row := q.db.QueryRowContext(ctx, createCancellation,
+		arg.PurchaseID,
+		arg.BookedVenueID,
+		arg.BookedPractitionerID,
+		arg.CancelledBy,
+		arg.Reason,
+		arg.NoticeHours,
+		arg.RefundAmount,
+		arg.Currency,
+		arg.RefundStatus,
+	)
+	var i Cancellations
+	err := row.Scan(
+		&i.ID,
+		&i.PurchaseID,
+		&i.BookedVenueID,
+		&i.BookedPractitionerID,
+		&i.CancelledBy,
+		&i.Reason,
+		&i.NoticeHours,
+		&i.RefundAmount,
+		&i.Currency,
+		&i.RefundStatus,
+		&i.ProviderRefundID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getCancellationByPurchase = `-- name: GetCancellationByPurchase :one
+SELECT id, purchase_id, booked_venue_id, booked_practitioner_id, cancelled_by, reason, notice_hours, refund_amount, currency, refund_status, provider_refund_id, created_at FROM "cancellations"
+WHERE purchase_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetCancellationByPurchase(ctx context.Context, purchaseID uuid.UUID) (Cancellations, error) {
+	row := q.db.QueryRowContext(ctx, getCancellationByPurchase, purchaseID)
+	var i Cancellations
+	err := row.Scan(
+		&i.ID,
+		&i.PurchaseID,
+		&i.BookedVenueID,
+		&i.BookedPractitionerID,
+		&i.CancelledBy,
+		&i.Reason,
+		&i.NoticeHours,
+		&i.RefundAmount,
+		&i.Currency,
+		&i.RefundStatus,
+		&i.ProviderRefundID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const recordCancellationRefund = `-- name: RecordCancellationRefund :one
+UPDATE "cancellations"
+SET refund_status = $1,
+    provider_refund_id = $2
+WHERE id = $3 AND refund_status = 'pending'
+RETURNING id, purchase_id, booked_venue_id, booked_practitioner_id, cancelled_by, reason, notice_hours, refund_amount, currency, refund_status, provider_refund_id, created_at
+`
+
+type RecordCancellationRefundParams struct {
+	RefundStatus     string         `json:"refund_status"`
+	ProviderRefundID sql.NullString `json:"provider_refund_id"`
+	ID               uuid.UUID      `json:"id"`
+}
+
+func (q *Queries) RecordCancellationRefund(ctx context.Context, arg RecordCancellationRefundParams) (Cancellations, error) {
+	row := q.db.QueryRowContext(ctx, recordCancellationRefund, arg.RefundStatus, arg.ProviderRefundID, arg.ID)
+	var i Cancellations
+	err := row.Scan(
+		&i.ID,
+		&i.PurchaseID,
+		&i.BookedVenueID,
+		&i.BookedPractitionerID,
+		&i.CancelledBy,
+		&i.Reason,
+		&i.NoticeHours,
+		&i.RefundAmount,
+		&i.Currency,
+		&i.RefundStatus,
+		&i.ProviderRefundID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
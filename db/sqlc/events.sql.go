@@ -7,10 +7,122 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const countFavouritesByEvents = `-- name: CountFavouritesByEvents :many
+SELECT event_id, count(*) AS favourite_count FROM favourites
+WHERE event_id = ANY($1::uuid[])
+GROUP BY event_id
+`
+
+type CountFavouritesByEventsRow struct {
+	EventID        uuid.NullUUID `json:"event_id"`
+	FavouriteCount int64         `json:"favourite_count"`
+}
+
+func (q *Queries) CountFavouritesByEvents(ctx context.Context, eventIds []uuid.UUID) ([]CountFavouritesByEventsRow, error) {
+	rows, err := q.db.QueryContext(ctx, countFavouritesByEvents, pq.Array(eventIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountFavouritesByEventsRow
+	for rows.Next() {
+		var i CountFavouritesByEventsRow
+		if err := rows.Scan(&i.EventID, &i.FavouriteCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createEvent = `-- name: CreateEvent :one
+INSERT INTO "events" (
+  venue_id,
+  image_links,
+  name,
+  theme,
+  description,
+  audience,
+  activities,
+  created_by,
+  start_time,
+  start_date,
+  end_date,
+  total_particpant
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+)
+RETURNING id, venue_id, image_links, name, theme, description, audience, activities, created_by, start_time, start_date, end_date, total_particpant, created_at, status, search_vector, full_refund_hours, no_refund_hours, partial_refund_percent
+`
+
+type CreateEventParams struct {
+	VenueID         uuid.NullUUID  `json:"venue_id"`
+	ImageLinks      []string       `json:"image_links"`
+	Name            sql.NullString `json:"name"`
+	Theme           sql.NullString `json:"theme"`
+	Description     sql.NullString `json:"description"`
+	Audience        sql.NullString `json:"audience"`
+	Activities      []string       `json:"activities"`
+	CreatedBy       uuid.NullUUID  `json:"created_by"`
+	StartTime       sql.NullTime   `json:"start_time"`
+	StartDate       time.Time      `json:"start_date"`
+	EndDate         sql.NullTime   `json:"end_date"`
+	TotalParticpant sql.NullInt32  `json:"total_particpant"`
+}
+
+func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) (Events, error) {
+	row := q.db.QueryRowContext(ctx, createEvent,
+		arg.VenueID,
+		pq.Array(arg.ImageLinks),
+		arg.Name,
+		arg.Theme,
+		arg.Description,
+		arg.Audience,
+		pq.Array(arg.Activities),
+		arg.CreatedBy,
+		arg.StartTime,
+		arg.StartDate,
+		arg.EndDate,
+		arg.TotalParticpant,
+	)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.VenueID,
+		pq.Array(&i.ImageLinks),
+		&i.Name,
+		&i.Theme,
+		&i.Description,
+		&i.Audience,
+		pq.Array(&i.Activities),
+		&i.CreatedBy,
+		&i.StartTime,
+		&i.StartDate,
+		&i.EndDate,
+		&i.TotalParticpant,
+		&i.CreatedAt,
+		&i.Status,
+		&i.SearchVector,
+		&i.FullRefundHours,
+		&i.NoRefundHours,
+		&i.PartialRefundPercent,
+	)
+	return i, err
+}
+
 const createFavourite = `-- name: CreateFavourite :one
 INSERT INTO "favourites" (
   event_id,
@@ -18,6 +130,8 @@ INSERT INTO "favourites" (
 ) VALUES (
   $1, $2
 )
+ON CONFLICT (event_id, added_by) DO UPDATE
+  set event_id = EXCLUDED.event_id
 RETURNING id, event_id, added_by, created_at
 `
 
@@ -26,6 +140,7 @@ type CreateFavouriteParams struct {
 	AddedBy uuid.NullUUID `json:"added_by"`
 }
 
+// Favouriting is idempotent: an existing favourite is returned unchanged.
 func (q *Queries) CreateFavourite(ctx context.Context, arg CreateFavouriteParams) (Favourites, error) {
 	row := q.db.QueryRowContext(ctx, createFavourite, arg.EventID, arg.AddedBy)
 	var i Favourites
@@ -38,6 +153,16 @@ func (q *Queries) CreateFavourite(ctx context.Context, arg CreateFavouriteParams
 	return i, err
 }
 
+const deleteEvent = `-- name: DeleteEvent :exec
+DELETE FROM events
+WHERE id = $1
+`
+
+func (q *Queries) DeleteEvent(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteEvent, id)
+	return err
+}
+
 const deleteFavourite = `-- name: DeleteFavourite :exec
 DELETE FROM favourites
 WHERE id = $1
@@ -63,6 +188,71 @@ func (q *Queries) DeleteFavouriteByUserAndEvent(ctx context.Context, arg DeleteF
 	return err
 }
 
+const getEvent = `-- name: GetEvent :one
+SELECT id, venue_id, image_links, name, theme, description, audience, activities, created_by, start_time, start_date, end_date, total_particpant, created_at, status, search_vector, full_refund_hours, no_refund_hours, partial_refund_percent FROM events
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetEvent(ctx context.Context, id uuid.UUID) (Events, error) {
+	row := q.db.QueryRowContext(ctx, getEvent, id)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.VenueID,
+		pq.Array(&i.ImageLinks),
+		&i.Name,
+		&i.Theme,
+		&i.Description,
+		&i.Audience,
+		pq.Array(&i.Activities),
+		&i.CreatedBy,
+		&i.StartTime,
+		&i.StartDate,
+		&i.EndDate,
+		&i.TotalParticpant,
+		&i.CreatedAt,
+		&i.Status,
+		&i.SearchVector,
+		&i.FullRefundHours,
+		&i.NoRefundHours,
+		&i.PartialRefundPercent,
+	)
+	return i, err
+}
+
+const getEventForUpdate = `-- name: GetEventForUpdate :one
+SELECT id, venue_id, image_links, name, theme, description, audience, activities, created_by, start_time, start_date, end_date, total_particpant, created_at, status, search_vector, full_refund_hours, no_refund_hours, partial_refund_percent FROM events
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetEventForUpdate(ctx context.Context, id uuid.UUID) (Events, error) {
+	row := q.db.QueryRowContext(ctx, getEventForUpdate, id)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.VenueID,
+		pq.Array(&i.ImageLinks),
+		&i.Name,
+		&i.Theme,
+		&i.Description,
+		&i.Audience,
+		pq.Array(&i.Activities),
+		&i.CreatedBy,
+		&i.StartTime,
+		&i.StartDate,
+		&i.EndDate,
+		&i.TotalParticpant,
+		&i.CreatedAt,
+		&i.Status,
+		&i.SearchVector,
+		&i.FullRefundHours,
+		&i.NoRefundHours,
+		&i.PartialRefundPercent,
+	)
+	return i, err
+}
+
 const getFavourite = `-- name: GetFavourite :one
 SELECT id, event_id, added_by, created_at FROM favourites
 WHERE id = $1 LIMIT 1
@@ -80,6 +270,204 @@ func (q *Queries) GetFavourite(ctx context.Context, id uuid.UUID) (Favourites, e
 	return i, err
 }
 
+const listEvents = `-- name: ListEvents :many
+SELECT id, venue_id, image_links, name, theme, description, audience, activities, created_by, start_time, start_date, end_date, total_particpant, created_at, status, search_vector, full_refund_hours, no_refund_hours, partial_refund_percent FROM events
+WHERE status <> 'draft'
+  AND ($1::varchar IS NULL OR status = $1)
+  AND ($2::uuid IS NULL
+    OR ($3::text = 'newest'
+      AND (created_at, id) < ($4::timestamp, $2::uuid))
+    OR ($3::text = 'start'
+      AND (start_date, id) > ($5::date, $2::uuid)))
+ORDER BY
+  CASE WHEN $3::text = 'newest' THEN created_at END DESC,
+  CASE WHEN $3::text = 'newest' THEN id END DESC,
+  start_date, id
+LIMIT $6
+`
+
+type ListEventsParams struct {
+	Status          sql.NullString `json:"status"`
+	CursorID        uuid.NullUUID  `json:"cursor_id"`
+	Sort            string         `json:"sort"`
+	CursorCreatedAt sql.NullTime   `json:"cursor_created_at"`
+	CursorStartDate sql.NullTime   `json:"cursor_start_date"`
+	PageLimit       int32          `json:"page_limit"`
+}
+
+func (q *Queries) ListEvents(ctx context.Context, arg ListEventsParams) ([]Events, error) {
+	rows, err := q.db.QueryContext(ctx, listEvents,
+		arg.Status,
+		arg.CursorID,
+		arg.Sort,
+		arg.CursorCreatedAt,
+		arg.CursorStartDate,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Events
+	for rows.Next() {
+		var i Events
+		if err := rows.Scan(
+			&i.ID,
+			&i.VenueID,
+			pq.Array(&i.ImageLinks),
+			&i.Name,
+			&i.Theme,
+			&i.Description,
+			&i.Audience,
+			pq.Array(&i.Activities),
+			&i.CreatedBy,
+			&i.StartTime,
+			&i.StartDate,
+			&i.EndDate,
+			&i.TotalParticpant,
+			&i.CreatedAt,
+			&i.Status,
+			&i.SearchVector,
+			&i.FullRefundHours,
+			&i.NoRefundHours,
+			&i.PartialRefundPercent,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEventsByCreator = `-- name: ListEventsByCreator :many
+SELECT id, venue_id, image_links, name, theme, description, audience, activities, created_by, start_time, start_date, end_date, total_particpant, created_at, status, search_vector, full_refund_hours, no_refund_hours, partial_refund_percent FROM events
+WHERE created_by = $1
+ORDER BY start_date, start_time
+`
+
+func (q *Queries) ListEventsByCreator(ctx context.Context, createdBy uuid.NullUUID) ([]Events, error) {
+	rows, err := q.db.QueryContext(ctx, listEventsByCreator, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Events
+	for rows.Next() {
+		var i Events
+		if err := rows.Scan(
+			&i.ID,
+			&i.VenueID,
+			pq.Array(&i.ImageLinks),
+			&i.Name,
+			&i.Theme,
+			&i.Description,
+			&i.Audience,
+			pq.Array(&i.Activities),
+			&i.CreatedBy,
+			&i.StartTime,
+			&i.StartDate,
+			&i.EndDate,
+			&i.TotalParticpant,
+			&i.CreatedAt,
+			&i.Status,
+			&i.SearchVector,
+			&i.FullRefundHours,
+			&i.NoRefundHours,
+			&i.PartialRefundPercent,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFavouriteEventsByUser = `-- name: ListFavouriteEventsByUser :many
+SELECT events.id, events.venue_id, events.image_links, events.name, events.theme, events.description, events.audience, events.activities, events.created_by, events.start_time, events.start_date, events.end_date, events.total_particpant, events.created_at, events.status, events.search_vector, events.full_refund_hours, events.no_refund_hours, events.partial_refund_percent, favourites.id AS favourite_id, favourites.created_at AS favourited_at FROM favourites
+JOIN events ON events.id = favourites.event_id
+WHERE favourites.added_by = $1
+  AND (events.status <> 'draft' OR events.created_by = favourites.added_by)
+  AND ($2::uuid IS NULL
+    OR (favourites.created_at, favourites.id) < ($3::timestamp, $2::uuid))
+ORDER BY favourites.created_at DESC, favourites.id DESC
+LIMIT $4
+`
+
+type ListFavouriteEventsByUserParams struct {
+	AddedBy         uuid.NullUUID `json:"added_by"`
+	CursorID        uuid.NullUUID `json:"cursor_id"`
+	CursorCreatedAt sql.NullTime  `json:"cursor_created_at"`
+	PageLimit       int32         `json:"page_limit"`
+}
+
+type ListFavouriteEventsByUserRow struct {
+	Events       Events    `json:"events"`
+	FavouriteID  uuid.UUID `json:"favourite_id"`
+	FavouritedAt time.Time `json:"favourited_at"`
+}
+
+func (q *Queries) ListFavouriteEventsByUser(ctx context.Context, arg ListFavouriteEventsByUserParams) ([]ListFavouriteEventsByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFavouriteEventsByUser,
+		arg.AddedBy,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFavouriteEventsByUserRow
+	for rows.Next() {
+		var i ListFavouriteEventsByUserRow
+		if err := rows.Scan(
+			&i.Events.ID,
+			&i.Events.VenueID,
+			pq.Array(&i.Events.ImageLinks),
+			&i.Events.Name,
+			&i.Events.Theme,
+			&i.Events.Description,
+			&i.Events.Audience,
+			pq.Array(&i.Events.Activities),
+			&i.Events.CreatedBy,
+			&i.Events.StartTime,
+			&i.Events.StartDate,
+			&i.Events.EndDate,
+			&i.Events.TotalParticpant,
+			&i.Events.CreatedAt,
+			&i.Events.Status,
+			&i.Events.SearchVector,
+			&i.Events.FullRefundHours,
+			&i.Events.NoRefundHours,
+			&i.Events.PartialRefundPercent,
+			&i.FavouriteID,
+			&i.FavouritedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listFavouritesByEvent = `-- name: ListFavouritesByEvent :many
 SELECT id, event_id, added_by, created_at FROM favourites
 WHERE event_id = $1
@@ -147,3 +535,162 @@ func (q *Queries) ListFavouritesByUser(ctx context.Context, addedBy uuid.NullUUI
 	}
 	return items, nil
 }
+
+const updateEvent = `-- name: UpdateEvent :one
+UPDATE events
+  set venue_id = $2,
+  image_links = $3,
+  name = $4,
+  theme = $5,
+  description = $6,
+  audience = $7,
+  activities = $8,
+  start_time = $9,
+  start_date = $10,
+  end_date = $11,
+  total_particpant = $12
+WHERE id = $1
+RETURNING id, venue_id, image_links, name, theme, description, audience, activities, created_by, start_time, start_date, end_date, total_particpant, created_at, status, search_vector, full_refund_hours, no_refund_hours, partial_refund_percent
+`
+
+type UpdateEventParams struct {
+	ID              uuid.UUID      `json:"id"`
+	VenueID         uuid.NullUUID  `json:"venue_id"`
+	ImageLinks      []string       `json:"image_links"`
+	Name            sql.NullString `json:"name"`
+	Theme           sql.NullString `json:"theme"`
+	Description     sql.NullString `json:"description"`
+	Audience        sql.NullString `json:"audience"`
+	Activities      []string       `json:"activities"`
+	StartTime       sql.NullTime   `json:"start_time"`
+	StartDate       time.Time      `json:"start_date"`
+	EndDate         sql.NullTime   `json:"end_date"`
+	TotalParticpant sql.NullInt32  `json:"total_particpant"`
+}
+
+func (q *Queries) UpdateEvent(ctx context.Context, arg UpdateEventParams) (Events, error) {
+	row := q.db.QueryRowContext(ctx, updateEvent,
+		arg.ID,
+		arg.VenueID,
+		pq.Array(arg.ImageLinks),
+		arg.Name,
+		arg.Theme,
+		arg.Description,
+		arg.Audience,
+		pq.Array(arg.Activities),
+		arg.StartTime,
+		arg.StartDate,
+		arg.EndDate,
+		arg.TotalParticpant,
+	)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.VenueID,
+		pq.Array(&i.ImageLinks),
+		&i.Name,
+		&i.Theme,
+		&i.Description,
+		&i.Audience,
+		pq.Array(&i.Activities),
+		&i.CreatedBy,
+		&i.StartTime,
+		&i.StartDate,
+		&i.EndDate,
+		&i.TotalParticpant,
+		&i.CreatedAt,
+		&i.Status,
+		&i.SearchVector,
+		&i.FullRefundHours,
+		&i.NoRefundHours,
+		&i.PartialRefundPercent,
+	)
+	return i, err
+}
+
+const updateEventCancellationPolicy = `-- name: UpdateEventCancellationPolicy :one
+UPDATE events
+SET full_refund_hours = $1,
+    no_refund_hours = $2,
+    partial_refund_percent = $3
+WHERE id = $4
+RETURNING id, venue_id, image_links, name, theme, description, audience, activities, created_by, start_time, start_date, end_date, total_particpant, created_at, status, search_vector, full_refund_hours, no_refund_hours, partial_refund_percent
+`
+
+type UpdateEventCancellationPolicyParams struct {
+	FullRefundHours      int32     `json:"full_refund_hours"`
+	NoRefundHours        int32     `json:"no_refund_hours"`
+	PartialRefundPercent int32     `json:"partial_refund_percent"`
+	ID                   uuid.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateEventCancellationPolicy(ctx context.Context, arg UpdateEventCancellationPolicyParams) (Events, error) {
+	row := q.db.QueryRowContext(ctx, updateEventCancellationPolicy,
+		arg.FullRefundHours,
+		arg.NoRefundHours,
+		arg.PartialRefundPercent,
+		arg.ID,
+	)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.VenueID,
+		pq.Array(&i.ImageLinks),
+		&i.Name,
+		&i.Theme,
+		&i.Description,
+		&i.Audience,
+		pq.Array(&i.Activities),
+		&i.CreatedBy,
+		&i.StartTime,
+		&i.StartDate,
+		&i.EndDate,
+		&i.TotalParticpant,
+		&i.CreatedAt,
+		&i.Status,
+		&i.SearchVector,
+		&i.FullRefundHours,
+		&i.NoRefundHours,
+		&i.PartialRefundPercent,
+	)
+	return i, err
+}
+
+const updateEventStatus = `-- name: UpdateEventStatus :one
+UPDATE events
+  set status = $2
+WHERE id = $1
+RETURNING id, venue_id, image_links, name, theme, description, audience, activities, created_by, start_time, start_date, end_date, total_particpant, created_at, status, search_vector, full_refund_hours, no_refund_hours, partial_refund_percent
+`
+
+type UpdateEventStatusParams struct {
+	ID     uuid.UUID `json:"id"`
+	Status string    `json:"status"`
+}
+
+func (q *Queries) UpdateEventStatus(ctx context.Context, arg UpdateEventStatusParams) (Events, error) {
+	row := q.db.QueryRowContext(ctx, updateEventStatus, arg.ID, arg.Status)
+	var i Events
+	err := row.Scan(
+		&i.ID,
+		&i.VenueID,
+		pq.Array(&i.ImageLinks),
+		&i.Name,
+		&i.Theme,
+		&i.Description,
+		&i.Audience,
+		pq.Array(&i.Activities),
+		&i.CreatedBy,
+		&i.StartTime,
+		&i.StartDate,
+		&i.EndDate,
+		&i.TotalParticpant,
+		&i.CreatedAt,
+		&i.Status,
+		&i.SearchVector,
+		&i.FullRefundHours,
+		&i.NoRefundHours,
+		&i.PartialRefundPercent,
+	)
+	return i, err
+}
@@ -0,0 +1,97 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: exchange_rates.sql
+
+package db
+
+import (
+	"context"
+)
+
+const getExchangeRate = `-- name: GetExchangeRate :one
+SELECT base, quote, rate, updated_at FROM "exchange_rates"
+WHERE base = $1 AND quote = $2 LIMIT 1
+`
+
+type GetExchangeRateParams struct {
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
+}
+
+func (q *Queries) GetExchangeRate(ctx context.Context, arg GetExchangeRateParams) (ExchangeRates, error) {
+	row := q.db.QueryRowContext(ctx, getExchangeRate, arg.Base, arg.Quote)
+	var i ExchangeRates
+	err := row.Scan(
+		&i.Base,
+		&i.Quote,
+		&i.Rate,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listExchangeRates = `-- name: ListExchangeRates :many
+SELECT base, quote, rate, updated_at FROM "exchange_rates"
+ORDER BY base, quote
+`
+
+func (q *Queries) ListExchangeRates(ctx context.Context) ([]ExchangeRates, error) {
+	rows, err := q.db.QueryContext(ctx, listExchangeRates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExchangeRates
+	for rows.Next() {
+		var i ExchangeRates
+		if err := rows.Scan(
+			&i.Base,
+			&i.Quote,
+			&i.Rate,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertExchangeRate = `-- name: UpsertExchangeRate :one
+INSERT INTO "exchange_rates" (
+  base,
+  quote,
+  rate
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (base, quote) DO UPDATE
+SET rate = EXCLUDED.rate,
+    updated_at = now()
+RETURNING base, quote, rate, updated_at
+`
+
+type UpsertExchangeRateParams struct {
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
+	Rate  string `json:"rate"`
+}
+
+func (q *Queries) UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) (ExchangeRates, error) {
+	row := q.db.QueryRowContext(ctx, upsertExchangeRate, arg.Base, arg.Quote, arg.Rate)
+	var i ExchangeRates
+	err := row.Scan(
+		&i.Base,
+		&i.Quote,
+		&i.Rate,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
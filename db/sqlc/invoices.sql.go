@@ -0,0 +1,254 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: invoices.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const createInvoice = `-- name: CreateInvoice :one
+INSERT INTO "invoices" (
+  purchase_id,
+  seller_id,
+  buyer_id,
+  sequence,
+  number,
+  seller_name,
+  seller_address,
+  seller_country,
+  buyer_name,
+  buyer_address,
+  buyer_country,
+  currency,
+  subtotal,
+  tax_amount,
+  total
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+)
+RETURNING id, purchase_id, seller_id, buyer_id, sequence, number, seller_name, seller_address, seller_country, buyer_name, buyer_address, buyer_country, currency, subtotal, tax_amount, total, issued_at
+`
+
+type CreateInvoiceParams struct {
+	PurchaseID    uuid.UUID      `json:"purchase_id"`
+	SellerID      uuid.UUID      `json:"seller_id"`
+	BuyerID       uuid.NullUUID  `json:"buyer_id"`
+	Sequence      int64          `json:"sequence"`
+	Number        string         `json:"number"`
+	SellerName    string         `json:"seller_name"`
+	SellerAddress sql.NullString `json:"seller_address"`
+	SellerCountry sql.NullString `json:"seller_country"`
+	BuyerName     string         `json:"buyer_name"`
+	BuyerAddress  sql.NullString `json:"buyer_address"`
+	BuyerCountry  sql.NullString `json:"buyer_country"`
+	Currency      string         `json:"currency"`
+	Subtotal      int32          `json:"subtotal"`
+	TaxAmount     int32          `json:"tax_amount"`
+	Total         int32          `json:"total"`
+}
+
+func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (Invoices, error) {
+	row := q.db.QueryRowContext(ctx, createInvoice,
+		arg.PurchaseID,
+		arg.SellerID,
+		arg.BuyerID,
+		arg.Sequence,
+		arg.Number,
+		arg.SellerName,
+		arg.SellerAddress,
+		arg.SellerCountry,
+		arg.BuyerName,
+		arg.BuyerAddress,
+		arg.BuyerCountry,
+		arg.Currency,
+		arg.Subtotal,
+		arg.TaxAmount,
+		arg.Total,
+	)
+	var i Invoices
+	err := row.Scan(
+		&i.ID,
+		&i.PurchaseID,
+		&i.SellerID,
+		&i.BuyerID,
+		&i.Sequence,
+		&i.Number,
+		&i.SellerName,
+		&i.SellerAddress,
+		&i.SellerCountry,
+		&i.BuyerName,
+		&i.BuyerAddress,
+		&i.BuyerCountry,
+		&i.Currency,
+		&i.Subtotal,
+		&i.TaxAmount,
+		&i.Total,
+		&i.IssuedAt,
+	)
+	return i, err
+}
+
+const createInvoiceLine = `-- name: CreateInvoiceLine :one
+INSERT INTO "invoice_lines" (
+  invoice_id,
+  position,
+  kind,
+  description,
+  quantity,
+  unit_amount,
+  amount,
+  tax_rate
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING id, invoice_id, position, kind, description, quantity, unit_amount, amount, tax_rate
+`
+
+type CreateInvoiceLineParams struct {
+	InvoiceID   uuid.UUID      `json:"invoice_id"`
+	Position    int32          `json:"position"`
+	Kind        string         `json:"kind"`
+	Description string         `json:"description"`
+	Quantity    int32          `json:"quantity"`
+	UnitAmount  int32          `json:"unit_amount"`
+	Amount      int32          `json:"amount"`
+	TaxRate     sql.NullString `json:"tax_rate"`
+}
+
+func (q *Queries) CreateInvoiceLine(ctx context.Context, arg CreateInvoiceLineParams) (InvoiceLines, error) {
+	row := q.db.QueryRowContext(ctx, createInvoiceLine,
+		arg.InvoiceID,
+		arg.Position,
+		arg.Kind,
+		arg.Description,
+		arg.Quantity,
+		arg.UnitAmount,
+		arg.Amount,
+		arg.TaxRate,
+	)
+	var i InvoiceLines
+	err := row.Scan(
+		&i.ID,
+		&i.InvoiceID,
+		&i.Position,
+		&i.Kind,
+		&i.Description,
+		&i.Quantity,
+		&i.UnitAmount,
+		&i.Amount,
+		&i.TaxRate,
+	)
+	return i, err
+}
+
+const getInvoiceByPurchase = `-- name: GetInvoiceByPurchase :one
+SELECT id, purchase_id, seller_id, buyer_id, sequence, number, seller_name, seller_address, seller_country, buyer_name, buyer_address, buyer_country, currency, subtotal, tax_amount, total, issued_at FROM "invoices"
+WHERE purchase_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetInvoiceByPurchase(ctx context.Context, purchaseID uuid.UUID) (Invoices, error) {
+	row := q.db.QueryRowContext(ctx, getInvoiceByPurchase, purchaseID)
+	var i Invoices
+	err := row.Scan(
+		&i.ID,
+		&i.PurchaseID,
+		&i.SellerID,
+		&i.BuyerID,
+		&i.Sequence,
+		&i.Number,
+		&i.SellerName,
+		&i.SellerAddress,
+		&i.SellerCountry,
+		&i.BuyerName,
+		&i.BuyerAddress,
+		&i.BuyerCountry,
+		&i.Currency,
+		&i.Subtotal,
+		&i.TaxAmount,
+		&i.Total,
+		&i.IssuedAt,
+	)
+	return i, err
+}
+
+const getTicketByPurchase = `-- name: GetTicketByPurchase :one
+SELECT id, purchase_id, tier_id, code, created_at FROM "tickets"
+WHERE purchase_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetTicketByPurchase(ctx context.Context, purchaseID uuid.UUID) (Tickets, error) {
+	row := q.db.QueryRowContext(ctx, getTicketByPurchase, purchaseID)
+	var i Tickets
+	err := row.Scan(
+		&i.ID,
+		&i.PurchaseID,
+		&i.TierID,
+		&i.Code,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listInvoiceLines = `-- name: ListInvoiceLines :many
+SELECT id, invoice_id, position, kind, description, quantity, unit_amount, amount, tax_rate FROM "invoice_lines"
+WHERE invoice_id = $1
+ORDER BY position
+`
+
+func (q *Queries) ListInvoiceLines(ctx context.Context, invoiceID uuid.UUID) ([]InvoiceLines, error) {
+	rows, err := q.db.QueryContext(ctx, listInvoiceLines, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InvoiceLines
+	for rows.Next() {
+		var i InvoiceLines
+		if err := rows.Scan(
+			&i.ID,
+			&i.InvoiceID,
+			&i.Position,
+			&i.Kind,
+			&i.Description,
+			&i.Quantity,
+			&i.UnitAmount,
+			&i.Amount,
+			&i.TaxRate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const nextInvoiceNumber = `-- name: NextInvoiceNumber :one
+INSERT INTO "invoice_sequences" (
+  profile_id,
+  last_number
+) VALUES (
+  $1, 1
+)
+ON CONFLICT (profile_id) DO UPDATE
+SET last_number = "invoice_sequences".last_number + 1
+RETURNING last_number
+`
+
+func (q *Queries) NextInvoiceNumber(ctx context.Context, profileID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, nextInvoiceNumber, profileID)
+	var last_number int64
+	err := row.Scan(&last_number)
+	return last_number, err
+}
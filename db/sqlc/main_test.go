@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"os"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/tedobanks/tabularasa_backend/db/migrate"
+	"github.com/tedobanks/tabularasa_backend/db/migrations"
+)
+
+// testStore is backed by the database at TEST_DB_SOURCE, migrated to the
+// latest version. It is nil when TEST_DB_SOURCE is not set.
+var testStore Store
+
+// requireDB skips tests that need a database when none is configured.
+func requireDB(t *testing.T) {
+	if testStore == nil {
+		t.Skip("TEST_DB_SOURCE is not set")
+	}
+}
+
+func TestMain(m *testing.M) {
+	source := os.Getenv("TEST_DB_SOURCE")
+	if source == "" {
+		os.Exit(m.Run())
+	}
+
+	conn, err := sql.Open("postgres", source)
+	if err != nil {
+		log.Fatal("cannot connect to db:", err)
+	}
+
+	migrator, err := migrate.New(conn, migrations.FS)
+	if err != nil {
+		log.Fatal("cannot load migrations:", err)
+	}
+	if err := migrator.Up(context.Background()); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		log.Fatal("cannot migrate db:", err)
+	}
+
+	testStore = NewStore(conn)
+	os.Exit(m.Run())
+}
@@ -6,63 +6,154 @@ package db
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type BookedPractitioners struct {
-	ID        uuid.UUID      `json:"id"`
-	Type      sql.NullString `json:"type"`
-	ServiceID uuid.NullUUID  `json:"service_id"`
-	BookedFor sql.NullTime   `json:"booked_for"`
-	BookedBy  uuid.NullUUID  `json:"booked_by"`
-	CreatedAt sql.NullTime   `json:"created_at"`
+	ID          uuid.UUID      `json:"id"`
+	Type        sql.NullString `json:"type"`
+	ServiceID   uuid.UUID      `json:"service_id"`
+	BookedFor   time.Time      `json:"booked_for"`
+	BookedBy    uuid.UUID      `json:"booked_by"`
+	CreatedAt   sql.NullTime   `json:"created_at"`
+	Status      string         `json:"status"`
+	CancelledAt sql.NullTime   `json:"cancelled_at"`
 }
 
 type BookedVenues struct {
-	ID        uuid.UUID      `json:"id"`
-	Type      sql.NullString `json:"type"`
-	VenueID   uuid.NullUUID  `json:"venue_id"`
-	BookedFor sql.NullTime   `json:"booked_for"`
-	BookedBy  uuid.NullUUID  `json:"booked_by"`
-	CreatedAt sql.NullTime   `json:"created_at"`
+	ID          uuid.UUID      `json:"id"`
+	Type        sql.NullString `json:"type"`
+	VenueID     uuid.UUID      `json:"venue_id"`
+	BookedFor   time.Time      `json:"booked_for"`
+	BookedBy    uuid.UUID      `json:"booked_by"`
+	CreatedAt   sql.NullTime   `json:"created_at"`
+	Status      string         `json:"status"`
+	CancelledAt sql.NullTime   `json:"cancelled_at"`
+}
+
+type Cancellations struct {
+	ID                   uuid.UUID      `json:"id"`
+	PurchaseID           uuid.UUID      `json:"purchase_id"`
+	BookedVenueID        uuid.NullUUID  `json:"booked_venue_id"`
+	BookedPractitionerID uuid.NullUUID  `json:"booked_practitioner_id"`
+	CancelledBy          uuid.UUID      `json:"cancelled_by"`
+	Reason               sql.NullString `json:"reason"`
+	NoticeHours          int32          `json:"notice_hours"`
+	RefundAmount         int32          `json:"refund_amount"`
+	Currency             string         `json:"currency"`
+	RefundStatus         string         `json:"refund_status"`
+	ProviderRefundID     sql.NullString `json:"provider_refund_id"`
+	CreatedAt            time.Time      `json:"created_at"`
 }
 
 type Events struct {
-	ID              uuid.UUID      `json:"id"`
-	VenueID         uuid.NullUUID  `json:"venue_id"`
-	ImageLinks      []string       `json:"image_links"`
-	Name            sql.NullString `json:"name"`
-	Theme           sql.NullString `json:"theme"`
-	Description     sql.NullString `json:"description"`
-	Audience        sql.NullString `json:"audience"`
-	Activities      []string       `json:"activities"`
-	CreatedBy       uuid.NullUUID  `json:"created_by"`
-	StartTime       sql.NullTime   `json:"start_time"`
-	StartDate       sql.NullTime   `json:"start_date"`
-	EndDate         sql.NullTime   `json:"end_date"`
-	TotalParticpant sql.NullInt32  `json:"total_particpant"`
-	CreatedAt       sql.NullTime   `json:"created_at"`
+	ID                   uuid.UUID      `json:"id"`
+	VenueID              uuid.NullUUID  `json:"venue_id"`
+	ImageLinks           []string       `json:"image_links"`
+	Name                 sql.NullString `json:"name"`
+	Theme                sql.NullString `json:"theme"`
+	Description          sql.NullString `json:"description"`
+	Audience             sql.NullString `json:"audience"`
+	Activities           []string       `json:"activities"`
+	CreatedBy            uuid.NullUUID  `json:"created_by"`
+	StartTime            sql.NullTime   `json:"start_time"`
+	StartDate            time.Time      `json:"start_date"`
+	EndDate              sql.NullTime   `json:"end_date"`
+	TotalParticpant      sql.NullInt32  `json:"total_particpant"`
+	CreatedAt            time.Time      `json:"created_at"`
+	Status               string         `json:"status"`
+	SearchVector         string         `json:"-"`
+	FullRefundHours      int32          `json:"full_refund_hours"`
+	NoRefundHours        int32          `json:"no_refund_hours"`
+	PartialRefundPercent int32          `json:"partial_refund_percent"`
+}
+
+type ExchangeRates struct {
+	Base      string    `json:"base"`
+	Quote     string    `json:"quote"`
+	Rate      string    `json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type Favourites struct {
 	ID        uuid.UUID     `json:"id"`
 	EventID   uuid.NullUUID `json:"event_id"`
 	AddedBy   uuid.NullUUID `json:"added_by"`
-	CreatedAt sql.NullTime  `json:"created_at"`
+	CreatedAt time.Time     `json:"created_at"`
 }
 
-type Practitioners struct {
+type InvoiceLines struct {
 	ID          uuid.UUID      `json:"id"`
-	Name        string         `json:"name"`
+	InvoiceID   uuid.UUID      `json:"invoice_id"`
+	Position    int32          `json:"position"`
+	Kind        string         `json:"kind"`
 	Description string         `json:"description"`
-	ImageLink   sql.NullString `json:"image_link"`
-	IsAvailable sql.NullBool   `json:"is_available"`
-	CreatedBy   uuid.NullUUID  `json:"created_by"`
-	OpensAt     sql.NullTime   `json:"opens_at"`
-	ClosesAt    sql.NullTime   `json:"closes_at"`
-	WorkingDays sql.NullString `json:"working_days"`
-	CreatedAt   sql.NullTime   `json:"created_at"`
+	Quantity    int32          `json:"quantity"`
+	UnitAmount  int32          `json:"unit_amount"`
+	Amount      int32          `json:"amount"`
+	TaxRate     sql.NullString `json:"tax_rate"`
+}
+
+type InvoiceSequences struct {
+	ProfileID  uuid.UUID `json:"profile_id"`
+	LastNumber int64     `json:"last_number"`
+}
+
+type Invoices struct {
+	ID            uuid.UUID      `json:"id"`
+	PurchaseID    uuid.UUID      `json:"purchase_id"`
+	SellerID      uuid.UUID      `json:"seller_id"`
+	BuyerID       uuid.NullUUID  `json:"buyer_id"`
+	Sequence      int64          `json:"sequence"`
+	Number        string         `json:"number"`
+	SellerName    string         `json:"seller_name"`
+	SellerAddress sql.NullString `json:"seller_address"`
+	SellerCountry sql.NullString `json:"seller_country"`
+	BuyerName     string         `json:"buyer_name"`
+	BuyerAddress  sql.NullString `json:"buyer_address"`
+	BuyerCountry  sql.NullString `json:"buyer_country"`
+	Currency      string         `json:"currency"`
+	Subtotal      int32          `json:"subtotal"`
+	TaxAmount     int32          `json:"tax_amount"`
+	Total         int32          `json:"total"`
+	IssuedAt      time.Time      `json:"issued_at"`
+}
+
+type Notifications struct {
+	ID              uuid.UUID     `json:"id"`
+	ProfileID       uuid.UUID     `json:"profile_id"`
+	Kind            string        `json:"kind"`
+	Message         string        `json:"message"`
+	WaitlistEntryID uuid.NullUUID `json:"waitlist_entry_id"`
+	ReadAt          sql.NullTime  `json:"read_at"`
+	CreatedAt       time.Time     `json:"created_at"`
+}
+
+type PaymentEvents struct {
+	Provider   string    `json:"provider"`
+	EventID    string    `json:"event_id"`
+	Type       string    `json:"type"`
+	CheckoutID string    `json:"checkout_id"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+type Practitioners struct {
+	ID                   uuid.UUID      `json:"id"`
+	Name                 string         `json:"name"`
+	Description          string         `json:"description"`
+	ImageLink            sql.NullString `json:"image_link"`
+	IsAvailable          sql.NullBool   `json:"is_available"`
+	CreatedBy            uuid.NullUUID  `json:"created_by"`
+	OpensAt              sql.NullTime   `json:"opens_at"`
+	ClosesAt             sql.NullTime   `json:"closes_at"`
+	WorkingDays          sql.NullString `json:"working_days"`
+	CreatedAt            time.Time      `json:"created_at"`
+	SearchVector         string         `json:"-"`
+	FullRefundHours      int32          `json:"full_refund_hours"`
+	NoRefundHours        int32          `json:"no_refund_hours"`
+	PartialRefundPercent int32          `json:"partial_refund_percent"`
 }
 
 type Profiles struct {
@@ -75,21 +166,74 @@ type Profiles struct {
 	Field        sql.NullString `json:"field"`
 	BusinessName sql.NullString `json:"business_name"`
 	Roles        string         `json:"roles"`
-	CreatedAt    sql.NullTime   `json:"created_at"`
+	CreatedAt    time.Time      `json:"created_at"`
 }
 
 type ProfilesUsers struct {
 	ProfilesID uuid.UUID `json:"profiles_id"`
 	UsersID    uuid.UUID `json:"users_id"`
+	MemberRole string    `json:"member_role"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type PurchaseTaxes struct {
+	ID         uuid.UUID `json:"id"`
+	PurchaseID uuid.UUID `json:"purchase_id"`
+	Position   int32     `json:"position"`
+	Name       string    `json:"name"`
+	Rate       string    `json:"rate"`
+	Inclusive  bool      `json:"inclusive"`
+	Amount     int32     `json:"amount"`
 }
 
 type Purchases struct {
-	ID          uuid.UUID     `json:"id"`
-	EventID     uuid.NullUUID `json:"event_id"`
-	VenueID     uuid.NullUUID `json:"venue_id"`
-	ServiceID   uuid.NullUUID `json:"service_id"`
-	PurchasedBy uuid.NullUUID `json:"purchased_by"`
-	CreatedAt   sql.NullTime  `json:"created_at"`
+	ID                   uuid.UUID      `json:"id"`
+	EventID              uuid.NullUUID  `json:"event_id"`
+	VenueID              uuid.NullUUID  `json:"venue_id"`
+	ServiceID            uuid.NullUUID  `json:"service_id"`
+	PurchasedBy          uuid.NullUUID  `json:"purchased_by"`
+	CreatedAt            time.Time      `json:"created_at"`
+	Amount               sql.NullInt32  `json:"amount"`
+	BookedVenueID        uuid.NullUUID  `json:"booked_venue_id"`
+	BookedPractitionerID uuid.NullUUID  `json:"booked_practitioner_id"`
+	Currency             string         `json:"currency"`
+	Status               string         `json:"status"`
+	Provider             sql.NullString `json:"provider"`
+	ProviderCheckoutID   sql.NullString `json:"provider_checkout_id"`
+	ProviderPaymentID    sql.NullString `json:"provider_payment_id"`
+	PaidAt               sql.NullTime   `json:"paid_at"`
+	CancelledAt          sql.NullTime   `json:"cancelled_at"`
+	TaxAmount            int32          `json:"tax_amount"`
+	PendingUntil         sql.NullTime   `json:"pending_until"`
+}
+
+type Sessions struct {
+	ID           uuid.UUID `json:"id"`
+	UserID       uuid.UUID `json:"user_id"`
+	RefreshToken string    `json:"refresh_token"`
+	UserAgent    string    `json:"user_agent"`
+	ClientIp     string    `json:"client_ip"`
+	IsBlocked    bool      `json:"is_blocked"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type TicketTiers struct {
+	ID        uuid.UUID `json:"id"`
+	EventID   uuid.UUID `json:"event_id"`
+	Name      string    `json:"name"`
+	Price     int32     `json:"price"`
+	Quantity  int32     `json:"quantity"`
+	CreatedAt time.Time `json:"created_at"`
+	Currency  string    `json:"currency"`
+}
+
+type Tickets struct {
+	ID         uuid.UUID    `json:"id"`
+	PurchaseID uuid.UUID    `json:"purchase_id"`
+	TierID     uuid.UUID    `json:"tier_id"`
+	Code       string       `json:"code"`
+	CreatedAt  sql.NullTime `json:"created_at"`
 }
 
 type Users struct {
@@ -98,30 +242,49 @@ type Users struct {
 	Password  sql.NullString `json:"password"`
 	Firstname sql.NullString `json:"firstname"`
 	Lastname  sql.NullString `json:"lastname"`
-	CreatedAt sql.NullTime   `json:"created_at"`
+	CreatedAt time.Time      `json:"created_at"`
 }
 
 type Venues struct {
-	ID              uuid.UUID      `json:"id"`
-	ImageLinks      []string       `json:"image_links"`
-	Name            string         `json:"name"`
-	Type            sql.NullString `json:"type"`
-	Description     sql.NullString `json:"description"`
-	Location        string         `json:"location"`
-	Dimension       sql.NullString `json:"dimension"`
-	Capacity        sql.NullInt32  `json:"capacity"`
-	Facilities      []string       `json:"facilities"`
-	HasAccomodation sql.NullBool   `json:"has_accomodation"`
-	RoomType        sql.NullString `json:"room_type"`
-	NoOfRooms       sql.NullInt32  `json:"no_of_rooms"`
-	Sleeps          sql.NullString `json:"sleeps"`
-	BedType         sql.NullString `json:"bed_type"`
-	Rent            sql.NullInt32  `json:"rent"`
-	OwnedBy         uuid.NullUUID  `json:"owned_by"`
-	IsAvailable     sql.NullBool   `json:"is_available"`
-	OpensAt         sql.NullTime   `json:"opens_at"`
-	ClosesAt        sql.NullTime   `json:"closes_at"`
-	RentalDays      sql.NullString `json:"rental_days"`
-	BookingPrice    sql.NullInt32  `json:"booking_price"`
-	CreatedAt       sql.NullTime   `json:"created_at"`
+	ID                   uuid.UUID       `json:"id"`
+	ImageLinks           []string        `json:"image_links"`
+	Name                 string          `json:"name"`
+	Type                 sql.NullString  `json:"type"`
+	Description          sql.NullString  `json:"description"`
+	Location             string          `json:"location"`
+	Dimension            sql.NullString  `json:"dimension"`
+	Capacity             sql.NullInt32   `json:"capacity"`
+	Facilities           []string        `json:"facilities"`
+	HasAccomodation      sql.NullBool    `json:"has_accomodation"`
+	RoomType             sql.NullString  `json:"room_type"`
+	NoOfRooms            sql.NullInt32   `json:"no_of_rooms"`
+	Sleeps               sql.NullString  `json:"sleeps"`
+	BedType              sql.NullString  `json:"bed_type"`
+	Rent                 sql.NullInt32   `json:"rent"`
+	OwnedBy              uuid.NullUUID   `json:"owned_by"`
+	IsAvailable          sql.NullBool    `json:"is_available"`
+	OpensAt              sql.NullTime    `json:"opens_at"`
+	ClosesAt             sql.NullTime    `json:"closes_at"`
+	RentalDays           sql.NullString  `json:"rental_days"`
+	BookingPrice         sql.NullInt32   `json:"booking_price"`
+	CreatedAt            time.Time       `json:"created_at"`
+	SearchVector         string          `json:"-"`
+	Latitude             sql.NullFloat64 `json:"latitude"`
+	Longitude            sql.NullFloat64 `json:"longitude"`
+	FullRefundHours      int32           `json:"full_refund_hours"`
+	NoRefundHours        int32           `json:"no_refund_hours"`
+	PartialRefundPercent int32           `json:"partial_refund_percent"`
+	Currency             string          `json:"currency"`
+}
+
+type WaitlistEntries struct {
+	ID         uuid.UUID     `json:"id"`
+	EventID    uuid.NullUUID `json:"event_id"`
+	VenueID    uuid.NullUUID `json:"venue_id"`
+	BookedFor  sql.NullTime  `json:"booked_for"`
+	ProfileID  uuid.UUID     `json:"profile_id"`
+	Status     string        `json:"status"`
+	PromotedAt sql.NullTime  `json:"promoted_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+	HoldUntil  sql.NullTime  `json:"hold_until"`
 }
@@ -0,0 +1,104 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: notifications.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const createNotification = `-- name: CreateNotification :one
+INSERT INTO "notifications" (
+  profile_id,
+  kind,
+  message,
+  waitlist_entry_id
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, profile_id, kind, message, waitlist_entry_id, read_at, created_at
+`
+
+type CreateNotificationParams struct {
+	ProfileID       uuid.UUID     `json:"profile_id"`
+	Kind            string        `json:"kind"`
+	Message         string        `json:"message"`
+	WaitlistEntryID uuid.NullUUID `json:"waitlist_entry_id"`
+}
+
+func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notifications, error) {
+	row := q.db.QueryRowContext(ctx, createNotification,
+		arg.ProfileID,
+		arg.Kind,
+		arg.Message,
+		arg.WaitlistEntryID,
+	)
+	var i Notifications
+	err := row.Scan(
+		&i.ID,
+		&i.ProfileID,
+		&i.Kind,
+		&i.Message,
+		&i.WaitlistEntryID,
+		&i.ReadAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listNotificationsByProfile = `-- name: ListNotificationsByProfile :many
+SELECT id, profile_id, kind, message, waitlist_entry_id, read_at, created_at FROM notifications
+WHERE profile_id = $1
+  AND ($2::uuid IS NULL
+    OR (created_at, id) < ($3::timestamp, $2::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT $4
+`
+
+type ListNotificationsByProfileParams struct {
+	ProfileID       uuid.UUID     `json:"profile_id"`
+	CursorID        uuid.NullUUID `json:"cursor_id"`
+	CursorCreatedAt sql.NullTime  `json:"cursor_created_at"`
+	PageLimit       int32         `json:"page_limit"`
+}
+
+func (q *Queries) ListNotificationsByProfile(ctx context.Context, arg ListNotificationsByProfileParams) ([]Notifications, error) {
+	rows, err := q.db.QueryContext(ctx, listNotificationsByProfile,
+		arg.ProfileID,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notifications
+	for rows.Next() {
+		var i Notifications
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProfileID,
+			&i.Kind,
+			&i.Message,
+			&i.WaitlistEntryID,
+			&i.ReadAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -25,7 +25,7 @@ INSERT INTO "practitioners" (
 ) VALUES (
   $1, $2, $3, $4, $5, $6, $7, $8
 )
-RETURNING id, name, description, image_link, is_available, created_by, opens_at, closes_at, working_days, created_at
+RETURNING id, name, description, image_link, is_available, created_by, opens_at, closes_at, working_days, created_at, search_vector, full_refund_hours, no_refund_hours, partial_refund_percent
 `
 
 type CreatePractitionerParams struct {
@@ -62,6 +62,10 @@ func (q *Queries) CreatePractitioner(ctx context.Context, arg CreatePractitioner
 		&i.ClosesAt,
 		&i.WorkingDays,
 		&i.CreatedAt,
+		&i.SearchVector,
+		&i.FullRefundHours,
+		&i.NoRefundHours,
+		&i.PartialRefundPercent,
 	)
 	return i, err
 }
@@ -77,7 +81,7 @@ func (q *Queries) DeletePractitioner(ctx context.Context, id uuid.UUID) error {
 }
 
 const getPractitioner = `-- name: GetPractitioner :one
-SELECT id, name, description, image_link, is_available, created_by, opens_at, closes_at, working_days, created_at FROM practitioners
+SELECT id, name, description, image_link, is_available, created_by, opens_at, closes_at, working_days, created_at, search_vector, full_refund_hours, no_refund_hours, partial_refund_percent FROM practitioners
 WHERE id = $1 LIMIT 1
 `
 
@@ -95,17 +99,75 @@ func (q *Queries) GetPractitioner(ctx context.Context, id uuid.UUID) (Practition
 		&i.ClosesAt,
 		&i.WorkingDays,
 		&i.CreatedAt,
+		&i.SearchVector,
+		&i.FullRefundHours,
+		&i.NoRefundHours,
+		&i.PartialRefundPercent,
+	)
+	return i, err
+}
+
+const getPractitionerForUpdate = `-- name: GetPractitionerForUpdate :one
+SELECT id, name, description, image_link, is_available, created_by, opens_at, closes_at, working_days, created_at, search_vector, full_refund_hours, no_refund_hours, partial_refund_percent FROM practitioners
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetPractitionerForUpdate(ctx context.Context, id uuid.UUID) (Practitioners, error) {
+	row := q.db.QueryRowContext(ctx, getPractitionerForUpdate, id)
+	var i Practitioners
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.ImageLink,
+		&i.IsAvailable,
+		&i.CreatedBy,
+		&i.OpensAt,
+		&i.ClosesAt,
+		&i.WorkingDays,
+		&i.CreatedAt,
+		&i.SearchVector,
+		&i.FullRefundHours,
+		&i.NoRefundHours,
+		&i.PartialRefundPercent,
 	)
 	return i, err
 }
 
 const listPractitioners = `-- name: ListPractitioners :many
-SELECT id, name, description, image_link, is_available, created_by, opens_at, closes_at, working_days, created_at FROM practitioners
-ORDER BY name
+SELECT id, name, description, image_link, is_available, created_by, opens_at, closes_at, working_days, created_at, search_vector, full_refund_hours, no_refund_hours, partial_refund_percent FROM practitioners
+WHERE ($1::boolean IS NULL OR is_available = $1)
+  AND ($2::uuid IS NULL
+    OR ($3::text = 'newest'
+      AND (created_at, id) < ($4::timestamp, $2::uuid))
+    OR ($3::text = 'name'
+      AND (name, id) > ($5::varchar, $2::uuid)))
+ORDER BY
+  CASE WHEN $3::text = 'newest' THEN created_at END DESC,
+  CASE WHEN $3::text = 'newest' THEN id END DESC,
+  name, id
+LIMIT $6
 `
 
-func (q *Queries) ListPractitioners(ctx context.Context) ([]Practitioners, error) {
-	rows, err := q.db.QueryContext(ctx, listPractitioners)
+type ListPractitionersParams struct {
+	IsAvailable     sql.NullBool   `json:"is_available"`
+	CursorID        uuid.NullUUID  `json:"cursor_id"`
+	Sort            string         `json:"sort"`
+	CursorCreatedAt sql.NullTime   `json:"cursor_created_at"`
+	CursorName      sql.NullString `json:"cursor_name"`
+	PageLimit       int32          `json:"page_limit"`
+}
+
+func (q *Queries) ListPractitioners(ctx context.Context, arg ListPractitionersParams) ([]Practitioners, error) {
+	rows, err := q.db.QueryContext(ctx, listPractitioners,
+		arg.IsAvailable,
+		arg.CursorID,
+		arg.Sort,
+		arg.CursorCreatedAt,
+		arg.CursorName,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -124,6 +186,10 @@ func (q *Queries) ListPractitioners(ctx context.Context) ([]Practitioners, error
 			&i.ClosesAt,
 			&i.WorkingDays,
 			&i.CreatedAt,
+			&i.SearchVector,
+			&i.FullRefundHours,
+			&i.NoRefundHours,
+			&i.PartialRefundPercent,
 		); err != nil {
 			return nil, err
 		}
@@ -149,7 +215,7 @@ UPDATE practitioners
   closes_at = $8,
   working_days = $9
 WHERE id = $1
-RETURNING id, name, description, image_link, is_available, created_by, opens_at, closes_at, working_days, created_at
+RETURNING id, name, description, image_link, is_available, created_by, opens_at, closes_at, working_days, created_at, search_vector, full_refund_hours, no_refund_hours, partial_refund_percent
 `
 
 type UpdatePractitionerParams struct {
@@ -188,6 +254,53 @@ func (q *Queries) UpdatePractitioner(ctx context.Context, arg UpdatePractitioner
 		&i.ClosesAt,
 		&i.WorkingDays,
 		&i.CreatedAt,
+		&i.SearchVector,
+		&i.FullRefundHours,
+		&i.NoRefundHours,
+		&i.PartialRefundPercent,
+	)
+	return i, err
+}
+
+const updatePractitionerCancellationPolicy = `-- name: UpdatePractitionerCancellationPolicy :one
+UPDATE practitioners
+SET full_refund_hours = $1,
+    no_refund_hours = $2,
+    partial_refund_percent = $3
+WHERE id = $4
+RETURNING id, name, description, image_link, is_available, created_by, opens_at, closes_at, working_days, created_at, search_vector, full_refund_hours, no_refund_hours, partial_refund_percent
+`
+
+type UpdatePractitionerCancellationPolicyParams struct {
+	FullRefundHours      int32     `json:"full_refund_hours"`
+	NoRefundHours        int32     `json:"no_refund_hours"`
+	PartialRefundPercent int32     `json:"partial_refund_percent"`
+	ID                   uuid.UUID `json:"id"`
+}
+
+func (q *Queries) UpdatePractitionerCancellationPolicy(ctx context.Context, arg UpdatePractitionerCancellationPolicyParams) (Practitioners, error) {
+	row := q.db.QueryRowContext(ctx, updatePractitionerCancellationPolicy,
+		arg.FullRefundHours,
+		arg.NoRefundHours,
+		arg.PartialRefundPercent,
+		arg.ID,
+	)
+	var i Practitioners
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.ImageLink,
+		&i.IsAvailable,
+		&i.CreatedBy,
+		&i.OpensAt,
+		&i.ClosesAt,
+		&i.WorkingDays,
+		&i.CreatedAt,
+		&i.SearchVector,
+		&i.FullRefundHours,
+		&i.NoRefundHours,
+		&i.PartialRefundPercent,
 	)
 	return i, err
 }
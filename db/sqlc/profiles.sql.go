@@ -8,12 +8,12 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 const createProfile = `-- name: CreateProfile :one
-
 INSERT INTO "profiles" (
   id,
   bio,
@@ -42,7 +42,6 @@ type CreateProfileParams struct {
 	Roles        string         `json:"roles"`
 }
 
-// Or any other relevant field for ordering
 func (q *Queries) CreateProfile(ctx context.Context, arg CreateProfileParams) (Profiles, error) {
 	row := q.db.QueryRowContext(ctx, createProfile,
 		arg.ID,
@@ -104,13 +103,262 @@ func (q *Queries) GetProfile(ctx context.Context, id uuid.UUID) (Profiles, error
 	return i, err
 }
 
+const getProfileMember = `-- name: GetProfileMember :one
+SELECT profiles_id, users_id, member_role, created_at FROM profiles_users
+WHERE profiles_id = $1 AND users_id = $2 LIMIT 1
+`
+
+type GetProfileMemberParams struct {
+	ProfilesID uuid.UUID `json:"profiles_id"`
+	UsersID    uuid.UUID `json:"users_id"`
+}
+
+func (q *Queries) GetProfileMember(ctx context.Context, arg GetProfileMemberParams) (ProfilesUsers, error) {
+	row := q.db.QueryRowContext(ctx, getProfileMember, arg.ProfilesID, arg.UsersID)
+	var i ProfilesUsers
+	err := row.Scan(
+		&i.ProfilesID,
+		&i.UsersID,
+		&i.MemberRole,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getProfileOwner = `-- name: GetProfileOwner :one
+SELECT users.id, users.email, users.password, users.firstname, users.lastname, users.created_at FROM users
+JOIN profiles_users ON profiles_users.users_id = users.id
+WHERE profiles_users.profiles_id = $1 AND profiles_users.member_role = 'owner'
+ORDER BY profiles_users.created_at
+LIMIT 1
+`
+
+func (q *Queries) GetProfileOwner(ctx context.Context, profilesID uuid.UUID) (Users, error) {
+	row := q.db.QueryRowContext(ctx, getProfileOwner, profilesID)
+	var i Users
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Password,
+		&i.Firstname,
+		&i.Lastname,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const linkUserToProfile = `-- name: LinkUserToProfile :one
+INSERT INTO "profiles_users" (
+  profiles_id,
+  users_id,
+  member_role
+) VALUES (
+  $1, $2, $3
+)
+RETURNING profiles_id, users_id, member_role, created_at
+`
+
+type LinkUserToProfileParams struct {
+	ProfilesID uuid.UUID `json:"profiles_id"`
+	UsersID    uuid.UUID `json:"users_id"`
+	MemberRole string    `json:"member_role"`
+}
+
+func (q *Queries) LinkUserToProfile(ctx context.Context, arg LinkUserToProfileParams) (ProfilesUsers, error) {
+	row := q.db.QueryRowContext(ctx, linkUserToProfile, arg.ProfilesID, arg.UsersID, arg.MemberRole)
+	var i ProfilesUsers
+	err := row.Scan(
+		&i.ProfilesID,
+		&i.UsersID,
+		&i.MemberRole,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listProfileMembers = `-- name: ListProfileMembers :many
+SELECT profiles_users.profiles_id, profiles_users.users_id, profiles_users.member_role, profiles_users.created_at, users.email, users.firstname, users.lastname FROM profiles_users
+JOIN users ON users.id = profiles_users.users_id
+WHERE profiles_users.profiles_id = $1
+  AND ($2::uuid IS NULL
+    OR (profiles_users.created_at, profiles_users.users_id) > ($3::timestamp, $2::uuid))
+ORDER BY profiles_users.created_at, profiles_users.users_id
+LIMIT $4
+`
+
+type ListProfileMembersParams struct {
+	ProfilesID      uuid.UUID     `json:"profiles_id"`
+	CursorID        uuid.NullUUID `json:"cursor_id"`
+	CursorCreatedAt sql.NullTime  `json:"cursor_created_at"`
+	PageLimit       int32         `json:"page_limit"`
+}
+
+type ListProfileMembersRow struct {
+	ProfilesID uuid.UUID      `json:"profiles_id"`
+	UsersID    uuid.UUID      `json:"users_id"`
+	MemberRole string         `json:"member_role"`
+	CreatedAt  time.Time      `json:"created_at"`
+	Email      string         `json:"email"`
+	Firstname  sql.NullString `json:"firstname"`
+	Lastname   sql.NullString `json:"lastname"`
+}
+
+func (q *Queries) ListProfileMembers(ctx context.Context, arg ListProfileMembersParams) ([]ListProfileMembersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listProfileMembers,
+		arg.ProfilesID,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListProfileMembersRow
+	for rows.Next() {
+		var i ListProfileMembersRow
+		if err := rows.Scan(
+			&i.ProfilesID,
+			&i.UsersID,
+			&i.MemberRole,
+			&i.CreatedAt,
+			&i.Email,
+			&i.Firstname,
+			&i.Lastname,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProfileMembershipsByUser = `-- name: ListProfileMembershipsByUser :many
+SELECT profiles.id, profiles.bio, profiles.phone_no, profiles.country, profiles.address, profiles.experience, profiles.field, profiles.business_name, profiles.roles, profiles.created_at, profiles_users.member_role FROM profiles
+JOIN profiles_users ON profiles_users.profiles_id = profiles.id
+WHERE profiles_users.users_id = $1
+  AND ($2::uuid IS NULL
+    OR (profiles.created_at, profiles.id) > ($3::timestamp, $2::uuid))
+ORDER BY profiles.created_at, profiles.id
+LIMIT $4
+`
+
+type ListProfileMembershipsByUserParams struct {
+	UsersID         uuid.UUID     `json:"users_id"`
+	CursorID        uuid.NullUUID `json:"cursor_id"`
+	CursorCreatedAt sql.NullTime  `json:"cursor_created_at"`
+	PageLimit       int32         `json:"page_limit"`
+}
+
+type ListProfileMembershipsByUserRow struct {
+	Profiles   Profiles `json:"profiles"`
+	MemberRole string   `json:"member_role"`
+}
+
+func (q *Queries) ListProfileMembershipsByUser(ctx context.Context, arg ListProfileMembershipsByUserParams) ([]ListProfileMembershipsByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, listProfileMembershipsByUser,
+		arg.UsersID,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListProfileMembershipsByUserRow
+	for rows.Next() {
+		var i ListProfileMembershipsByUserRow
+		if err := rows.Scan(
+			&i.Profiles.ID,
+			&i.Profiles.Bio,
+			&i.Profiles.PhoneNo,
+			&i.Profiles.Country,
+			&i.Profiles.Address,
+			&i.Profiles.Experience,
+			&i.Profiles.Field,
+			&i.Profiles.BusinessName,
+			&i.Profiles.Roles,
+			&i.Profiles.CreatedAt,
+			&i.MemberRole,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProfileOwnersForUpdate = `-- name: ListProfileOwnersForUpdate :many
+SELECT profiles_id, users_id, member_role, created_at FROM profiles_users
+WHERE profiles_id = $1 AND member_role = 'owner'
+ORDER BY users_id
+FOR UPDATE
+`
+
+func (q *Queries) ListProfileOwnersForUpdate(ctx context.Context, profilesID uuid.UUID) ([]ProfilesUsers, error) {
+	rows, err := q.db.QueryContext(ctx, listProfileOwnersForUpdate, profilesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProfilesUsers
+	for rows.Next() {
+		var i ProfilesUsers
+		if err := rows.Scan(
+			&i.ProfilesID,
+			&i.UsersID,
+			&i.MemberRole,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listProfiles = `-- name: ListProfiles :many
 SELECT id, bio, phone_no, country, address, experience, field, business_name, roles, created_at FROM profiles
-ORDER BY business_name
+WHERE ($1::varchar IS NULL OR country = $1)
+  AND ($2::uuid IS NULL
+    OR (created_at, id) < ($3::timestamp, $2::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT $4
 `
 
-func (q *Queries) ListProfiles(ctx context.Context) ([]Profiles, error) {
-	rows, err := q.db.QueryContext(ctx, listProfiles)
+type ListProfilesParams struct {
+	Country         sql.NullString `json:"country"`
+	CursorID        uuid.NullUUID  `json:"cursor_id"`
+	CursorCreatedAt sql.NullTime   `json:"cursor_created_at"`
+	PageLimit       int32          `json:"page_limit"`
+}
+
+func (q *Queries) ListProfiles(ctx context.Context, arg ListProfilesParams) ([]Profiles, error) {
+	rows, err := q.db.QueryContext(ctx, listProfiles,
+		arg.Country,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -143,6 +391,81 @@ func (q *Queries) ListProfiles(ctx context.Context) ([]Profiles, error) {
 	return items, nil
 }
 
+const listProfilesByUser = `-- name: ListProfilesByUser :many
+SELECT profiles.id, profiles.bio, profiles.phone_no, profiles.country, profiles.address, profiles.experience, profiles.field, profiles.business_name, profiles.roles, profiles.created_at, profiles_users.member_role FROM profiles
+JOIN profiles_users ON profiles_users.profiles_id = profiles.id
+WHERE profiles_users.users_id = $1
+ORDER BY profiles.created_at
+`
+
+type ListProfilesByUserRow struct {
+	Profiles   Profiles `json:"profiles"`
+	MemberRole string   `json:"member_role"`
+}
+
+func (q *Queries) ListProfilesByUser(ctx context.Context, usersID uuid.UUID) ([]ListProfilesByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, listProfilesByUser, usersID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListProfilesByUserRow
+	for rows.Next() {
+		var i ListProfilesByUserRow
+		if err := rows.Scan(
+			&i.Profiles.ID,
+			&i.Profiles.Bio,
+			&i.Profiles.PhoneNo,
+			&i.Profiles.Country,
+			&i.Profiles.Address,
+			&i.Profiles.Experience,
+			&i.Profiles.Field,
+			&i.Profiles.BusinessName,
+			&i.Profiles.Roles,
+			&i.Profiles.CreatedAt,
+			&i.MemberRole,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const unlinkAllUsersFromProfile = `-- name: UnlinkAllUsersFromProfile :exec
+DELETE FROM profiles_users
+WHERE profiles_id = $1
+`
+
+func (q *Queries) UnlinkAllUsersFromProfile(ctx context.Context, profilesID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, unlinkAllUsersFromProfile, profilesID)
+	return err
+}
+
+const unlinkUserFromProfile = `-- name: UnlinkUserFromProfile :execrows
+DELETE FROM profiles_users
+WHERE profiles_id = $1 AND users_id = $2
+`
+
+type UnlinkUserFromProfileParams struct {
+	ProfilesID uuid.UUID `json:"profiles_id"`
+	UsersID    uuid.UUID `json:"users_id"`
+}
+
+func (q *Queries) UnlinkUserFromProfile(ctx context.Context, arg UnlinkUserFromProfileParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unlinkUserFromProfile, arg.ProfilesID, arg.UsersID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateProfile = `-- name: UpdateProfile :one
 UPDATE profiles
   set bio = $2,
@@ -196,3 +519,28 @@ func (q *Queries) UpdateProfile(ctx context.Context, arg UpdateProfileParams) (P
 	)
 	return i, err
 }
+
+const updateProfileMemberRole = `-- name: UpdateProfileMemberRole :one
+UPDATE profiles_users
+  set member_role = $3
+WHERE profiles_id = $1 AND users_id = $2
+RETURNING profiles_id, users_id, member_role, created_at
+`
+
+type UpdateProfileMemberRoleParams struct {
+	ProfilesID uuid.UUID `json:"profiles_id"`
+	UsersID    uuid.UUID `json:"users_id"`
+	MemberRole string    `json:"member_role"`
+}
+
+func (q *Queries) UpdateProfileMemberRole(ctx context.Context, arg UpdateProfileMemberRoleParams) (ProfilesUsers, error) {
+	row := q.db.QueryRowContext(ctx, updateProfileMemberRole, arg.ProfilesID, arg.UsersID, arg.MemberRole)
+	var i ProfilesUsers
+	err := row.Scan(
+		&i.ProfilesID,
+		&i.UsersID,
+		&i.MemberRole,
+		&i.CreatedAt,
+	)
+	return i, err
+}
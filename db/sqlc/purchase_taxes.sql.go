@@ -0,0 +1,94 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: purchase_taxes.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const createPurchaseTax = `-- name: CreatePurchaseTax :one
+INSERT INTO "purchase_taxes" (
+  purchase_id,
+  position,
+  name,
+  rate,
+  inclusive,
+  amount
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING id, purchase_id, position, name, rate, inclusive, amount
+`
+
+type CreatePurchaseTaxParams struct {
+	PurchaseID uuid.UUID `json:"purchase_id"`
+	Position   int32     `json:"position"`
+	Name       string    `json:"name"`
+	Rate       string    `json:"rate"`
+	Inclusive  bool      `json:"inclusive"`
+	Amount     int32     `json:"amount"`
+}
+
+func (q *Queries) CreatePurchaseTax(ctx context.Context, arg CreatePurchaseTaxParams) (PurchaseTaxes, error) {
+	row := q.db.QueryRowContext(ctx, createPurchaseTax,
+		arg.PurchaseID,
+		arg.Position,
+		arg.Name,
+		arg.Rate,
+		arg.Inclusive,
+		arg.Amount,
+	)
+	var i PurchaseTaxes
+	err := row.Scan(
+		&i.ID,
+		&i.PurchaseID,
+		&i.Position,
+		&i.Name,
+		&i.Rate,
+		&i.Inclusive,
+		&i.Amount,
+	)
+	return i, err
+}
+
+const listPurchaseTaxes = `-- name: ListPurchaseTaxes :many
+SELECT id, purchase_id, position, name, rate, inclusive, amount FROM "purchase_taxes"
+WHERE purchase_id = $1
+ORDER BY position
+`
+
+func (q *Queries) ListPurchaseTaxes(ctx context.Context, purchaseID uuid.UUID) ([]PurchaseTaxes, error) {
+	rows, err := q.db.QueryContext(ctx, listPurchaseTaxes, purchaseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PurchaseTaxes
+	for rows.Next() {
+		var i PurchaseTaxes
+		if err := rows.Scan(
+			&i.ID,
+			&i.PurchaseID,
+			&i.Position,
+			&i.Name,
+			&i.Rate,
+			&i.Inclusive,
+			&i.Amount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
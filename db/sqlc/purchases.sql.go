@@ -7,27 +7,108 @@ package db
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
+const cancelPurchase = `-- name: CancelPurchase :one
+UPDATE purchases
+SET status = 'cancelled',
+    cancelled_at = now()
+WHERE id = $1 AND status IN ('pending', 'paid')
+RETURNING id, event_id, venue_id, service_id, purchased_by, created_at, amount, booked_venue_id, booked_practitioner_id, currency, status, provider, provider_checkout_id, provider_payment_id, paid_at, cancelled_at, tax_amount, pending_until
+`
+
+func (q *Queries) CancelPurchase(ctx context.Context, id uuid.UUID) (Purchases, error) {
+	row := q.db.QueryRowContext(ctx, cancelPurchase, id)
+	var i Purchases
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.VenueID,
+		&i.ServiceID,
+		&i.PurchasedBy,
+		&i.CreatedAt,
+		&i.Amount,
+		&i.BookedVenueID,
+		&i.BookedPractitionerID,
+		&i.Currency,
+		&i.Status,
+		&i.Provider,
+		&i.ProviderCheckoutID,
+		&i.ProviderPaymentID,
+		&i.PaidAt,
+		&i.CancelledAt,
+		&i.TaxAmount,
+		&i.PendingUntil,
+	)
+	return i, err
+}
+
+const countPaidCheckoutPurchases = `-- name: CountPaidCheckoutPurchases :one
+SELECT count(*) FROM purchases
+WHERE provider = $1 AND provider_checkout_id = $2 AND status = 'paid'
+`
+
+type CountPaidCheckoutPurchasesParams struct {
+	Provider           sql.NullString `json:"provider"`
+	ProviderCheckoutID sql.NullString `json:"provider_checkout_id"`
+}
+
+func (q *Queries) CountPaidCheckoutPurchases(ctx context.Context, arg CountPaidCheckoutPurchasesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPaidCheckoutPurchases, arg.Provider, arg.ProviderCheckoutID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPurchasesByEvent = `-- name: CountPurchasesByEvent :one
+SELECT count(*) FROM purchases
+WHERE event_id = $1 AND status IN ('pending', 'paid')
+`
+
+func (q *Queries) CountPurchasesByEvent(ctx context.Context, eventID uuid.NullUUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPurchasesByEvent, eventID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createPurchase = `-- name: CreatePurchase :one
 INSERT INTO "purchases" (
   event_id,
   venue_id,
   service_id,
-  purchased_by
+  purchased_by,
+  amount,
+  booked_venue_id,
+  booked_practitioner_id,
+  currency,
+  status,
+  paid_at,
+  tax_amount,
+  pending_until
 ) VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3, $4, $5, $6, $7, $8, $9,
+  CASE WHEN $9::varchar = 'paid' THEN now() END,
+  $10, $11
 )
-RETURNING id, event_id, venue_id, service_id, purchased_by, created_at
+RETURNING id, event_id, venue_id, service_id, purchased_by, created_at, amount, booked_venue_id, booked_practitioner_id, currency, status, provider, provider_checkout_id, provider_payment_id, paid_at, cancelled_at, tax_amount, pending_until
 `
 
 type CreatePurchaseParams struct {
-	EventID     uuid.NullUUID `json:"event_id"`
-	VenueID     uuid.NullUUID `json:"venue_id"`
-	ServiceID   uuid.NullUUID `json:"service_id"`
-	PurchasedBy uuid.NullUUID `json:"purchased_by"`
+	EventID              uuid.NullUUID `json:"event_id"`
+	VenueID              uuid.NullUUID `json:"venue_id"`
+	ServiceID            uuid.NullUUID `json:"service_id"`
+	PurchasedBy          uuid.NullUUID `json:"purchased_by"`
+	Amount               sql.NullInt32 `json:"amount"`
+	BookedVenueID        uuid.NullUUID `json:"booked_venue_id"`
+	BookedPractitionerID uuid.NullUUID `json:"booked_practitioner_id"`
+	Currency             string        `json:"currency"`
+	Status               string        `json:"status"`
+	TaxAmount            int32         `json:"tax_amount"`
+	PendingUntil         sql.NullTime  `json:"pending_until"`
 }
 
 func (q *Queries) CreatePurchase(ctx context.Context, arg CreatePurchaseParams) (Purchases, error) {
@@ -36,6 +117,13 @@ func (q *Queries) CreatePurchase(ctx context.Context, arg CreatePurchaseParams)
 		arg.VenueID,
 		arg.ServiceID,
 		arg.PurchasedBy,
+		arg.Amount,
+		arg.BookedVenueID,
+		arg.BookedPractitionerID,
+		arg.Currency,
+		arg.Status,
+		arg.TaxAmount,
+		arg.PendingUntil,
 	)
 	var i Purchases
 	err := row.Scan(
@@ -45,22 +133,96 @@ func (q *Queries) CreatePurchase(ctx context.Context, arg CreatePurchaseParams)
 		&i.ServiceID,
 		&i.PurchasedBy,
 		&i.CreatedAt,
+		&i.Amount,
+		&i.BookedVenueID,
+		&i.BookedPractitionerID,
+		&i.Currency,
+		&i.Status,
+		&i.Provider,
+		&i.ProviderCheckoutID,
+		&i.ProviderPaymentID,
+		&i.PaidAt,
+		&i.CancelledAt,
+		&i.TaxAmount,
+		&i.PendingUntil,
 	)
 	return i, err
 }
 
-const deletePurchase = `-- name: DeletePurchase :exec
-DELETE FROM purchases
-WHERE id = $1
+const failExpiredPurchases = `-- name: FailExpiredPurchases :many
+UPDATE purchases
+SET status = 'failed'
+WHERE status = 'pending'
+  AND pending_until <= now()
+  AND ($1::uuid IS NULL OR event_id = $1)
+  AND ($2::uuid IS NULL OR venue_id = $2)
+  AND ($3::uuid IS NULL OR service_id = $3)
+RETURNING id, event_id, venue_id, service_id, purchased_by, created_at, amount, booked_venue_id, booked_practitioner_id, currency, status, provider, provider_checkout_id, provider_payment_id, paid_at, cancelled_at, tax_amount, pending_until
 `
 
-func (q *Queries) DeletePurchase(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deletePurchase, id)
+type FailExpiredPurchasesParams struct {
+	EventID   uuid.NullUUID `json:"event_id"`
+	VenueID   uuid.NullUUID `json:"venue_id"`
+	ServiceID uuid.NullUUID `json:"service_id"`
+}
+
+// Fails the pending purchases whose checkout has expired, optionally only
+// those of one event, venue or service.
+func (q *Queries) FailExpiredPurchases(ctx context.Context, arg FailExpiredPurchasesParams) ([]Purchases, error) {
+	rows, err := q.db.QueryContext(ctx, failExpiredPurchases, arg.EventID, arg.VenueID, arg.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Purchases
+	for rows.Next() {
+		var i Purchases
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.VenueID,
+			&i.ServiceID,
+			&i.PurchasedBy,
+			&i.CreatedAt,
+			&i.Amount,
+			&i.BookedVenueID,
+			&i.BookedPractitionerID,
+			&i.Currency,
+			&i.Status,
+			&i.Provider,
+			&i.ProviderCheckoutID,
+			&i.ProviderPaymentID,
+			&i.PaidAt,
+			&i.CancelledAt,
+			&i.TaxAmount,
+			&i.PendingUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const failPurchase = `-- name: FailPurchase :exec
+UPDATE purchases
+SET status = 'failed'
+WHERE id = $1 AND status = 'pending'
+`
+
+func (q *Queries) FailPurchase(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, failPurchase, id)
 	return err
 }
 
 const getPurchase = `-- name: GetPurchase :one
-SELECT id, event_id, venue_id, service_id, purchased_by, created_at FROM purchases
+SELECT id, event_id, venue_id, service_id, purchased_by, created_at, amount, booked_venue_id, booked_practitioner_id, currency, status, provider, provider_checkout_id, provider_payment_id, paid_at, cancelled_at, tax_amount, pending_until FROM purchases
 WHERE id = $1 LIMIT 1
 `
 
@@ -74,18 +236,299 @@ func (q *Queries) GetPurchase(ctx context.Context, id uuid.UUID) (Purchases, err
 		&i.ServiceID,
 		&i.PurchasedBy,
 		&i.CreatedAt,
+		&i.Amount,
+		&i.BookedVenueID,
+		&i.BookedPractitionerID,
+		&i.Currency,
+		&i.Status,
+		&i.Provider,
+		&i.ProviderCheckoutID,
+		&i.ProviderPaymentID,
+		&i.PaidAt,
+		&i.CancelledAt,
+		&i.TaxAmount,
+		&i.PendingUntil,
 	)
 	return i, err
 }
 
+const getPurchaseByBookedPractitioner = `-- name: GetPurchaseByBookedPractitioner :one
+SELECT id, event_id, venue_id, service_id, purchased_by, created_at, amount, booked_venue_id, booked_practitioner_id, currency, status, provider, provider_checkout_id, provider_payment_id, paid_at, cancelled_at, tax_amount, pending_until FROM purchases
+WHERE booked_practitioner_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetPurchaseByBookedPractitioner(ctx context.Context, bookedPractitionerID uuid.NullUUID) (Purchases, error) {
+	row := q.db.QueryRowContext(ctx, getPurchaseByBookedPractitioner, bookedPractitionerID)
+	var i Purchases
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.VenueID,
+		&i.ServiceID,
+		&i.PurchasedBy,
+		&i.CreatedAt,
+		&i.Amount,
+		&i.BookedVenueID,
+		&i.BookedPractitionerID,
+		&i.Currency,
+		&i.Status,
+		&i.Provider,
+		&i.ProviderCheckoutID,
+		&i.ProviderPaymentID,
+		&i.PaidAt,
+		&i.CancelledAt,
+		&i.TaxAmount,
+		&i.PendingUntil,
+	)
+	return i, err
+}
+
+const getPurchaseByBookedVenue = `-- name: GetPurchaseByBookedVenue :one
+SELECT id, event_id, venue_id, service_id, purchased_by, created_at, amount, booked_venue_id, booked_practitioner_id, currency, status, provider, provider_checkout_id, provider_payment_id, paid_at, cancelled_at, tax_amount, pending_until FROM purchases
+WHERE booked_venue_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetPurchaseByBookedVenue(ctx context.Context, bookedVenueID uuid.NullUUID) (Purchases, error) {
+	row := q.db.QueryRowContext(ctx, getPurchaseByBookedVenue, bookedVenueID)
+	var i Purchases
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.VenueID,
+		&i.ServiceID,
+		&i.PurchasedBy,
+		&i.CreatedAt,
+		&i.Amount,
+		&i.BookedVenueID,
+		&i.BookedPractitionerID,
+		&i.Currency,
+		&i.Status,
+		&i.Provider,
+		&i.ProviderCheckoutID,
+		&i.ProviderPaymentID,
+		&i.PaidAt,
+		&i.CancelledAt,
+		&i.TaxAmount,
+		&i.PendingUntil,
+	)
+	return i, err
+}
+
+const getPurchaseForUpdate = `-- name: GetPurchaseForUpdate :one
+SELECT id, event_id, venue_id, service_id, purchased_by, created_at, amount, booked_venue_id, booked_practitioner_id, currency, status, provider, provider_checkout_id, provider_payment_id, paid_at, cancelled_at, tax_amount, pending_until FROM purchases
+WHERE id = $1 LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetPurchaseForUpdate(ctx context.Context, id uuid.UUID) (Purchases, error) {
+	row := q.db.QueryRowContext(ctx, getPurchaseForUpdate, id)
+	var i Purchases
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.VenueID,
+		&i.ServiceID,
+		&i.PurchasedBy,
+		&i.CreatedAt,
+		&i.Amount,
+		&i.BookedVenueID,
+		&i.BookedPractitionerID,
+		&i.Currency,
+		&i.Status,
+		&i.Provider,
+		&i.ProviderCheckoutID,
+		&i.ProviderPaymentID,
+		&i.PaidAt,
+		&i.CancelledAt,
+		&i.TaxAmount,
+		&i.PendingUntil,
+	)
+	return i, err
+}
+
+const listCheckoutPurchases = `-- name: ListCheckoutPurchases :many
+SELECT id, event_id, venue_id, service_id, purchased_by, created_at, amount, booked_venue_id, booked_practitioner_id, currency, status, provider, provider_checkout_id, provider_payment_id, paid_at, cancelled_at, tax_amount, pending_until FROM purchases
+WHERE provider = $1 AND provider_checkout_id = $2
+`
+
+type ListCheckoutPurchasesParams struct {
+	Provider           sql.NullString `json:"provider"`
+	ProviderCheckoutID sql.NullString `json:"provider_checkout_id"`
+}
+
+func (q *Queries) ListCheckoutPurchases(ctx context.Context, arg ListCheckoutPurchasesParams) ([]Purchases, error) {
+	rows, err := q.db.QueryContext(ctx, listCheckoutPurchases, arg.Provider, arg.ProviderCheckoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Purchases
+	for rows.Next() {
+		var i Purchases
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.VenueID,
+			&i.ServiceID,
+			&i.PurchasedBy,
+			&i.CreatedAt,
+			&i.Amount,
+			&i.BookedVenueID,
+			&i.BookedPractitionerID,
+			&i.Currency,
+			&i.Status,
+			&i.Provider,
+			&i.ProviderCheckoutID,
+			&i.ProviderPaymentID,
+			&i.PaidAt,
+			&i.CancelledAt,
+			&i.TaxAmount,
+			&i.PendingUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExpiredPurchases = `-- name: ListExpiredPurchases :many
+SELECT id, event_id, venue_id, service_id, purchased_by, created_at, amount, booked_venue_id, booked_practitioner_id, currency, status, provider, provider_checkout_id, provider_payment_id, paid_at, cancelled_at, tax_amount, pending_until FROM purchases
+WHERE status = 'pending'
+  AND pending_until <= now()
+  AND ($1::uuid IS NULL OR event_id = $1)
+  AND ($2::uuid IS NULL OR venue_id = $2)
+  AND ($3::uuid IS NULL OR service_id = $3)
+`
+
+type ListExpiredPurchasesParams struct {
+	EventID   uuid.NullUUID `json:"event_id"`
+	VenueID   uuid.NullUUID `json:"venue_id"`
+	ServiceID uuid.NullUUID `json:"service_id"`
+}
+
+// Lists the pending purchases whose checkout has expired, optionally only
+// those of one event, venue or service.
+func (q *Queries) ListExpiredPurchases(ctx context.Context, arg ListExpiredPurchasesParams) ([]Purchases, error) {
+	rows, err := q.db.QueryContext(ctx, listExpiredPurchases, arg.EventID, arg.VenueID, arg.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Purchases
+	for rows.Next() {
+		var i Purchases
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.VenueID,
+			&i.ServiceID,
+			&i.PurchasedBy,
+			&i.CreatedAt,
+			&i.Amount,
+			&i.BookedVenueID,
+			&i.BookedPractitionerID,
+			&i.Currency,
+			&i.Status,
+			&i.Provider,
+			&i.ProviderCheckoutID,
+			&i.ProviderPaymentID,
+			&i.PaidAt,
+			&i.CancelledAt,
+			&i.TaxAmount,
+			&i.PendingUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLivePurchasesByEventForUpdate = `-- name: ListLivePurchasesByEventForUpdate :many
+SELECT id, event_id, venue_id, service_id, purchased_by, created_at, amount, booked_venue_id, booked_practitioner_id, currency, status, provider, provider_checkout_id, provider_payment_id, paid_at, cancelled_at, tax_amount, pending_until FROM purchases
+WHERE event_id = $1 AND status IN ('pending', 'paid')
+ORDER BY created_at, id
+FOR UPDATE
+`
+
+func (q *Queries) ListLivePurchasesByEventForUpdate(ctx context.Context, eventID uuid.NullUUID) ([]Purchases, error) {
+	rows, err := q.db.QueryContext(ctx, listLivePurchasesByEventForUpdate, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Purchases
+	for rows.Next() {
+		var i Purchases
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.VenueID,
+			&i.ServiceID,
+			&i.PurchasedBy,
+			&i.CreatedAt,
+			&i.Amount,
+			&i.BookedVenueID,
+			&i.BookedPractitionerID,
+			&i.Currency,
+			&i.Status,
+			&i.Provider,
+			&i.ProviderCheckoutID,
+			&i.ProviderPaymentID,
+			&i.PaidAt,
+			&i.CancelledAt,
+			&i.TaxAmount,
+			&i.PendingUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPurchasesByEvent = `-- name: ListPurchasesByEvent :many
-SELECT id, event_id, venue_id, service_id, purchased_by, created_at FROM purchases
+SELECT id, event_id, venue_id, service_id, purchased_by, created_at, amount, booked_venue_id, booked_practitioner_id, currency, status, provider, provider_checkout_id, provider_payment_id, paid_at, cancelled_at, tax_amount, pending_until FROM purchases
 WHERE event_id = $1
-ORDER BY created_at DESC
+  AND ($2::uuid IS NULL
+    OR (created_at, id) < ($3::timestamp, $2::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT $4
 `
 
-func (q *Queries) ListPurchasesByEvent(ctx context.Context, eventID uuid.NullUUID) ([]Purchases, error) {
-	rows, err := q.db.QueryContext(ctx, listPurchasesByEvent, eventID)
+type ListPurchasesByEventParams struct {
+	EventID         uuid.NullUUID `json:"event_id"`
+	CursorID        uuid.NullUUID `json:"cursor_id"`
+	CursorCreatedAt sql.NullTime  `json:"cursor_created_at"`
+	PageLimit       int32         `json:"page_limit"`
+}
+
+func (q *Queries) ListPurchasesByEvent(ctx context.Context, arg ListPurchasesByEventParams) ([]Purchases, error) {
+	rows, err := q.db.QueryContext(ctx, listPurchasesByEvent,
+		arg.EventID,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -100,6 +543,18 @@ func (q *Queries) ListPurchasesByEvent(ctx context.Context, eventID uuid.NullUUI
 			&i.ServiceID,
 			&i.PurchasedBy,
 			&i.CreatedAt,
+			&i.Amount,
+			&i.BookedVenueID,
+			&i.BookedPractitionerID,
+			&i.Currency,
+			&i.Status,
+			&i.Provider,
+			&i.ProviderCheckoutID,
+			&i.ProviderPaymentID,
+			&i.PaidAt,
+			&i.CancelledAt,
+			&i.TaxAmount,
+			&i.PendingUntil,
 		); err != nil {
 			return nil, err
 		}
@@ -115,13 +570,28 @@ func (q *Queries) ListPurchasesByEvent(ctx context.Context, eventID uuid.NullUUI
 }
 
 const listPurchasesByService = `-- name: ListPurchasesByService :many
-SELECT id, event_id, venue_id, service_id, purchased_by, created_at FROM purchases
+SELECT id, event_id, venue_id, service_id, purchased_by, created_at, amount, booked_venue_id, booked_practitioner_id, currency, status, provider, provider_checkout_id, provider_payment_id, paid_at, cancelled_at, tax_amount, pending_until FROM purchases
 WHERE service_id = $1
-ORDER BY created_at DESC
+  AND ($2::uuid IS NULL
+    OR (created_at, id) < ($3::timestamp, $2::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT $4
 `
 
-func (q *Queries) ListPurchasesByService(ctx context.Context, serviceID uuid.NullUUID) ([]Purchases, error) {
-	rows, err := q.db.QueryContext(ctx, listPurchasesByService, serviceID)
+type ListPurchasesByServiceParams struct {
+	ServiceID       uuid.NullUUID `json:"service_id"`
+	CursorID        uuid.NullUUID `json:"cursor_id"`
+	CursorCreatedAt sql.NullTime  `json:"cursor_created_at"`
+	PageLimit       int32         `json:"page_limit"`
+}
+
+func (q *Queries) ListPurchasesByService(ctx context.Context, arg ListPurchasesByServiceParams) ([]Purchases, error) {
+	rows, err := q.db.QueryContext(ctx, listPurchasesByService,
+		arg.ServiceID,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -136,6 +606,18 @@ func (q *Queries) ListPurchasesByService(ctx context.Context, serviceID uuid.Nul
 			&i.ServiceID,
 			&i.PurchasedBy,
 			&i.CreatedAt,
+			&i.Amount,
+			&i.BookedVenueID,
+			&i.BookedPractitionerID,
+			&i.Currency,
+			&i.Status,
+			&i.Provider,
+			&i.ProviderCheckoutID,
+			&i.ProviderPaymentID,
+			&i.PaidAt,
+			&i.CancelledAt,
+			&i.TaxAmount,
+			&i.PendingUntil,
 		); err != nil {
 			return nil, err
 		}
@@ -151,13 +633,28 @@ func (q *Queries) ListPurchasesByService(ctx context.Context, serviceID uuid.Nul
 }
 
 const listPurchasesByUser = `-- name: ListPurchasesByUser :many
-SELECT id, event_id, venue_id, service_id, purchased_by, created_at FROM purchases
+SELECT id, event_id, venue_id, service_id, purchased_by, created_at, amount, booked_venue_id, booked_practitioner_id, currency, status, provider, provider_checkout_id, provider_payment_id, paid_at, cancelled_at, tax_amount, pending_until FROM purchases
 WHERE purchased_by = $1
-ORDER BY created_at DESC
+  AND ($2::uuid IS NULL
+    OR (created_at, id) < ($3::timestamp, $2::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT $4
 `
 
-func (q *Queries) ListPurchasesByUser(ctx context.Context, purchasedBy uuid.NullUUID) ([]Purchases, error) {
-	rows, err := q.db.QueryContext(ctx, listPurchasesByUser, purchasedBy)
+type ListPurchasesByUserParams struct {
+	PurchasedBy     uuid.NullUUID `json:"purchased_by"`
+	CursorID        uuid.NullUUID `json:"cursor_id"`
+	CursorCreatedAt sql.NullTime  `json:"cursor_created_at"`
+	PageLimit       int32         `json:"page_limit"`
+}
+
+func (q *Queries) ListPurchasesByUser(ctx context.Context, arg ListPurchasesByUserParams) ([]Purchases, error) {
+	rows, err := q.db.QueryContext(ctx, listPurchasesByUser,
+		arg.PurchasedBy,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -172,6 +669,18 @@ func (q *Queries) ListPurchasesByUser(ctx context.Context, purchasedBy uuid.Null
 			&i.ServiceID,
 			&i.PurchasedBy,
 			&i.CreatedAt,
+			&i.Amount,
+			&i.BookedVenueID,
+			&i.BookedPractitionerID,
+			&i.Currency,
+			&i.Status,
+			&i.Provider,
+			&i.ProviderCheckoutID,
+			&i.ProviderPaymentID,
+			&i.PaidAt,
+			&i.CancelledAt,
+			&i.TaxAmount,
+			&i.PendingUntil,
 		); err != nil {
 			return nil, err
 		}
@@ -187,13 +696,165 @@ func (q *Queries) ListPurchasesByUser(ctx context.Context, purchasedBy uuid.Null
 }
 
 const listPurchasesByVenue = `-- name: ListPurchasesByVenue :many
-SELECT id, event_id, venue_id, service_id, purchased_by, created_at FROM purchases
+SELECT id, event_id, venue_id, service_id, purchased_by, created_at, amount, booked_venue_id, booked_practitioner_id, currency, status, provider, provider_checkout_id, provider_payment_id, paid_at, cancelled_at, tax_amount, pending_until FROM purchases
 WHERE venue_id = $1
-ORDER BY created_at DESC
+  AND ($2::uuid IS NULL
+    OR (created_at, id) < ($3::timestamp, $2::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT $4
+`
+
+type ListPurchasesByVenueParams struct {
+	VenueID         uuid.NullUUID `json:"venue_id"`
+	CursorID        uuid.NullUUID `json:"cursor_id"`
+	CursorCreatedAt sql.NullTime  `json:"cursor_created_at"`
+	PageLimit       int32         `json:"page_limit"`
+}
+
+func (q *Queries) ListPurchasesByVenue(ctx context.Context, arg ListPurchasesByVenueParams) ([]Purchases, error) {
+	rows, err := q.db.QueryContext(ctx, listPurchasesByVenue,
+		arg.VenueID,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Purchases
+	for rows.Next() {
+		var i Purchases
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.VenueID,
+			&i.ServiceID,
+			&i.PurchasedBy,
+			&i.CreatedAt,
+			&i.Amount,
+			&i.BookedVenueID,
+			&i.BookedPractitionerID,
+			&i.Currency,
+			&i.Status,
+			&i.Provider,
+			&i.ProviderCheckoutID,
+			&i.ProviderPaymentID,
+			&i.PaidAt,
+			&i.CancelledAt,
+			&i.TaxAmount,
+			&i.PendingUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordPaymentEvent = `-- name: RecordPaymentEvent :execrows
+INSERT INTO payment_events (
+  provider,
+  event_id,
+  type,
+  checkout_id
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT DO NOTHING
+`
+
+type RecordPaymentEventParams struct {
+	Provider   string `json:"provider"`
+	EventID    string `json:"event_id"`
+	Type       string `json:"type"`
+	CheckoutID string `json:"checkout_id"`
+}
+
+func (q *Queries) RecordPaymentEvent(ctx context.Context, arg RecordPaymentEventParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, recordPaymentEvent,
+		arg.Provider,
+		arg.EventID,
+		arg.Type,
+		arg.CheckoutID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setPurchaseCheckout = `-- name: SetPurchaseCheckout :one
+UPDATE purchases
+SET provider = $1::varchar,
+    provider_checkout_id = $2::varchar
+WHERE id = $3 AND status = 'pending'
+RETURNING id, event_id, venue_id, service_id, purchased_by, created_at, amount, booked_venue_id, booked_practitioner_id, currency, status, provider, provider_checkout_id, provider_payment_id, paid_at, cancelled_at, tax_amount, pending_until
+`
+
+type SetPurchaseCheckoutParams struct {
+	Provider           string    `json:"provider"`
+	ProviderCheckoutID string    `json:"provider_checkout_id"`
+	ID                 uuid.UUID `json:"id"`
+}
+
+func (q *Queries) SetPurchaseCheckout(ctx context.Context, arg SetPurchaseCheckoutParams) (Purchases, error) {
+	row := q.db.QueryRowContext(ctx, setPurchaseCheckout, arg.Provider, arg.ProviderCheckoutID, arg.ID)
+	var i Purchases
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.VenueID,
+		&i.ServiceID,
+		&i.PurchasedBy,
+		&i.CreatedAt,
+		&i.Amount,
+		&i.BookedVenueID,
+		&i.BookedPractitionerID,
+		&i.Currency,
+		&i.Status,
+		&i.Provider,
+		&i.ProviderCheckoutID,
+		&i.ProviderPaymentID,
+		&i.PaidAt,
+		&i.CancelledAt,
+		&i.TaxAmount,
+		&i.PendingUntil,
+	)
+	return i, err
+}
+
+const settleCheckoutPurchases = `-- name: SettleCheckoutPurchases :many
+UPDATE purchases
+SET status = $1::varchar,
+    provider_payment_id = $2,
+    paid_at = CASE WHEN $1::varchar = 'paid' THEN now() END
+WHERE provider = $3::varchar
+  AND provider_checkout_id = $4::varchar
+  AND status = 'pending'
+RETURNING id, event_id, venue_id, service_id, purchased_by, created_at, amount, booked_venue_id, booked_practitioner_id, currency, status, provider, provider_checkout_id, provider_payment_id, paid_at, cancelled_at, tax_amount, pending_until
 `
 
-func (q *Queries) ListPurchasesByVenue(ctx context.Context, venueID uuid.NullUUID) ([]Purchases, error) {
-	rows, err := q.db.QueryContext(ctx, listPurchasesByVenue, venueID)
+type SettleCheckoutPurchasesParams struct {
+	Status             string         `json:"status"`
+	ProviderPaymentID  sql.NullString `json:"provider_payment_id"`
+	Provider           string         `json:"provider"`
+	ProviderCheckoutID string         `json:"provider_checkout_id"`
+}
+
+func (q *Queries) SettleCheckoutPurchases(ctx context.Context, arg SettleCheckoutPurchasesParams) ([]Purchases, error) {
+	rows, err := q.db.QueryContext(ctx, settleCheckoutPurchases,
+		arg.Status,
+		arg.ProviderPaymentID,
+		arg.Provider,
+		arg.ProviderCheckoutID,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -208,6 +869,18 @@ func (q *Queries) ListPurchasesByVenue(ctx context.Context, venueID uuid.NullUUI
 			&i.ServiceID,
 			&i.PurchasedBy,
 			&i.CreatedAt,
+			&i.Amount,
+			&i.BookedVenueID,
+			&i.BookedPractitionerID,
+			&i.Currency,
+			&i.Status,
+			&i.Provider,
+			&i.ProviderCheckoutID,
+			&i.ProviderPaymentID,
+			&i.PaidAt,
+			&i.CancelledAt,
+			&i.TaxAmount,
+			&i.PendingUntil,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,162 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Querier interface {
+	BlockSession(ctx context.Context, id uuid.UUID) error
+	CancelBookedPractitioner(ctx context.Context, id uuid.UUID) (BookedPractitioners, error)
+	CancelBookedVenue(ctx context.Context, id uuid.UUID) (BookedVenues, error)
+	CancelPurchase(ctx context.Context, id uuid.UUID) (Purchases, error)
+	ClaimEventHold(ctx context.Context, arg ClaimEventHoldParams) (int64, error)
+	ClaimVenueHold(ctx context.Context, arg ClaimVenueHoldParams) (int64, error)
+	CountBookedPractitionersBetween(ctx context.Context, arg CountBookedPractitionersBetweenParams) (int64, error)
+	CountBookedVenueDays(ctx context.Context, arg CountBookedVenueDaysParams) (int64, error)
+	CountBookedVenuesBetween(ctx context.Context, arg CountBookedVenuesBetweenParams) (int64, error)
+	CountEventHolds(ctx context.Context, eventID uuid.NullUUID) (int64, error)
+	CountFavouritesByEvents(ctx context.Context, eventIds []uuid.UUID) ([]CountFavouritesByEventsRow, error)
+	CountPaidCheckoutPurchases(ctx context.Context, arg CountPaidCheckoutPurchasesParams) (int64, error)
+	CountPurchasesByEvent(ctx context.Context, eventID uuid.NullUUID) (int64, error)
+	CountTicketsByEvent(ctx context.Context, eventID uuid.NullUUID) (int64, error)
+	CountTicketsByTier(ctx context.Context, tierID uuid.UUID) (int64, error)
+	// Counts the venues matching a search by each value of the facet columns.
+	CountVenueFacets(ctx context.Context, arg CountVenueFacetsParams) ([]CountVenueFacetsRow, error)
+	CountVenueHolds(ctx context.Context, arg CountVenueHoldsParams) (int64, error)
+	CreateBookedPractitioner(ctx context.Context, arg CreateBookedPractitionerParams) (BookedPractitioners, error)
+	CreateBookedVenue(ctx context.Context, arg CreateBookedVenueParams) (BookedVenues, error)
+	CreateCancellation(ctx context.Context, arg CreateCancellationParams) (Cancellations, error)
+	CreateEvent(ctx context.Context, arg CreateEventParams) (Events, error)
+	// Favouriting is idempotent: an existing favourite is returned unchanged.
+	CreateFavourite(ctx context.Context, arg CreateFavouriteParams) (Favourites, error)
+	CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (Invoices, error)
+	CreateInvoiceLine(ctx context.Context, arg CreateInvoiceLineParams) (InvoiceLines, error)
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notifications, error)
+	CreatePractitioner(ctx context.Context, arg CreatePractitionerParams) (Practitioners, error)
+	CreateProfile(ctx context.Context, arg CreateProfileParams) (Profiles, error)
+	CreatePurchase(ctx context.Context, arg CreatePurchaseParams) (Purchases, error)
+	CreatePurchaseTax(ctx context.Context, arg CreatePurchaseTaxParams) (PurchaseTaxes, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Sessions, error)
+	CreateTicket(ctx context.Context, arg CreateTicketParams) (Tickets, error)
+	CreateTicketTier(ctx context.Context, arg CreateTicketTierParams) (TicketTiers, error)
+	CreateUser(ctx context.Context, arg CreateUserParams) (Users, error)
+	CreateVenue(ctx context.Context, arg CreateVenueParams) (Venues, error)
+	CreateWaitlistEntry(ctx context.Context, arg CreateWaitlistEntryParams) (WaitlistEntries, error)
+	DeleteEvent(ctx context.Context, id uuid.UUID) error
+	DeleteFavourite(ctx context.Context, id uuid.UUID) error
+	DeleteFavouriteByUserAndEvent(ctx context.Context, arg DeleteFavouriteByUserAndEventParams) error
+	DeletePractitioner(ctx context.Context, id uuid.UUID) error
+	DeleteProfile(ctx context.Context, id uuid.UUID) error
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+	DeleteVenue(ctx context.Context, id uuid.UUID) error
+	ExpireWaitlistHold(ctx context.Context, id uuid.UUID) (WaitlistEntries, error)
+	// Fails the pending purchases whose checkout has expired, optionally only
+	// those of one event, venue or service.
+	FailExpiredPurchases(ctx context.Context, arg FailExpiredPurchasesParams) ([]Purchases, error)
+	FailPurchase(ctx context.Context, id uuid.UUID) error
+	GetBookedPractitioner(ctx context.Context, id uuid.UUID) (BookedPractitioners, error)
+	GetBookedVenue(ctx context.Context, id uuid.UUID) (BookedVenues, error)
+	GetCancellationByPurchase(ctx context.Context, purchaseID uuid.UUID) (Cancellations, error)
+	GetEvent(ctx context.Context, id uuid.UUID) (Events, error)
+	GetEventForUpdate(ctx context.Context, id uuid.UUID) (Events, error)
+	GetExchangeRate(ctx context.Context, arg GetExchangeRateParams) (ExchangeRates, error)
+	GetFavourite(ctx context.Context, id uuid.UUID) (Favourites, error)
+	GetInvoiceByPurchase(ctx context.Context, purchaseID uuid.UUID) (Invoices, error)
+	GetPractitioner(ctx context.Context, id uuid.UUID) (Practitioners, error)
+	GetPractitionerForUpdate(ctx context.Context, id uuid.UUID) (Practitioners, error)
+	GetProfile(ctx context.Context, id uuid.UUID) (Profiles, error)
+	GetProfileMember(ctx context.Context, arg GetProfileMemberParams) (ProfilesUsers, error)
+	GetProfileOwner(ctx context.Context, profilesID uuid.UUID) (Users, error)
+	GetPurchase(ctx context.Context, id uuid.UUID) (Purchases, error)
+	GetPurchaseByBookedPractitioner(ctx context.Context, bookedPractitionerID uuid.NullUUID) (Purchases, error)
+	GetPurchaseByBookedVenue(ctx context.Context, bookedVenueID uuid.NullUUID) (Purchases, error)
+	GetPurchaseForUpdate(ctx context.Context, id uuid.UUID) (Purchases, error)
+	GetSession(ctx context.Context, id uuid.UUID) (Sessions, error)
+	GetTicketByPurchase(ctx context.Context, purchaseID uuid.UUID) (Tickets, error)
+	GetTicketTier(ctx context.Context, id uuid.UUID) (TicketTiers, error)
+	GetUser(ctx context.Context, id uuid.UUID) (Users, error)
+	GetUserByEmail(ctx context.Context, email string) (Users, error)
+	GetVenue(ctx context.Context, id uuid.UUID) (Venues, error)
+	GetVenueForUpdate(ctx context.Context, id uuid.UUID) (Venues, error)
+	GetWaitlistEntry(ctx context.Context, id uuid.UUID) (WaitlistEntries, error)
+	LinkUserToProfile(ctx context.Context, arg LinkUserToProfileParams) (ProfilesUsers, error)
+	ListBookedPractitionersBetween(ctx context.Context, arg ListBookedPractitionersBetweenParams) ([]BookedPractitioners, error)
+	ListBookedPractitionersByService(ctx context.Context, arg ListBookedPractitionersByServiceParams) ([]BookedPractitioners, error)
+	ListBookedPractitionersByUser(ctx context.Context, arg ListBookedPractitionersByUserParams) ([]BookedPractitioners, error)
+	ListBookedVenuesBetween(ctx context.Context, arg ListBookedVenuesBetweenParams) ([]BookedVenues, error)
+	ListBookedVenuesByUser(ctx context.Context, arg ListBookedVenuesByUserParams) ([]BookedVenues, error)
+	ListBookedVenuesByVenue(ctx context.Context, arg ListBookedVenuesByVenueParams) ([]BookedVenues, error)
+	ListCheckoutPurchases(ctx context.Context, arg ListCheckoutPurchasesParams) ([]Purchases, error)
+	ListEvents(ctx context.Context, arg ListEventsParams) ([]Events, error)
+	ListEventsByCreator(ctx context.Context, createdBy uuid.NullUUID) ([]Events, error)
+	ListExchangeRates(ctx context.Context) ([]ExchangeRates, error)
+	// Lists the pending purchases whose checkout has expired, optionally only
+	// those of one event, venue or service.
+	ListExpiredPurchases(ctx context.Context, arg ListExpiredPurchasesParams) ([]Purchases, error)
+	ListFavouriteEventsByUser(ctx context.Context, arg ListFavouriteEventsByUserParams) ([]ListFavouriteEventsByUserRow, error)
+	ListFavouritesByEvent(ctx context.Context, eventID uuid.NullUUID) ([]Favourites, error)
+	ListFavouritesByUser(ctx context.Context, addedBy uuid.NullUUID) ([]Favourites, error)
+	ListInvoiceLines(ctx context.Context, invoiceID uuid.UUID) ([]InvoiceLines, error)
+	// Lists the holds that ended before their place was bought, optionally only
+	// those of one event or venue.
+	ListLapsedHolds(ctx context.Context, arg ListLapsedHoldsParams) ([]WaitlistEntries, error)
+	ListLivePurchasesByEventForUpdate(ctx context.Context, eventID uuid.NullUUID) ([]Purchases, error)
+	ListNotificationsByProfile(ctx context.Context, arg ListNotificationsByProfileParams) ([]Notifications, error)
+	ListPractitioners(ctx context.Context, arg ListPractitionersParams) ([]Practitioners, error)
+	ListProfileMembers(ctx context.Context, arg ListProfileMembersParams) ([]ListProfileMembersRow, error)
+	ListProfileMembershipsByUser(ctx context.Context, arg ListProfileMembershipsByUserParams) ([]ListProfileMembershipsByUserRow, error)
+	ListProfileOwnersForUpdate(ctx context.Context, profilesID uuid.UUID) ([]ProfilesUsers, error)
+	ListProfiles(ctx context.Context, arg ListProfilesParams) ([]Profiles, error)
+	ListProfilesByUser(ctx context.Context, usersID uuid.UUID) ([]ListProfilesByUserRow, error)
+	ListPurchaseTaxes(ctx context.Context, purchaseID uuid.UUID) ([]PurchaseTaxes, error)
+	ListPurchasesByEvent(ctx context.Context, arg ListPurchasesByEventParams) ([]Purchases, error)
+	ListPurchasesByService(ctx context.Context, arg ListPurchasesByServiceParams) ([]Purchases, error)
+	ListPurchasesByUser(ctx context.Context, arg ListPurchasesByUserParams) ([]Purchases, error)
+	ListPurchasesByVenue(ctx context.Context, arg ListPurchasesByVenueParams) ([]Purchases, error)
+	ListTicketTiersByEvent(ctx context.Context, arg ListTicketTiersByEventParams) ([]TicketTiers, error)
+	ListUsers(ctx context.Context, arg ListUsersParams) ([]Users, error)
+	// The bounding box narrows the candidates down using the index before the
+	// haversine distance is computed.
+	ListVenuesNearby(ctx context.Context, arg ListVenuesNearbyParams) ([]ListVenuesNearbyRow, error)
+	ListWaitingByEvent(ctx context.Context, arg ListWaitingByEventParams) ([]WaitlistEntries, error)
+	ListWaitingByVenue(ctx context.Context, arg ListWaitingByVenueParams) ([]WaitlistEntries, error)
+	Listvenues(ctx context.Context, arg ListvenuesParams) ([]Venues, error)
+	NextInvoiceNumber(ctx context.Context, profileID uuid.UUID) (int64, error)
+	NextWaitingForEvent(ctx context.Context, eventID uuid.NullUUID) (WaitlistEntries, error)
+	NextWaitingForVenue(ctx context.Context, arg NextWaitingForVenueParams) (WaitlistEntries, error)
+	PromoteWaitlistEntry(ctx context.Context, arg PromoteWaitlistEntryParams) (WaitlistEntries, error)
+	RecordCancellationRefund(ctx context.Context, arg RecordCancellationRefundParams) (Cancellations, error)
+	RecordPaymentEvent(ctx context.Context, arg RecordPaymentEventParams) (int64, error)
+	// Drafts are private to their organiser and never appear in search results.
+	SearchEventsText(ctx context.Context, arg SearchEventsTextParams) ([]SearchEventsTextRow, error)
+	SearchPractitionersText(ctx context.Context, arg SearchPractitionersTextParams) ([]SearchPractitionersTextRow, error)
+	SearchVenues(ctx context.Context, arg SearchVenuesParams) ([]Venues, error)
+	SearchVenuesText(ctx context.Context, arg SearchVenuesTextParams) ([]SearchVenuesTextRow, error)
+	SetPurchaseCheckout(ctx context.Context, arg SetPurchaseCheckoutParams) (Purchases, error)
+	SettleCheckoutPurchases(ctx context.Context, arg SettleCheckoutPurchasesParams) ([]Purchases, error)
+	UnlinkAllUsersFromProfile(ctx context.Context, profilesID uuid.UUID) error
+	UnlinkUserFromProfile(ctx context.Context, arg UnlinkUserFromProfileParams) (int64, error)
+	UpdateBookedPractitioner(ctx context.Context, arg UpdateBookedPractitionerParams) (BookedPractitioners, error)
+	UpdateBookedVenue(ctx context.Context, arg UpdateBookedVenueParams) (BookedVenues, error)
+	UpdateEvent(ctx context.Context, arg UpdateEventParams) (Events, error)
+	UpdateEventCancellationPolicy(ctx context.Context, arg UpdateEventCancellationPolicyParams) (Events, error)
+	UpdateEventStatus(ctx context.Context, arg UpdateEventStatusParams) (Events, error)
+	UpdatePractitioner(ctx context.Context, arg UpdatePractitionerParams) (Practitioners, error)
+	UpdatePractitionerCancellationPolicy(ctx context.Context, arg UpdatePractitionerCancellationPolicyParams) (Practitioners, error)
+	UpdateProfile(ctx context.Context, arg UpdateProfileParams) (Profiles, error)
+	UpdateProfileMemberRole(ctx context.Context, arg UpdateProfileMemberRoleParams) (ProfilesUsers, error)
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (Users, error)
+	UpdateVenue(ctx context.Context, arg UpdateVenueParams) (Venues, error)
+	UpdateVenueCancellationPolicy(ctx context.Context, arg UpdateVenueCancellationPolicyParams) (Venues, error)
+	UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) (ExchangeRates, error)
+	WithdrawEventWaitlistEntry(ctx context.Context, arg WithdrawEventWaitlistEntryParams) (int64, error)
+	WithdrawVenueWaitlistEntry(ctx context.Context, arg WithdrawVenueWaitlistEntryParams) (int64, error)
+}
+
+var _ Querier = (*Queries)(nil)
@@ -0,0 +1,201 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: search.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const searchEventsText = `-- name: SearchEventsText :many
+WITH matches AS (
+  SELECT events.id, coalesce(events.name, '')::varchar AS name, ts_rank(events.search_vector, query) AS rank,
+    concat_ws(' ', events.name, events.theme, events.description, events.audience) AS document, query
+  FROM events, websearch_to_tsquery('english', $4::text) AS query
+  WHERE events.search_vector @@ query
+    AND events.status <> 'draft'
+)
+SELECT id, name, rank, ts_headline('english', document, query, 'MaxWords=30, MinWords=10')::text AS snippet
+FROM matches
+WHERE $1::uuid IS NULL
+  OR (rank, id) < ($2::real, $1::uuid)
+ORDER BY rank DESC, id DESC
+LIMIT $3
+`
+
+type SearchEventsTextParams struct {
+	CursorID   uuid.NullUUID   `json:"cursor_id"`
+	CursorRank sql.NullFloat64 `json:"cursor_rank"`
+	PageLimit  int32           `json:"page_limit"`
+	Query      string          `json:"query"`
+}
+
+type SearchEventsTextRow struct {
+	ID      uuid.UUID `json:"id"`
+	Name    string    `json:"name"`
+	Rank    float32   `json:"rank"`
+	Snippet string    `json:"snippet"`
+}
+
+// Drafts are private to their organiser and never appear in search results.
+func (q *Queries) SearchEventsText(ctx context.Context, arg SearchEventsTextParams) ([]SearchEventsTextRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchEventsText,
+		arg.CursorID,
+		arg.CursorRank,
+		arg.PageLimit,
+		arg.Query,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchEventsTextRow
+	for rows.Next() {
+		var i SearchEventsTextRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchPractitionersText = `-- name: SearchPractitionersText :many
+WITH matches AS (
+  SELECT practitioners.id, practitioners.name, ts_rank(practitioners.search_vector, query) AS rank,
+    concat_ws(' ', practitioners.name, practitioners.description) AS document, query
+  FROM practitioners, websearch_to_tsquery('english', $4::text) AS query
+  WHERE practitioners.search_vector @@ query
+)
+SELECT id, name, rank, ts_headline('english', document, query, 'MaxWords=30, MinWords=10')::text AS snippet
+FROM matches
+WHERE $1::uuid IS NULL
+  OR (rank, id) < ($2::real, $1::uuid)
+ORDER BY rank DESC, id DESC
+LIMIT $3
+`
+
+type SearchPractitionersTextParams struct {
+	CursorID   uuid.NullUUID   `json:"cursor_id"`
+	CursorRank sql.NullFloat64 `json:"cursor_rank"`
+	PageLimit  int32           `json:"page_limit"`
+	Query      string          `json:"query"`
+}
+
+type SearchPractitionersTextRow struct {
+	ID      uuid.UUID `json:"id"`
+	Name    string    `json:"name"`
+	Rank    float32   `json:"rank"`
+	Snippet string    `json:"snippet"`
+}
+
+func (q *Queries) SearchPractitionersText(ctx context.Context, arg SearchPractitionersTextParams) ([]SearchPractitionersTextRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPractitionersText,
+		arg.CursorID,
+		arg.CursorRank,
+		arg.PageLimit,
+		arg.Query,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchPractitionersTextRow
+	for rows.Next() {
+		var i SearchPractitionersTextRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchVenuesText = `-- name: SearchVenuesText :many
+WITH matches AS (
+  SELECT venues.id, venues.name, ts_rank(venues.search_vector, query) AS rank,
+    concat_ws(' ', venues.name, venues.type, venues.location, venues.description) AS document, query
+  FROM venues, websearch_to_tsquery('english', $4::text) AS query
+  WHERE venues.search_vector @@ query
+)
+SELECT id, name, rank, ts_headline('english', document, query, 'MaxWords=30, MinWords=10')::text AS snippet
+FROM matches
+WHERE $1::uuid IS NULL
+  OR (rank, id) < ($2::real, $1::uuid)
+ORDER BY rank DESC, id DESC
+LIMIT $3
+`
+
+type SearchVenuesTextParams struct {
+	CursorID   uuid.NullUUID   `json:"cursor_id"`
+	CursorRank sql.NullFloat64 `json:"cursor_rank"`
+	PageLimit  int32           `json:"page_limit"`
+	Query      string          `json:"query"`
+}
+
+type SearchVenuesTextRow struct {
+	ID      uuid.UUID `json:"id"`
+	Name    string    `json:"name"`
+	Rank    float32   `json:"rank"`
+	Snippet string    `json:"snippet"`
+}
+
+func (q *Queries) SearchVenuesText(ctx context.Context, arg SearchVenuesTextParams) ([]SearchVenuesTextRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchVenuesText,
+		arg.CursorID,
+		arg.CursorRank,
+		arg.PageLimit,
+		arg.Query,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchVenuesTextRow
+	for rows.Next() {
+		var i SearchVenuesTextRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
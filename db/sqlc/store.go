@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Store provides all functions to execute db queries and transactions.
+type Store interface {
+	Querier
+	BookVenueTx(ctx context.Context, arg BookVenueTxParams) (BookVenueTxResult, error)
+	BookPractitionerTx(ctx context.Context, arg BookPractitionerTxParams) (BookPractitionerTxResult, error)
+	UpdateEventTx(ctx context.Context, arg UpdateEventParams) (Events, error)
+	CancelEventTx(ctx context.Context, arg CancelTxParams) (CancelEventTxResult, error)
+	PurchaseTicketsTx(ctx context.Context, arg PurchaseTicketsTxParams) (PurchaseTicketsTxResult, error)
+	CancelPurchaseTx(ctx context.Context, arg CancelTxParams) (CancelTxResult, error)
+	CancelVenueBookingTx(ctx context.Context, arg CancelTxParams) (CancelTxResult, error)
+	CancelPractitionerBookingTx(ctx context.Context, arg CancelTxParams) (CancelTxResult, error)
+	CreateProfileTx(ctx context.Context, arg CreateProfileTxParams) (CreateProfileTxResult, error)
+	DeleteProfileTx(ctx context.Context, profileID uuid.UUID) error
+	UpdateProfileMemberRoleTx(ctx context.Context, arg UpdateProfileMemberRoleParams) (ProfilesUsers, error)
+	RemoveProfileMemberTx(ctx context.Context, arg UnlinkUserFromProfileParams) error
+	ApplyPaymentEventTx(ctx context.Context, arg ApplyPaymentEventTxParams) (ApplyPaymentEventTxResult, error)
+	ExpirePendingPurchasesTx(ctx context.Context, holdFor time.Duration) ([]Purchases, error)
+	ExpireWaitlistHoldsTx(ctx context.Context, holdFor time.Duration) ([]WaitlistEntries, error)
+	IssueInvoiceTx(ctx context.Context, purchaseID uuid.UUID) (InvoiceTxResult, error)
+	LoadExchangeRatesTx(ctx context.Context, rates []UpsertExchangeRateParams) error
+}
+
+// SQLStore provides all functions to execute SQL queries and transactions.
+type SQLStore struct {
+	db *sql.DB
+	*Queries
+}
+
+// NewStore creates a new Store.
+func NewStore(db *sql.DB) Store {
+	return &SQLStore{
+		db:      db,
+		Queries: New(db),
+	}
+}
+
+// execTx executes a function within a database transaction.
+// The transaction is rolled back if fn returns an error, and committed otherwise.
+func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	q := New(tx)
+	err = fn(q)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,183 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: tickets.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const countTicketsByEvent = `-- name: CountTicketsByEvent :one
+SELECT count(*) FROM tickets
+JOIN purchases ON purchases.id = tickets.purchase_id
+WHERE purchases.event_id = $1 AND purchases.status IN ('pending', 'paid')
+`
+
+func (q *Queries) CountTicketsByEvent(ctx context.Context, eventID uuid.NullUUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countTicketsByEvent, eventID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countTicketsByTier = `-- name: CountTicketsByTier :one
+SELECT count(*) FROM tickets
+JOIN purchases ON purchases.id = tickets.purchase_id
+WHERE tickets.tier_id = $1 AND purchases.status IN ('pending', 'paid')
+`
+
+func (q *Queries) CountTicketsByTier(ctx context.Context, tierID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countTicketsByTier, tierID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createTicket = `-- name: CreateTicket :one
+INSERT INTO "tickets" (
+  purchase_id,
+  tier_id,
+  code
+) VALUES (
+  $1, $2, $3
+)
+RETURNING id, purchase_id, tier_id, code, created_at
+`
+
+type CreateTicketParams struct {
+	PurchaseID uuid.UUID `json:"purchase_id"`
+	TierID     uuid.UUID `json:"tier_id"`
+	Code       string    `json:"code"`
+}
+
+func (q *Queries) CreateTicket(ctx context.Context, arg CreateTicketParams) (Tickets, error) {
+	row := q.db.QueryRowContext(ctx, createTicket, arg.PurchaseID, arg.TierID, arg.Code)
+	var i Tickets
+	err := row.Scan(
+		&i.ID,
+		&i.PurchaseID,
+		&i.TierID,
+		&i.Code,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createTicketTier = `-- name: CreateTicketTier :one
+INSERT INTO "ticket_tiers" (
+  event_id,
+  name,
+  price,
+  quantity,
+  currency
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING id, event_id, name, price, quantity, created_at, currency
+`
+
+type CreateTicketTierParams struct {
+	EventID  uuid.UUID `json:"event_id"`
+	Name     string    `json:"name"`
+	Price    int32     `json:"price"`
+	Quantity int32     `json:"quantity"`
+	Currency string    `json:"currency"`
+}
+
+func (q *Queries) CreateTicketTier(ctx context.Context, arg CreateTicketTierParams) (TicketTiers, error) {
+	row := q.db.QueryRowContext(ctx, createTicketTier,
+		arg.EventID,
+		arg.Name,
+		arg.Price,
+		arg.Quantity,
+		arg.Currency,
+	)
+	var i TicketTiers
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.Name,
+		&i.Price,
+		&i.Quantity,
+		&i.CreatedAt,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const getTicketTier = `-- name: GetTicketTier :one
+SELECT id, event_id, name, price, quantity, created_at, currency FROM ticket_tiers
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetTicketTier(ctx context.Context, id uuid.UUID) (TicketTiers, error) {
+	row := q.db.QueryRowContext(ctx, getTicketTier, id)
+	var i TicketTiers
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.Name,
+		&i.Price,
+		&i.Quantity,
+		&i.CreatedAt,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const listTicketTiersByEvent = `-- name: ListTicketTiersByEvent :many
+SELECT id, event_id, name, price, quantity, created_at, currency FROM ticket_tiers
+WHERE event_id = $1
+  AND ($2::uuid IS NULL
+    OR (price, id) > ($3::integer, $2::uuid))
+ORDER BY price, id
+LIMIT $4
+`
+
+type ListTicketTiersByEventParams struct {
+	EventID     uuid.UUID     `json:"event_id"`
+	CursorID    uuid.NullUUID `json:"cursor_id"`
+	CursorPrice sql.NullInt32 `json:"cursor_price"`
+	PageLimit   int32         `json:"page_limit"`
+}
+
+func (q *Queries) ListTicketTiersByEvent(ctx context.Context, arg ListTicketTiersByEventParams) ([]TicketTiers, error) {
+	rows, err := q.db.QueryContext(ctx, listTicketTiersByEvent,
+		arg.EventID,
+		arg.CursorID,
+		arg.CursorPrice,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TicketTiers
+	for rows.Next() {
+		var i TicketTiers
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.Name,
+			&i.Price,
+			&i.Quantity,
+			&i.CreatedAt,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tedobanks/tabularasa_backend/tax"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// Errors returned by BookPractitionerTx when an appointment is rejected.
+var (
+	ErrPractitionerUnavailable = errors.New("practitioner is not available for booking")
+	ErrOutsideWorkingHours     = errors.New("appointment is outside the practitioner's working hours")
+	ErrAppointmentConflict     = errors.New("appointment conflicts with an existing booking")
+)
+
+// BookPractitionerTxParams contains the input parameters of the book practitioner transaction.
+type BookPractitionerTxParams struct {
+	ServiceID uuid.UUID     `json:"service_id"`
+	BookedBy  uuid.UUID     `json:"booked_by"`
+	BookedFor time.Time     `json:"booked_for"`
+	Type      string        `json:"type"`
+	Duration  time.Duration `json:"duration"`
+	Currency  string        `json:"currency"`
+	TaxRules  *tax.Rules    `json:"-"`
+}
+
+// BookPractitionerTxResult is the result of the book practitioner transaction.
+type BookPractitionerTxResult struct {
+	Practitioner Practitioners       `json:"practitioner"`
+	Booking      BookedPractitioners `json:"booking"`
+	Purchase     Purchases           `json:"purchase"`
+	Taxes        []PurchaseTaxes     `json:"taxes"`
+}
+
+// BookPractitionerTx books an appointment with a practitioner and records
+// the matching purchase, taxed under arg.TaxRules. Every appointment lasts arg.Duration, so two
+// appointments conflict when their start times are less than one duration
+// apart. The practitioner row is locked for the duration of the
+// transaction so concurrent bookings for the same service are serialised.
+func (store *SQLStore) BookPractitionerTx(ctx context.Context, arg BookPractitionerTxParams) (BookPractitionerTxResult, error) {
+	var result BookPractitionerTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Practitioner, err = q.GetPractitionerForUpdate(ctx, arg.ServiceID)
+		if err != nil {
+			return err
+		}
+
+		err = checkPractitionerWorking(result.Practitioner, arg.BookedFor, arg.Duration)
+		if err != nil {
+			return err
+		}
+
+		count, err := q.CountBookedPractitionersBetween(ctx, CountBookedPractitionersBetweenParams{
+			ServiceID: arg.ServiceID,
+			FromTime:  arg.BookedFor.Add(-arg.Duration),
+			ToTime:    arg.BookedFor.Add(arg.Duration),
+		})
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrAppointmentConflict
+		}
+
+		result.Booking, err = q.CreateBookedPractitioner(ctx, CreateBookedPractitionerParams{
+			Type:      sql.NullString{String: arg.Type, Valid: arg.Type != ""},
+			ServiceID: arg.ServiceID,
+			BookedFor: arg.BookedFor,
+			BookedBy:  arg.BookedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		supply := tax.Supply{Service: "practitioner", Type: arg.Type}
+		result.Purchase, result.Taxes, err = createTaxedPurchase(ctx, q, arg.TaxRules, result.Practitioner.CreatedBy, supply, CreatePurchaseParams{
+			ServiceID:            uuid.NullUUID{UUID: arg.ServiceID, Valid: true},
+			PurchasedBy:          uuid.NullUUID{UUID: arg.BookedBy, Valid: true},
+			BookedPractitionerID: uuid.NullUUID{UUID: result.Booking.ID, Valid: true},
+			Currency:             arg.Currency,
+			Status:               string(initialPurchaseStatus(sql.NullInt32{})),
+		})
+		if err != nil {
+			return err
+		}
+
+		return invoicePaidPurchase(ctx, q, result.Purchase)
+	})
+
+	return result, err
+}
+
+// checkPractitionerWorking validates a requested appointment against the
+// practitioner's availability flag, working days and working hours.
+func checkPractitionerWorking(practitioner Practitioners, start time.Time, duration time.Duration) error {
+	if !practitioner.IsAvailable.Valid || !practitioner.IsAvailable.Bool {
+		return ErrPractitionerUnavailable
+	}
+
+	days, err := util.ParseWeekdays(practitioner.WorkingDays.String)
+	if err != nil {
+		return fmt.Errorf("%w: working_days: %v", ErrInvalidScheduleData, err)
+	}
+	if !days.Contains(start.Weekday()) {
+		return ErrOutsideWorkingHours
+	}
+
+	hours := util.OpeningHours{
+		OpensAt:  practitioner.OpensAt.Time,
+		ClosesAt: practitioner.ClosesAt.Time,
+		Valid:    practitioner.OpensAt.Valid && practitioner.ClosesAt.Valid,
+	}
+	if !hours.Covers(start, start.Add(duration)) {
+		return ErrOutsideWorkingHours
+	}
+
+	return nil
+}
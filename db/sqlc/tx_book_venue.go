@@ -0,0 +1,172 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tedobanks/tabularasa_backend/tax"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// Errors returned by BookVenueTx when a booking is rejected.
+var (
+	ErrVenueUnavailable    = errors.New("venue is not available for booking")
+	ErrVenueClosed         = errors.New("venue is not open at the requested time")
+	ErrVenueAlreadyBooked  = errors.New("venue is already booked for the requested day")
+	ErrVenueDayHeld        = errors.New("venue day is held for a waitlisted profile")
+	ErrInvalidScheduleData = errors.New("invalid schedule data")
+)
+
+// BookVenueTxParams contains the input parameters of the book venue transaction.
+type BookVenueTxParams struct {
+	VenueID    uuid.UUID     `json:"venue_id"`
+	BookedBy   uuid.UUID     `json:"booked_by"`
+	BookedFor  time.Time     `json:"booked_for"`
+	Type       string        `json:"type"`
+	TaxRules   *tax.Rules    `json:"-"`
+	PendingTTL time.Duration `json:"-"` // how long an unpaid booking holds the day
+	HoldFor    time.Duration `json:"-"` // how long a day freed for a waitlisted profile is held
+}
+
+// BookVenueTxResult is the result of the book venue transaction.
+type BookVenueTxResult struct {
+	Venue    Venues          `json:"venue"`
+	Booking  BookedVenues    `json:"booking"`
+	Purchase Purchases       `json:"purchase"`
+	Taxes    []PurchaseTaxes `json:"taxes"`
+}
+
+// BookVenueTx books a venue for a day and records the matching purchase,
+// taxed under arg.TaxRules.
+// Venues are rented by the day, so a venue can only be booked once per
+// calendar day, and bookings whose purchase expired unpaid are released
+// first. The venue row is locked for the duration of the transaction so
+// concurrent bookings for the same venue are serialised.
+func (store *SQLStore) BookVenueTx(ctx context.Context, arg BookVenueTxParams) (BookVenueTxResult, error) {
+	var result BookVenueTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Venue, err = q.GetVenueForUpdate(ctx, arg.VenueID)
+		if err != nil {
+			return err
+		}
+
+		err = checkVenueOpen(result.Venue, arg.BookedFor)
+		if err != nil {
+			return err
+		}
+
+		// Days freed by unpaid bookings or lapsed holds go to the waitlist
+		// before anyone else can book them
+		locks := newPlaceLocks()
+		locks.venues[arg.VenueID] = result.Venue
+		_, err = expirePendingPurchases(ctx, q, locks, FailExpiredPurchasesParams{
+			VenueID: uuid.NullUUID{UUID: arg.VenueID, Valid: true},
+		}, arg.HoldFor)
+		if err != nil {
+			return err
+		}
+		_, err = expireLapsedHolds(ctx, q, locks, ListLapsedHoldsParams{
+			VenueID: uuid.NullUUID{UUID: arg.VenueID, Valid: true},
+		}, arg.HoldFor)
+		if err != nil {
+			return err
+		}
+
+		dayStart := util.StartOfDay(arg.BookedFor)
+		count, err := q.CountBookedVenuesBetween(ctx, CountBookedVenuesBetweenParams{
+			VenueID:  arg.VenueID,
+			FromTime: dayStart,
+			ToTime:   dayStart.AddDate(0, 0, 1),
+		})
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrVenueAlreadyBooked
+		}
+
+		// A day freed by a cancellation is held for the promoted profile
+		day := sql.NullTime{Time: dayStart, Valid: true}
+		_, err = q.ClaimVenueHold(ctx, ClaimVenueHoldParams{
+			VenueID:   uuid.NullUUID{UUID: arg.VenueID, Valid: true},
+			BookedFor: day,
+			ProfileID: arg.BookedBy,
+		})
+		if err != nil {
+			return err
+		}
+		held, err := q.CountVenueHolds(ctx, CountVenueHoldsParams{
+			VenueID:   uuid.NullUUID{UUID: arg.VenueID, Valid: true},
+			BookedFor: day,
+		})
+		if err != nil {
+			return err
+		}
+		if held > 0 {
+			return ErrVenueDayHeld
+		}
+
+		result.Booking, err = q.CreateBookedVenue(ctx, CreateBookedVenueParams{
+			Type:      sql.NullString{String: arg.Type, Valid: arg.Type != ""},
+			VenueID:   arg.VenueID,
+			BookedFor: arg.BookedFor,
+			BookedBy:  arg.BookedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		status := initialPurchaseStatus(result.Venue.BookingPrice)
+		supply := tax.Supply{Service: "venue", Type: result.Venue.Type.String}
+		result.Purchase, result.Taxes, err = createTaxedPurchase(ctx, q, arg.TaxRules, result.Venue.OwnedBy, supply, CreatePurchaseParams{
+			VenueID:       uuid.NullUUID{UUID: arg.VenueID, Valid: true},
+			PurchasedBy:   uuid.NullUUID{UUID: arg.BookedBy, Valid: true},
+			Amount:        result.Venue.BookingPrice,
+			BookedVenueID: uuid.NullUUID{UUID: result.Booking.ID, Valid: true},
+			Currency:      result.Venue.Currency,
+			Status:        string(status),
+			PendingUntil:  pendingUntil(status, arg.PendingTTL),
+		})
+		if err != nil {
+			return err
+		}
+
+		return invoicePaidPurchase(ctx, q, result.Purchase)
+	})
+
+	return result, err
+}
+
+// checkVenueOpen validates a requested booking time against the venue's
+// availability flag, rental days and opening hours.
+func checkVenueOpen(venue Venues, bookedFor time.Time) error {
+	if !venue.IsAvailable.Valid || !venue.IsAvailable.Bool {
+		return ErrVenueUnavailable
+	}
+
+	days, err := util.ParseWeekdays(venue.RentalDays.String)
+	if err != nil {
+		return fmt.Errorf("%w: rental_days: %v", ErrInvalidScheduleData, err)
+	}
+	if !days.Contains(bookedFor.Weekday()) {
+		return ErrVenueClosed
+	}
+
+	hours := util.OpeningHours{
+		OpensAt:  venue.OpensAt.Time,
+		ClosesAt: venue.ClosesAt.Time,
+		Valid:    venue.OpensAt.Valid && venue.ClosesAt.Valid,
+	}
+	if !hours.Contains(bookedFor) {
+		return ErrVenueClosed
+	}
+
+	return nil
+}
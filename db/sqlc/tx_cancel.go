@@ -0,0 +1,262 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// NotificationWaitlistPromoted is the kind of notification sent to a
+// profile whose waitlist entry was promoted.
+const NotificationWaitlistPromoted = "waitlist_promoted"
+
+// holdLayout formats the end of a hold in promotion notifications.
+const holdLayout = "2006-01-02 15:04 MST"
+
+// Errors returned by the cancellation transactions.
+var (
+	ErrNotTicketPurchase = errors.New("purchase is not an event ticket")
+	ErrAlreadyCancelled  = errors.New("already cancelled")
+)
+
+// CancellationPolicy returns the venue's cancellation policy.
+func (venue Venues) CancellationPolicy() util.CancellationPolicy {
+	return util.CancellationPolicy{
+		FullRefundHours:      venue.FullRefundHours,
+		NoRefundHours:        venue.NoRefundHours,
+		PartialRefundPercent: venue.PartialRefundPercent,
+	}
+}
+
+// CancellationPolicy returns the practitioner's cancellation policy.
+func (practitioner Practitioners) CancellationPolicy() util.CancellationPolicy {
+	return util.CancellationPolicy{
+		FullRefundHours:      practitioner.FullRefundHours,
+		NoRefundHours:        practitioner.NoRefundHours,
+		PartialRefundPercent: practitioner.PartialRefundPercent,
+	}
+}
+
+// CancellationPolicy returns the event's cancellation policy for its tickets.
+func (event Events) CancellationPolicy() util.CancellationPolicy {
+	return util.CancellationPolicy{
+		FullRefundHours:      event.FullRefundHours,
+		NoRefundHours:        event.NoRefundHours,
+		PartialRefundPercent: event.PartialRefundPercent,
+	}
+}
+
+// StartsAt returns when the event starts: its start time if set, otherwise
+// the beginning of its first day.
+func (event Events) StartsAt() time.Time {
+	if event.StartTime.Valid {
+		return event.StartTime.Time
+	}
+	return event.StartDate
+}
+
+// CancelTxParams contains the input parameters of the cancellation transactions.
+type CancelTxParams struct {
+	ID          uuid.UUID     `json:"id"` // the booking, ticket purchase or event to cancel
+	CancelledBy uuid.UUID     `json:"cancelled_by"`
+	Reason      string        `json:"reason"`
+	FullRefund  bool          `json:"full_refund"` // ignore the policy, for cancellations by the seller
+	Now         time.Time     `json:"now"`
+	HoldFor     time.Duration `json:"-"` // how long the freed place is held for a promoted profile
+}
+
+// CancelTxResult is the result of a cancellation. The cancellation records
+// the refund owed, which is still pending until issued through the payment
+// provider. When a waitlisted profile was promoted into the freed place,
+// Promoted and Notification are set and the place is held for it until
+// Promoted.HoldUntil.
+type CancelTxResult struct {
+	VenueBooking        *BookedVenues        `json:"venue_booking,omitempty"`
+	PractitionerBooking *BookedPractitioners `json:"practitioner_booking,omitempty"`
+	Purchase            Purchases            `json:"purchase"`
+	Cancellation        Cancellations        `json:"cancellation"`
+	Promoted            *WaitlistEntries     `json:"promoted,omitempty"`
+	Notification        *Notifications       `json:"notification,omitempty"`
+}
+
+// CancelPurchaseTx cancels an event ticket purchase and promotes the next
+// profile waiting for the event. The event row is locked so the freed
+// place cannot be sold and promoted at the same time.
+func (store *SQLStore) CancelPurchaseTx(ctx context.Context, arg CancelTxParams) (CancelTxResult, error) {
+	var result CancelTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		purchase, err := q.GetPurchase(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if !purchase.EventID.Valid {
+			return ErrNotTicketPurchase
+		}
+
+		event, err := q.GetEventForUpdate(ctx, purchase.EventID.UUID)
+		if err != nil {
+			return err
+		}
+
+		err = cancelAndRefund(ctx, q, arg, purchase, event.CancellationPolicy(), event.StartsAt(), &result)
+		if err != nil {
+			return err
+		}
+
+		result.Promoted, result.Notification, err = promoteForEvent(ctx, q, event, arg.Now.Add(arg.HoldFor))
+		return err
+	})
+
+	return result, err
+}
+
+// CancelVenueBookingTx cancels a venue booking together with its purchase
+// and promotes the next profile waiting for that venue day. The venue row
+// is locked so the freed day cannot be booked and promoted at the same time.
+func (store *SQLStore) CancelVenueBookingTx(ctx context.Context, arg CancelTxParams) (CancelTxResult, error) {
+	var result CancelTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		booking, err := q.GetBookedVenue(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		venue, err := q.GetVenueForUpdate(ctx, booking.VenueID)
+		if err != nil {
+			return err
+		}
+
+		booking, err = q.CancelBookedVenue(ctx, booking.ID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrAlreadyCancelled
+			}
+			return err
+		}
+		result.VenueBooking = &booking
+
+		purchase, err := q.GetPurchaseByBookedVenue(ctx, uuid.NullUUID{UUID: booking.ID, Valid: true})
+		if err != nil {
+			return err
+		}
+
+		err = cancelAndRefund(ctx, q, arg, purchase, venue.CancellationPolicy(), booking.BookedFor, &result)
+		if err != nil {
+			return err
+		}
+
+		result.Promoted, result.Notification, err = promoteForVenueDay(ctx, q, venue, booking.BookedFor, arg.Now.Add(arg.HoldFor))
+		return err
+	})
+
+	return result, err
+}
+
+// CancelPractitionerBookingTx cancels a practitioner appointment together
+// with its purchase. The practitioner row is locked so the freed slot is not
+// booked while the cancellation is in progress.
+func (store *SQLStore) CancelPractitionerBookingTx(ctx context.Context, arg CancelTxParams) (CancelTxResult, error) {
+	var result CancelTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		booking, err := q.GetBookedPractitioner(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		practitioner, err := q.GetPractitionerForUpdate(ctx, booking.ServiceID)
+		if err != nil {
+			return err
+		}
+
+		booking, err = q.CancelBookedPractitioner(ctx, booking.ID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrAlreadyCancelled
+			}
+			return err
+		}
+		result.PractitionerBooking = &booking
+
+		purchase, err := q.GetPurchaseByBookedPractitioner(ctx, uuid.NullUUID{UUID: booking.ID, Valid: true})
+		if err != nil {
+			return err
+		}
+
+		return cancelAndRefund(ctx, q, arg, purchase, practitioner.CancellationPolicy(), booking.BookedFor, &result)
+	})
+
+	return result, err
+}
+
+// cancelAndRefund cancels a purchase of something starting at start and
+// records the cancellation with the refund the policy gives. Only money
+// taken through the payment provider is refunded.
+func cancelAndRefund(ctx context.Context, q *Queries, arg CancelTxParams, purchase Purchases, policy util.CancellationPolicy, start time.Time, result *CancelTxResult) error {
+	var err error
+	result.Purchase, err = q.CancelPurchase(ctx, purchase.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrAlreadyCancelled
+		}
+		return err
+	}
+
+	notice := start.Sub(arg.Now)
+	var refund int64
+	if util.PurchaseStatus(purchase.Status) == util.PurchasePaid && purchase.ProviderPaymentID.Valid {
+		refund = int64(purchase.Amount.Int32)
+		if !arg.FullRefund {
+			refund = policy.Refund(refund, notice)
+		}
+	}
+
+	refundStatus := util.RefundNone
+	if refund > 0 {
+		refundStatus = util.RefundPending
+	}
+
+	result.Cancellation, err = q.CreateCancellation(ctx, CreateCancellationParams{
+		PurchaseID:           purchase.ID,
+		BookedVenueID:        purchase.BookedVenueID,
+		BookedPractitionerID: purchase.BookedPractitionerID,
+		CancelledBy:          arg.CancelledBy,
+		Reason:               sql.NullString{String: arg.Reason, Valid: arg.Reason != ""},
+		NoticeHours:          int32(notice / time.Hour),
+		RefundAmount:         int32(refund),
+		Currency:             purchase.Currency,
+		RefundStatus:         string(refundStatus),
+	})
+	return err
+}
+
+// promote marks a waitlist entry as promoted, holding the place it waited
+// for until holdUntil, and notifies its profile. PurchaseTicketsTx and
+// BookVenueTx keep held places for the promoted profile.
+func promote(ctx context.Context, q *Queries, entry WaitlistEntries, message string, holdUntil time.Time) (*WaitlistEntries, *Notifications, error) {
+	promoted, err := q.PromoteWaitlistEntry(ctx, PromoteWaitlistEntryParams{
+		ID:        entry.ID,
+		HoldUntil: sql.NullTime{Time: holdUntil, Valid: true},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	notification, err := q.CreateNotification(ctx, CreateNotificationParams{
+		ProfileID:       promoted.ProfileID,
+		Kind:            NotificationWaitlistPromoted,
+		Message:         message,
+		WaitlistEntryID: uuid.NullUUID{UUID: promoted.ID, Valid: true},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &promoted, &notification, nil
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// Errors returned by the event transactions.
+var (
+	// ErrCapacityBelowSold is returned by UpdateEventTx when the new participant
+	// limit is lower than the number of tickets already sold.
+	ErrCapacityBelowSold = errors.New("total_particpant is below the number of tickets already sold")
+	// ErrEventNotCancellable is returned by CancelEventTx for an event that
+	// is already cancelled or completed.
+	ErrEventNotCancellable = errors.New("event cannot be cancelled")
+)
+
+// UpdateEventTx replaces the details of an event. The event row is locked
+// while the tickets sold are counted, so a sale cannot slip in between the
+// check and the update and leave more tickets sold than places.
+func (store *SQLStore) UpdateEventTx(ctx context.Context, arg UpdateEventParams) (Events, error) {
+	var event Events
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		_, err := q.GetEventForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if arg.TotalParticpant.Valid {
+			sold, err := q.CountTicketsByEvent(ctx, uuid.NullUUID{UUID: arg.ID, Valid: true})
+			if err != nil {
+				return err
+			}
+			if sold > int64(arg.TotalParticpant.Int32) {
+				return ErrCapacityBelowSold
+			}
+		}
+
+		event, err = q.UpdateEvent(ctx, arg)
+		return err
+	})
+
+	return event, err
+}
+
+// CancelEventTxResult is the result of cancelling an event: the cancelled
+// event and the cancellation of every ticket purchase it still had.
+type CancelEventTxResult struct {
+	Event         Events           `json:"event"`
+	Cancellations []CancelTxResult `json:"cancellations"`
+}
+
+// CancelEventTx cancels an event together with its pending and paid ticket
+// purchases. Buyers are owed a full refund, recorded as pending on each
+// cancellation until issued through the payment provider. The event row is
+// locked so no ticket can be sold while the event is being cancelled.
+func (store *SQLStore) CancelEventTx(ctx context.Context, arg CancelTxParams) (CancelEventTxResult, error) {
+	var result CancelEventTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		event, err := q.GetEventForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if !util.EventStatus(event.Status).CanTransition(util.EventCancelled) {
+			return ErrEventNotCancellable
+		}
+
+		purchases, err := q.ListLivePurchasesByEventForUpdate(ctx, uuid.NullUUID{UUID: event.ID, Valid: true})
+		if err != nil {
+			return err
+		}
+
+		// The seller is calling the event off, so the policy does not apply
+		arg.FullRefund = true
+		result.Cancellations = make([]CancelTxResult, 0, len(purchases))
+		for _, purchase := range purchases {
+			var cancellation CancelTxResult
+			err = cancelAndRefund(ctx, q, arg, purchase, event.CancellationPolicy(), event.StartsAt(), &cancellation)
+			if err != nil {
+				return err
+			}
+			result.Cancellations = append(result.Cancellations, cancellation)
+		}
+
+		result.Event, err = q.UpdateEventStatus(ctx, UpdateEventStatusParams{
+			ID:     event.ID,
+			Status: string(util.EventCancelled),
+		})
+		return err
+	})
+
+	return result, err
+}
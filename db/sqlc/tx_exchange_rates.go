@@ -0,0 +1,16 @@
+package db
+
+import "context"
+
+// LoadExchangeRatesTx inserts or replaces a batch of exchange rates, so that
+// a partially loaded file never leaves a mix of old and new rates.
+func (store *SQLStore) LoadExchangeRatesTx(ctx context.Context, rates []UpsertExchangeRateParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		for _, rate := range rates {
+			if _, err := q.UpsertExchangeRate(ctx, rate); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,248 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// Errors returned when issuing invoices.
+var (
+	ErrPurchaseNotPaid = errors.New("purchase has not been paid")
+	ErrNoSeller        = errors.New("purchase has no seller to invoice it")
+)
+
+// InvoiceTxResult is the result of the issue invoice transaction.
+type InvoiceTxResult struct {
+	Purchase Purchases      `json:"purchase"`
+	Invoice  Invoices       `json:"invoice"`
+	Lines    []InvoiceLines `json:"lines"`
+}
+
+// IssueInvoiceTx returns the invoice of a paid purchase, issuing it with the
+// seller's next invoice number if it has not been issued yet.
+func (store *SQLStore) IssueInvoiceTx(ctx context.Context, purchaseID uuid.UUID) (InvoiceTxResult, error) {
+	var result InvoiceTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// Locking the purchase stops two requests issuing its invoice at once
+		result.Purchase, err = q.GetPurchaseForUpdate(ctx, purchaseID)
+		if err != nil {
+			return err
+		}
+
+		result.Invoice, err = q.GetInvoiceByPurchase(ctx, purchaseID)
+		if err == nil {
+			result.Lines, err = q.ListInvoiceLines(ctx, result.Invoice.ID)
+			return err
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if !result.Purchase.PaidAt.Valid {
+			return ErrPurchaseNotPaid
+		}
+
+		result.Invoice, result.Lines, err = issueInvoice(ctx, q, result.Purchase)
+		return err
+	})
+
+	return result, err
+}
+
+// invoicePaidPurchase issues the invoice of a purchase that was just paid.
+// Purchases without a seller are not invoiced.
+func invoicePaidPurchase(ctx context.Context, q *Queries, purchase Purchases) error {
+	if util.PurchaseStatus(purchase.Status) != util.PurchasePaid {
+		return nil
+	}
+
+	_, _, err := issueInvoice(ctx, q, purchase)
+	if errors.Is(err, ErrNoSeller) {
+		return nil
+	}
+	return err
+}
+
+// issueInvoice issues an invoice for a purchase under the seller's next
+// invoice number, with the net price as its item and each tax charged on
+// the purchase as a line of its own. The number is taken inside the
+// caller's transaction, so a rolled back invoice gives its number back.
+func issueInvoice(ctx context.Context, q *Queries, purchase Purchases) (Invoices, []InvoiceLines, error) {
+	sellerID, description, err := describePurchase(ctx, q, purchase)
+	if err != nil {
+		return Invoices{}, nil, err
+	}
+	if !sellerID.Valid {
+		return Invoices{}, nil, ErrNoSeller
+	}
+
+	seller, err := q.GetProfile(ctx, sellerID.UUID)
+	if err != nil {
+		return Invoices{}, nil, err
+	}
+	sellerName, err := profileName(ctx, q, seller)
+	if err != nil {
+		return Invoices{}, nil, err
+	}
+
+	taxes, err := q.ListPurchaseTaxes(ctx, purchase.ID)
+	if err != nil {
+		return Invoices{}, nil, err
+	}
+	net := purchase.Amount.Int32 - purchase.TaxAmount
+
+	arg := CreateInvoiceParams{
+		PurchaseID:    purchase.ID,
+		SellerID:      seller.ID,
+		BuyerID:       purchase.PurchasedBy,
+		SellerName:    sellerName,
+		SellerAddress: seller.Address,
+		SellerCountry: seller.Country,
+		BuyerName:     "Unknown buyer",
+		Currency:      purchase.Currency,
+		Subtotal:      net,
+		TaxAmount:     purchase.TaxAmount,
+		Total:         purchase.Amount.Int32,
+	}
+
+	if purchase.PurchasedBy.Valid {
+		buyer, err := q.GetProfile(ctx, purchase.PurchasedBy.UUID)
+		if err != nil {
+			return Invoices{}, nil, err
+		}
+		arg.BuyerName, err = profileName(ctx, q, buyer)
+		if err != nil {
+			return Invoices{}, nil, err
+		}
+		arg.BuyerAddress = buyer.Address
+		arg.BuyerCountry = buyer.Country
+	}
+
+	arg.Sequence, err = q.NextInvoiceNumber(ctx, seller.ID)
+	if err != nil {
+		return Invoices{}, nil, err
+	}
+	arg.Number = util.InvoiceNumber(seller.BusinessName.String, arg.Sequence)
+
+	invoice, err := q.CreateInvoice(ctx, arg)
+	if err != nil {
+		return Invoices{}, nil, err
+	}
+
+	lines := []CreateInvoiceLineParams{{
+		Kind:        string(util.InvoiceLineItem),
+		Description: description,
+		Quantity:    1,
+		UnitAmount:  net,
+		Amount:      net,
+	}}
+	for _, purchaseTax := range taxes {
+		rate := formatPercent(purchaseTax.Rate)
+		lines = append(lines, CreateInvoiceLineParams{
+			Kind:        string(util.InvoiceLineTax),
+			Description: fmt.Sprintf("%s %s%%", purchaseTax.Name, rate),
+			Quantity:    1,
+			UnitAmount:  purchaseTax.Amount,
+			Amount:      purchaseTax.Amount,
+			TaxRate:     sql.NullString{String: purchaseTax.Rate, Valid: true},
+		})
+	}
+
+	invoiceLines := make([]InvoiceLines, 0, len(lines))
+	for i, line := range lines {
+		line.InvoiceID = invoice.ID
+		line.Position = int32(i + 1)
+		invoiceLine, err := q.CreateInvoiceLine(ctx, line)
+		if err != nil {
+			return Invoices{}, nil, err
+		}
+		invoiceLines = append(invoiceLines, invoiceLine)
+	}
+
+	return invoice, invoiceLines, nil
+}
+
+// formatPercent trims the trailing zeros of a numeric percentage read from
+// the database, such as "20.0000".
+func formatPercent(rate string) string {
+	if !strings.Contains(rate, ".") {
+		return rate
+	}
+	return strings.TrimSuffix(strings.TrimRight(rate, "0"), ".")
+}
+
+// describePurchase returns the profile selling what a purchase paid for and
+// a description of it for the invoice.
+func describePurchase(ctx context.Context, q *Queries, purchase Purchases) (uuid.NullUUID, string, error) {
+	switch {
+	case purchase.BookedVenueID.Valid:
+		booking, err := q.GetBookedVenue(ctx, purchase.BookedVenueID.UUID)
+		if err != nil {
+			return uuid.NullUUID{}, "", err
+		}
+		venue, err := q.GetVenue(ctx, booking.VenueID)
+		if err != nil {
+			return uuid.NullUUID{}, "", err
+		}
+		return venue.OwnedBy, fmt.Sprintf("Booking of %s on %s", venue.Name, booking.BookedFor.Format("2 Jan 2006")), nil
+
+	case purchase.BookedPractitionerID.Valid:
+		appointment, err := q.GetBookedPractitioner(ctx, purchase.BookedPractitionerID.UUID)
+		if err != nil {
+			return uuid.NullUUID{}, "", err
+		}
+		practitioner, err := q.GetPractitioner(ctx, appointment.ServiceID)
+		if err != nil {
+			return uuid.NullUUID{}, "", err
+		}
+		return practitioner.CreatedBy, fmt.Sprintf("Appointment with %s on %s", practitioner.Name, appointment.BookedFor.Format("2 Jan 2006 15:04")), nil
+
+	case purchase.EventID.Valid:
+		event, err := q.GetEvent(ctx, purchase.EventID.UUID)
+		if err != nil {
+			return uuid.NullUUID{}, "", err
+		}
+		ticket, err := q.GetTicketByPurchase(ctx, purchase.ID)
+		if err != nil {
+			return uuid.NullUUID{}, "", err
+		}
+		tier, err := q.GetTicketTier(ctx, ticket.TierID)
+		if err != nil {
+			return uuid.NullUUID{}, "", err
+		}
+		return event.CreatedBy, fmt.Sprintf("%s ticket for %s (%s)", tier.Name, event.Name.String, ticket.Code), nil
+
+	default:
+		return uuid.NullUUID{}, "", ErrNoSeller
+	}
+}
+
+// profileName returns the name a profile is invoiced under: its business
+// name, or else the name of the user owning it.
+func profileName(ctx context.Context, q *Queries, profile Profiles) (string, error) {
+	if name := strings.TrimSpace(profile.BusinessName.String); name != "" {
+		return name, nil
+	}
+
+	owner, err := q.GetProfileOwner(ctx, profile.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return profile.ID.String(), nil
+		}
+		return "", err
+	}
+
+	if name := strings.TrimSpace(owner.Firstname.String + " " + owner.Lastname.String); name != "" {
+		return name, nil
+	}
+	return owner.Email, nil
+}
@@ -0,0 +1,192 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// initialPurchaseStatus returns the status a new purchase starts in. Free
+// purchases have nothing to pay and are paid straight away.
+func initialPurchaseStatus(amount sql.NullInt32) util.PurchaseStatus {
+	if amount.Valid && amount.Int32 > 0 {
+		return util.PurchasePending
+	}
+	return util.PurchasePaid
+}
+
+// pendingUntil returns when a purchase in the given status expires if it is
+// still unpaid. Only pending purchases expire.
+func pendingUntil(status util.PurchaseStatus, ttl time.Duration) sql.NullTime {
+	if status != util.PurchasePending {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: time.Now().Add(ttl), Valid: true}
+}
+
+// ExpirePendingPurchasesTx fails every pending purchase whose checkout has
+// expired and gives the places of their bookings back, promoting the next
+// waiting profile into each. Freed places are held for holdFor.
+func (store *SQLStore) ExpirePendingPurchasesTx(ctx context.Context, holdFor time.Duration) ([]Purchases, error) {
+	var expired []Purchases
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		expired, err = expirePendingPurchases(ctx, q, newPlaceLocks(), FailExpiredPurchasesParams{}, holdFor)
+		return err
+	})
+
+	return expired, err
+}
+
+// expirePendingPurchases fails the expired pending purchases matching arg
+// and releases their places, so abandoned checkouts stop holding them. The
+// events and venues of the purchases are locked before the purchases.
+func expirePendingPurchases(ctx context.Context, q *Queries, locks *placeLocks, arg FailExpiredPurchasesParams, holdFor time.Duration) ([]Purchases, error) {
+	pending, err := q.ListExpiredPurchases(ctx, ListExpiredPurchasesParams(arg))
+	if err != nil || len(pending) == 0 {
+		return nil, err
+	}
+	if err := locks.lockPurchases(ctx, q, pending); err != nil {
+		return nil, err
+	}
+
+	expired, err := q.FailExpiredPurchases(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, purchase := range expired {
+		if err := releasePlace(ctx, q, locks, purchase, holdFor); err != nil {
+			return nil, err
+		}
+	}
+	return expired, nil
+}
+
+// ApplyPaymentEventTxParams contains the input parameters of the apply payment event transaction.
+type ApplyPaymentEventTxParams struct {
+	Provider   string              `json:"provider"`
+	EventID    string              `json:"event_id"`
+	Type       string              `json:"type"`
+	CheckoutID string              `json:"checkout_id"`
+	PaymentID  string              `json:"payment_id"`
+	Status     util.PurchaseStatus `json:"status"`
+	HoldFor    time.Duration       `json:"-"` // how long a place freed by a failed payment is held for a promoted profile
+}
+
+// ApplyPaymentEventTxResult is the result of the apply payment event transaction.
+type ApplyPaymentEventTxResult struct {
+	Duplicate bool        `json:"duplicate"`
+	Purchases []Purchases `json:"purchases"`
+	// Paid reports whether any purchase of the checkout is paid, including
+	// on a redelivered event, so the caller knows whether to capture the
+	// payment or void it.
+	Paid bool `json:"paid"`
+}
+
+// ApplyPaymentEventTx settles the pending purchases of a checkout as paid
+// or failed, invoicing paid ones and releasing the places of failed ones to
+// the next waiting profiles.
+// Purchases that expired or were cancelled before the event arrived are
+// left alone. Each provider event is recorded, and an event that was
+// already applied changes nothing, so webhooks may safely be delivered
+// again.
+func (store *SQLStore) ApplyPaymentEventTx(ctx context.Context, arg ApplyPaymentEventTxParams) (ApplyPaymentEventTxResult, error) {
+	var result ApplyPaymentEventTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		rows, err := q.RecordPaymentEvent(ctx, RecordPaymentEventParams{
+			Provider:   arg.Provider,
+			EventID:    arg.EventID,
+			Type:       arg.Type,
+			CheckoutID: arg.CheckoutID,
+		})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			result.Duplicate = true
+			return checkoutPaid(ctx, q, arg, &result)
+		}
+
+		// A failed payment gives its places back, so lock them before the
+		// purchases, in the same order as sales and bookings do
+		locks := newPlaceLocks()
+		if arg.Status == util.PurchaseFailed {
+			purchases, err := q.ListCheckoutPurchases(ctx, ListCheckoutPurchasesParams{
+				Provider:           sql.NullString{String: arg.Provider, Valid: true},
+				ProviderCheckoutID: sql.NullString{String: arg.CheckoutID, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+			if err := locks.lockPurchases(ctx, q, purchases); err != nil {
+				return err
+			}
+		}
+
+		result.Purchases, err = q.SettleCheckoutPurchases(ctx, SettleCheckoutPurchasesParams{
+			Status:             string(arg.Status),
+			ProviderPaymentID:  sql.NullString{String: arg.PaymentID, Valid: arg.PaymentID != ""},
+			Provider:           arg.Provider,
+			ProviderCheckoutID: arg.CheckoutID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.Status == util.PurchasePaid {
+			for _, purchase := range result.Purchases {
+				if err := invoicePaidPurchase(ctx, q, purchase); err != nil {
+					return err
+				}
+			}
+			return checkoutPaid(ctx, q, arg, &result)
+		}
+		if arg.Status != util.PurchaseFailed {
+			return nil
+		}
+
+		// Unpaid purchases give their place back to the waitlist
+		for _, purchase := range result.Purchases {
+			if err := releasePlace(ctx, q, locks, purchase, arg.HoldFor); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// checkoutPaid sets result.Paid from the purchases of the event's checkout.
+func checkoutPaid(ctx context.Context, q *Queries, arg ApplyPaymentEventTxParams, result *ApplyPaymentEventTxResult) error {
+	paid, err := q.CountPaidCheckoutPurchases(ctx, CountPaidCheckoutPurchasesParams{
+		Provider:           sql.NullString{String: arg.Provider, Valid: true},
+		ProviderCheckoutID: sql.NullString{String: arg.CheckoutID, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	result.Paid = paid > 0
+	return nil
+}
+
+// releaseBooking cancels the venue or practitioner booking paid for by a
+// purchase, if it is still confirmed.
+func releaseBooking(ctx context.Context, q *Queries, purchase Purchases) error {
+	var err error
+	switch {
+	case purchase.BookedVenueID.Valid:
+		_, err = q.CancelBookedVenue(ctx, purchase.BookedVenueID.UUID)
+	case purchase.BookedPractitionerID.Valid:
+		_, err = q.CancelBookedPractitioner(ctx, purchase.BookedPractitionerID.UUID)
+	}
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// ErrLastOwner is returned for a change that would leave a profile without
+// an owner.
+var ErrLastOwner = errors.New("a profile must keep at least one owner")
+
+// CreateProfileTxParams contains the input parameters of the create profile transaction.
+type CreateProfileTxParams struct {
+	CreateProfileParams
+	OwnerID uuid.UUID `json:"owner_id"`
+}
+
+// CreateProfileTxResult is the result of the create profile transaction.
+type CreateProfileTxResult struct {
+	Profile Profiles      `json:"profile"`
+	Member  ProfilesUsers `json:"member"`
+}
+
+// CreateProfileTx creates a profile and links the creating user to it as its owner.
+func (store *SQLStore) CreateProfileTx(ctx context.Context, arg CreateProfileTxParams) (CreateProfileTxResult, error) {
+	var result CreateProfileTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Profile, err = q.CreateProfile(ctx, arg.CreateProfileParams)
+		if err != nil {
+			return err
+		}
+
+		result.Member, err = q.LinkUserToProfile(ctx, LinkUserToProfileParams{
+			ProfilesID: result.Profile.ID,
+			UsersID:    arg.OwnerID,
+			MemberRole: string(util.MemberOwner),
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// DeleteProfileTx unlinks every member of a profile and deletes it.
+func (store *SQLStore) DeleteProfileTx(ctx context.Context, profileID uuid.UUID) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		err := q.UnlinkAllUsersFromProfile(ctx, profileID)
+		if err != nil {
+			return err
+		}
+
+		return q.DeleteProfile(ctx, profileID)
+	})
+}
+
+// checkNotLastOwner locks the owner memberships of a profile and refuses to
+// demote or unlink the user if they are its last owner. Concurrent changes
+// to the owners wait for the lock, so they see each other's result.
+func checkNotLastOwner(ctx context.Context, q *Queries, profileID, userID uuid.UUID) error {
+	owners, err := q.ListProfileOwnersForUpdate(ctx, profileID)
+	if err != nil {
+		return err
+	}
+	if len(owners) == 1 && owners[0].UsersID == userID {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// UpdateProfileMemberRoleTx changes a member's role, refusing to demote the
+// last owner of the profile.
+func (store *SQLStore) UpdateProfileMemberRoleTx(ctx context.Context, arg UpdateProfileMemberRoleParams) (ProfilesUsers, error) {
+	var member ProfilesUsers
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if util.MemberRole(arg.MemberRole) != util.MemberOwner {
+			if err := checkNotLastOwner(ctx, q, arg.ProfilesID, arg.UsersID); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		member, err = q.UpdateProfileMemberRole(ctx, arg)
+		return err
+	})
+
+	return member, err
+}
+
+// RemoveProfileMemberTx unlinks a user from a profile, refusing to unlink its
+// last owner. It returns sql.ErrNoRows if the user is not a member.
+func (store *SQLStore) RemoveProfileMemberTx(ctx context.Context, arg UnlinkUserFromProfileParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if err := checkNotLastOwner(ctx, q, arg.ProfilesID, arg.UsersID); err != nil {
+			return err
+		}
+
+		rows, err := q.UnlinkUserFromProfile(ctx, arg)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
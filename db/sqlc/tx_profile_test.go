@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// addTestOwner links a new user to the profile as an owner.
+func addTestOwner(t *testing.T, profile Profiles) uuid.UUID {
+	ctx := context.Background()
+
+	user, err := testStore.CreateUser(ctx, CreateUserParams{
+		Email: fmt.Sprintf("%s@example.com", uuid.NewString()),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = testStore.LinkUserToProfile(ctx, LinkUserToProfileParams{
+		ProfilesID: profile.ID,
+		UsersID:    user.ID,
+		MemberRole: string(util.MemberOwner),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user.ID
+}
+
+func TestProfileKeepsAnOwner(t *testing.T) {
+	requireDB(t)
+	ctx := context.Background()
+
+	testCases := []struct {
+		name   string
+		change func(profile Profiles, userID uuid.UUID) error
+	}{
+		{
+			name: "demote",
+			change: func(profile Profiles, userID uuid.UUID) error {
+				_, err := testStore.UpdateProfileMemberRoleTx(ctx, UpdateProfileMemberRoleParams{
+					ProfilesID: profile.ID,
+					UsersID:    userID,
+					MemberRole: string(util.MemberStaff),
+				})
+				return err
+			},
+		},
+		{
+			name: "remove",
+			change: func(profile Profiles, userID uuid.UUID) error {
+				return testStore.RemoveProfileMemberTx(ctx, UnlinkUserFromProfileParams{
+					ProfilesID: profile.ID,
+					UsersID:    userID,
+				})
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			profile := createTestProfile(t)
+			owners := []uuid.UUID{addTestOwner(t, profile), addTestOwner(t, profile)}
+
+			// Both owners step down at once, only one of them may succeed
+			errs := make(chan error, len(owners))
+			for _, userID := range owners {
+				go func(userID uuid.UUID) {
+					errs <- tc.change(profile, userID)
+				}(userID)
+			}
+
+			var succeeded, refused int
+			for range owners {
+				err := <-errs
+				switch {
+				case err == nil:
+					succeeded++
+				case errors.Is(err, ErrLastOwner):
+					refused++
+				default:
+					t.Fatal(err)
+				}
+			}
+			if succeeded != 1 || refused != 1 {
+				t.Fatalf("succeeded = %d, refused = %d, want 1 and 1", succeeded, refused)
+			}
+
+			left, err := testStore.ListProfileOwnersForUpdate(ctx, profile.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(left) != 1 {
+				t.Fatalf("profile has %d owners, want 1", len(left))
+			}
+		})
+	}
+}
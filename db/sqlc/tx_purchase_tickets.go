@@ -0,0 +1,170 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tedobanks/tabularasa_backend/tax"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// ticketCodeBytes gives 16 character ticket codes.
+const ticketCodeBytes = 10
+
+// Errors returned by PurchaseTicketsTx when a sale is rejected.
+var (
+	ErrEventNotOnSale = errors.New("event is not on sale")
+	ErrTierNotInEvent = errors.New("ticket tier does not belong to the event")
+	ErrEventSoldOut   = errors.New("not enough places left for the event")
+	ErrTierSoldOut    = errors.New("not enough tickets left in the tier")
+)
+
+// PurchaseTicketsTxParams contains the input parameters of the purchase tickets transaction.
+type PurchaseTicketsTxParams struct {
+	EventID     uuid.UUID     `json:"event_id"`
+	TierID      uuid.UUID     `json:"tier_id"`
+	PurchasedBy uuid.UUID     `json:"purchased_by"`
+	Quantity    int           `json:"quantity"`
+	TaxRules    *tax.Rules    `json:"-"`
+	PendingTTL  time.Duration `json:"-"` // how long unpaid tickets hold their places
+	HoldFor     time.Duration `json:"-"` // how long a place freed for a waitlisted profile is held
+}
+
+// PurchasedTicket is a ticket together with the purchase that paid for it.
+type PurchasedTicket struct {
+	Ticket   Tickets         `json:"ticket"`
+	Purchase Purchases       `json:"purchase"`
+	Taxes    []PurchaseTaxes `json:"taxes"`
+}
+
+// PurchaseTicketsTxResult is the result of the purchase tickets transaction.
+type PurchaseTicketsTxResult struct {
+	Event   Events            `json:"event"`
+	Tier    TicketTiers       `json:"tier"`
+	Tickets []PurchasedTicket `json:"tickets"`
+}
+
+// PurchaseTicketsTx sells tickets of one tier of a published event. Each
+// ticket is recorded as its own purchase, so the number of purchases of an
+// event is the number of places taken, which together with the places held
+// for promoted waitlist entries must stay within events.total_particpant,
+// and is taxed under arg.TaxRules. The event row is
+// locked for the duration of the transaction so concurrent sales for the
+// same event are serialised.
+func (store *SQLStore) PurchaseTicketsTx(ctx context.Context, arg PurchaseTicketsTxParams) (PurchaseTicketsTxResult, error) {
+	var result PurchaseTicketsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Event, err = q.GetEventForUpdate(ctx, arg.EventID)
+		if err != nil {
+			return err
+		}
+		if util.EventStatus(result.Event.Status) != util.EventPublished {
+			return ErrEventNotOnSale
+		}
+
+		result.Tier, err = q.GetTicketTier(ctx, arg.TierID)
+		if err != nil {
+			return err
+		}
+		if result.Tier.EventID != arg.EventID {
+			return ErrTierNotInEvent
+		}
+
+		// Tickets whose purchase expired unpaid no longer take a place, and
+		// lapsed holds stop holding one; both go to the waitlist first
+		locks := newPlaceLocks()
+		locks.events[arg.EventID] = result.Event
+		_, err = expirePendingPurchases(ctx, q, locks, FailExpiredPurchasesParams{
+			EventID: uuid.NullUUID{UUID: arg.EventID, Valid: true},
+		}, arg.HoldFor)
+		if err != nil {
+			return err
+		}
+		_, err = expireLapsedHolds(ctx, q, locks, ListLapsedHoldsParams{
+			EventID: uuid.NullUUID{UUID: arg.EventID, Valid: true},
+		}, arg.HoldFor)
+		if err != nil {
+			return err
+		}
+
+		// A promoted profile buys the place held for it, while the places
+		// held for other profiles stay taken
+		_, err = q.ClaimEventHold(ctx, ClaimEventHoldParams{
+			EventID:   uuid.NullUUID{UUID: arg.EventID, Valid: true},
+			ProfileID: arg.PurchasedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		if result.Event.TotalParticpant.Valid {
+			taken, err := q.CountPurchasesByEvent(ctx, uuid.NullUUID{UUID: arg.EventID, Valid: true})
+			if err != nil {
+				return err
+			}
+			held, err := q.CountEventHolds(ctx, uuid.NullUUID{UUID: arg.EventID, Valid: true})
+			if err != nil {
+				return err
+			}
+			if taken+held+int64(arg.Quantity) > int64(result.Event.TotalParticpant.Int32) {
+				return ErrEventSoldOut
+			}
+		}
+
+		sold, err := q.CountTicketsByTier(ctx, arg.TierID)
+		if err != nil {
+			return err
+		}
+		if sold+int64(arg.Quantity) > int64(result.Tier.Quantity) {
+			return ErrTierSoldOut
+		}
+
+		price := sql.NullInt32{Int32: result.Tier.Price, Valid: true}
+		status := initialPurchaseStatus(price)
+		for i := 0; i < arg.Quantity; i++ {
+			var ticket PurchasedTicket
+
+			supply := tax.Supply{Service: "ticket"}
+			ticket.Purchase, ticket.Taxes, err = createTaxedPurchase(ctx, q, arg.TaxRules, result.Event.CreatedBy, supply, CreatePurchaseParams{
+				EventID:      uuid.NullUUID{UUID: arg.EventID, Valid: true},
+				PurchasedBy:  uuid.NullUUID{UUID: arg.PurchasedBy, Valid: true},
+				Amount:       price,
+				Currency:     result.Tier.Currency,
+				Status:       string(status),
+				PendingUntil: pendingUntil(status, arg.PendingTTL),
+			})
+			if err != nil {
+				return err
+			}
+
+			code, err := util.NewCode(ticketCodeBytes)
+			if err != nil {
+				return err
+			}
+
+			ticket.Ticket, err = q.CreateTicket(ctx, CreateTicketParams{
+				PurchaseID: ticket.Purchase.ID,
+				TierID:     arg.TierID,
+				Code:       code,
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := invoicePaidPurchase(ctx, q, ticket.Purchase); err != nil {
+				return err
+			}
+
+			result.Tickets = append(result.Tickets, ticket)
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tedobanks/tabularasa_backend/money"
+	"github.com/tedobanks/tabularasa_backend/tax"
+)
+
+// createTaxedPurchase creates a purchase of a supply sold by the seller
+// profile, adding the taxes due on arg.Amount under the rules, and records
+// each tax charged. Sellers are taxed in the country of their profile;
+// purchases of sellers without a country are not taxed.
+func createTaxedPurchase(ctx context.Context, q *Queries, rules *tax.Rules, seller uuid.NullUUID, supply tax.Supply, arg CreatePurchaseParams) (Purchases, []PurchaseTaxes, error) {
+	var charges []tax.Charge
+
+	if rules != nil && arg.Amount.Valid && seller.Valid {
+		profile, err := q.GetProfile(ctx, seller.UUID)
+		if err != nil {
+			return Purchases{}, nil, err
+		}
+		currency, err := money.ParseCurrency(arg.Currency)
+		if err != nil {
+			return Purchases{}, nil, err
+		}
+
+		supply.Location = profile.Country.String
+		result := rules.Apply(supply, money.New(int64(arg.Amount.Int32), currency))
+
+		arg.Amount.Int32 = int32(result.Total.Amount)
+		arg.TaxAmount = int32(result.Tax().Amount)
+		charges = result.Taxes
+	}
+
+	purchase, err := q.CreatePurchase(ctx, arg)
+	if err != nil {
+		return Purchases{}, nil, err
+	}
+
+	taxes := make([]PurchaseTaxes, 0, len(charges))
+	for i, charge := range charges {
+		purchaseTax, err := q.CreatePurchaseTax(ctx, CreatePurchaseTaxParams{
+			PurchaseID: purchase.ID,
+			Position:   int32(i + 1),
+			Name:       charge.Name,
+			Rate:       charge.Rate,
+			Inclusive:  charge.Inclusive,
+			Amount:     int32(charge.Amount.Amount),
+		})
+		if err != nil {
+			return Purchases{}, nil, err
+		}
+		taxes = append(taxes, purchaseTax)
+	}
+
+	return purchase, taxes, nil
+}
@@ -0,0 +1,231 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// placeLocks holds the events and venues a transaction has locked before
+// giving their places back, so a freed place cannot be sold while it is
+// being passed to the next waiting profile.
+type placeLocks struct {
+	events map[uuid.UUID]Events
+	venues map[uuid.UUID]Venues
+}
+
+func newPlaceLocks() *placeLocks {
+	return &placeLocks{
+		events: make(map[uuid.UUID]Events),
+		venues: make(map[uuid.UUID]Venues),
+	}
+}
+
+// lock locks the given events and then the given venues, each in ID order,
+// so concurrent transactions always take the locks in the same order.
+func (locks *placeLocks) lock(ctx context.Context, q *Queries, eventIDs, venueIDs []uuid.NullUUID) error {
+	for _, id := range sortedIDs(eventIDs) {
+		if _, err := locks.event(ctx, q, id); err != nil {
+			return err
+		}
+	}
+	for _, id := range sortedIDs(venueIDs) {
+		if _, err := locks.venue(ctx, q, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockPurchases locks the events and venues the purchases are for.
+func (locks *placeLocks) lockPurchases(ctx context.Context, q *Queries, purchases []Purchases) error {
+	eventIDs := make([]uuid.NullUUID, 0, len(purchases))
+	venueIDs := make([]uuid.NullUUID, 0, len(purchases))
+	for _, purchase := range purchases {
+		eventIDs = append(eventIDs, purchase.EventID)
+		venueIDs = append(venueIDs, purchase.VenueID)
+	}
+	return locks.lock(ctx, q, eventIDs, venueIDs)
+}
+
+// event returns the locked event, locking it first if needed.
+func (locks *placeLocks) event(ctx context.Context, q *Queries, id uuid.UUID) (Events, error) {
+	if event, ok := locks.events[id]; ok {
+		return event, nil
+	}
+	event, err := q.GetEventForUpdate(ctx, id)
+	if err != nil {
+		return Events{}, err
+	}
+	locks.events[id] = event
+	return event, nil
+}
+
+// venue returns the locked venue, locking it first if needed.
+func (locks *placeLocks) venue(ctx context.Context, q *Queries, id uuid.UUID) (Venues, error) {
+	if venue, ok := locks.venues[id]; ok {
+		return venue, nil
+	}
+	venue, err := q.GetVenueForUpdate(ctx, id)
+	if err != nil {
+		return Venues{}, err
+	}
+	locks.venues[id] = venue
+	return venue, nil
+}
+
+// sortedIDs returns the distinct valid IDs in ascending order.
+func sortedIDs(ids []uuid.NullUUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	sorted := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if id.Valid && !seen[id.UUID] {
+			seen[id.UUID] = true
+			sorted = append(sorted, id.UUID)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].String() < sorted[j].String() })
+	return sorted
+}
+
+// promoteForEvent promotes the next profile waiting for a place at the
+// event into a freed place, holding it until holdUntil. It returns nil when
+// nobody is waiting or the event is no longer on sale.
+func promoteForEvent(ctx context.Context, q *Queries, event Events, holdUntil time.Time) (*WaitlistEntries, *Notifications, error) {
+	if util.EventStatus(event.Status) != util.EventPublished {
+		return nil, nil, nil
+	}
+
+	entry, err := q.NextWaitingForEvent(ctx, uuid.NullUUID{UUID: event.ID, Valid: true})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	message := fmt.Sprintf("A place has opened up for %s and is held for you until %s",
+		event.Name.String, holdUntil.Format(holdLayout))
+	return promote(ctx, q, entry, message, holdUntil)
+}
+
+// promoteForVenueDay promotes the next profile waiting for a freed venue
+// day, holding the day until holdUntil. It returns nil when nobody is
+// waiting.
+func promoteForVenueDay(ctx context.Context, q *Queries, venue Venues, day time.Time, holdUntil time.Time) (*WaitlistEntries, *Notifications, error) {
+	day = util.StartOfDay(day)
+	entry, err := q.NextWaitingForVenue(ctx, NextWaitingForVenueParams{
+		VenueID:   uuid.NullUUID{UUID: venue.ID, Valid: true},
+		BookedFor: sql.NullTime{Time: day, Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	message := fmt.Sprintf("%s is now free on %s and is held for you until %s",
+		venue.Name, day.Format("2006-01-02"), holdUntil.Format(holdLayout))
+	return promote(ctx, q, entry, message, holdUntil)
+}
+
+// releasePlace gives back the place taken by a purchase that failed or
+// expired unpaid: it cancels the purchase's booking and passes the freed
+// place to the next waiting profile. The purchase's event or venue must be
+// locked in locks.
+func releasePlace(ctx context.Context, q *Queries, locks *placeLocks, purchase Purchases, holdFor time.Duration) error {
+	holdUntil := time.Now().Add(holdFor)
+
+	switch {
+	case purchase.EventID.Valid:
+		event, err := locks.event(ctx, q, purchase.EventID.UUID)
+		if err != nil {
+			return err
+		}
+		_, _, err = promoteForEvent(ctx, q, event, holdUntil)
+		return err
+
+	case purchase.BookedVenueID.Valid:
+		booking, err := q.CancelBookedVenue(ctx, purchase.BookedVenueID.UUID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				// Already cancelled, so the day was given back then
+				return nil
+			}
+			return err
+		}
+		venue, err := locks.venue(ctx, q, booking.VenueID)
+		if err != nil {
+			return err
+		}
+		_, _, err = promoteForVenueDay(ctx, q, venue, booking.BookedFor, holdUntil)
+		return err
+
+	default:
+		return releaseBooking(ctx, q, purchase)
+	}
+}
+
+// ExpireWaitlistHoldsTx expires the holds that ended before their place was
+// bought and passes each place to the next waiting profile.
+func (store *SQLStore) ExpireWaitlistHoldsTx(ctx context.Context, holdFor time.Duration) ([]WaitlistEntries, error) {
+	var expired []WaitlistEntries
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		expired, err = expireLapsedHolds(ctx, q, newPlaceLocks(), ListLapsedHoldsParams{}, holdFor)
+		return err
+	})
+
+	return expired, err
+}
+
+// expireLapsedHolds expires the lapsed holds matching arg and promotes the
+// next waiting profile into each freed place. The events and venues of the
+// holds are locked first.
+func expireLapsedHolds(ctx context.Context, q *Queries, locks *placeLocks, arg ListLapsedHoldsParams, holdFor time.Duration) ([]WaitlistEntries, error) {
+	lapsed, err := q.ListLapsedHolds(ctx, arg)
+	if err != nil || len(lapsed) == 0 {
+		return nil, err
+	}
+
+	eventIDs := make([]uuid.NullUUID, 0, len(lapsed))
+	venueIDs := make([]uuid.NullUUID, 0, len(lapsed))
+	for _, entry := range lapsed {
+		eventIDs = append(eventIDs, entry.EventID)
+		venueIDs = append(venueIDs, entry.VenueID)
+	}
+	if err := locks.lock(ctx, q, eventIDs, venueIDs); err != nil {
+		return nil, err
+	}
+
+	holdUntil := time.Now().Add(holdFor)
+	expired := make([]WaitlistEntries, 0, len(lapsed))
+	for _, entry := range lapsed {
+		// The place may have been bought before its lock was taken
+		entry, err = q.ExpireWaitlistHold(ctx, entry.ID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+			return nil, err
+		}
+		expired = append(expired, entry)
+
+		if entry.EventID.Valid {
+			_, _, err = promoteForEvent(ctx, q, locks.events[entry.EventID.UUID], holdUntil)
+		} else {
+			_, _, err = promoteForVenueDay(ctx, q, locks.venues[entry.VenueID.UUID], entry.BookedFor.Time, holdUntil)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return expired, nil
+}
@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tedobanks/tabularasa_backend/util"
+)
+
+// lapsed is far enough in the past to have passed whatever the time zone
+// of the database session.
+const lapsed = -48 * time.Hour
+
+func createTestProfile(t *testing.T) Profiles {
+	profile, err := testStore.CreateProfile(context.Background(), CreateProfileParams{
+		ID:    uuid.New(),
+		Roles: string(util.RoleAttendee),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return profile
+}
+
+// createTestEvent creates a published event with the given number of places.
+func createTestEvent(t *testing.T, places int32) Events {
+	ctx := context.Background()
+	organiser := createTestProfile(t)
+
+	event, err := testStore.CreateEvent(ctx, CreateEventParams{
+		Name:            sql.NullString{String: "Waitlist test", Valid: true},
+		CreatedBy:       uuid.NullUUID{UUID: organiser.ID, Valid: true},
+		StartDate:       time.Now().AddDate(0, 1, 0),
+		TotalParticpant: sql.NullInt32{Int32: places, Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event, err = testStore.UpdateEventStatus(ctx, UpdateEventStatusParams{
+		ID:     event.ID,
+		Status: string(util.EventPublished),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+// joinWaitlist puts a new profile on the event's waitlist.
+func joinWaitlist(t *testing.T, event Events) WaitlistEntries {
+	entry, err := testStore.CreateWaitlistEntry(context.Background(), CreateWaitlistEntryParams{
+		EventID:   uuid.NullUUID{UUID: event.ID, Valid: true},
+		ProfileID: createTestProfile(t).ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entry
+}
+
+// requireEntryStatus checks the status of a waitlist entry and returns it.
+func requireEntryStatus(t *testing.T, id uuid.UUID, status string) WaitlistEntries {
+	t.Helper()
+
+	entry, err := testStore.GetWaitlistEntry(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Status != status {
+		t.Fatalf("entry %s status = %q, want %q", id, entry.Status, status)
+	}
+	return entry
+}
+
+func TestFreedPlacesPromoteWaitlist(t *testing.T) {
+	requireDB(t)
+	ctx := context.Background()
+	holdFor := time.Hour
+
+	event := createTestEvent(t, 1)
+	purchase, err := testStore.CreatePurchase(ctx, CreatePurchaseParams{
+		EventID:      uuid.NullUUID{UUID: event.ID, Valid: true},
+		PurchasedBy:  uuid.NullUUID{UUID: createTestProfile(t).ID, Valid: true},
+		Amount:       sql.NullInt32{Int32: 1000, Valid: true},
+		Currency:     "USD",
+		Status:       string(util.PurchasePending),
+		PendingUntil: sql.NullTime{Time: time.Now().Add(lapsed), Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := joinWaitlist(t, event)
+	second := joinWaitlist(t, event)
+
+	t.Run("expired purchase", func(t *testing.T) {
+		expired, err := testStore.ExpirePendingPurchasesTx(ctx, holdFor)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		found := false
+		for _, p := range expired {
+			found = found || p.ID == purchase.ID
+		}
+		if !found {
+			t.Fatalf("purchase %s was not expired", purchase.ID)
+		}
+
+		promoted := requireEntryStatus(t, first.ID, "promoted")
+		if !promoted.HoldUntil.Valid {
+			t.Fatal("promoted entry has no hold")
+		}
+		requireEntryStatus(t, second.ID, "waiting")
+	})
+
+	t.Run("lapsed hold", func(t *testing.T) {
+		_, err := testStore.PromoteWaitlistEntry(ctx, PromoteWaitlistEntryParams{
+			ID:        first.ID,
+			HoldUntil: sql.NullTime{Time: time.Now().Add(lapsed), Valid: true},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := testStore.ExpireWaitlistHoldsTx(ctx, holdFor); err != nil {
+			t.Fatal(err)
+		}
+
+		requireEntryStatus(t, first.ID, "expired")
+		promoted := requireEntryStatus(t, second.ID, "promoted")
+		if !promoted.HoldUntil.Valid {
+			t.Fatal("promoted entry has no hold")
+		}
+	})
+
+	t.Run("nobody left waiting", func(t *testing.T) {
+		_, err := testStore.PromoteWaitlistEntry(ctx, PromoteWaitlistEntryParams{
+			ID:        second.ID,
+			HoldUntil: sql.NullTime{Time: time.Now().Add(lapsed), Valid: true},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := testStore.ExpireWaitlistHoldsTx(ctx, holdFor); err != nil {
+			t.Fatal(err)
+		}
+		requireEntryStatus(t, second.ID, "expired")
+	})
+}
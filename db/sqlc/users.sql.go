@@ -100,11 +100,34 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (Users, erro
 
 const listUsers = `-- name: ListUsers :many
 SELECT id, email, password, firstname, lastname, created_at FROM users
-ORDER BY lastname, firstname
+WHERE $1::uuid IS NULL
+  OR ($2::text = 'newest'
+    AND (created_at, id) < ($3::timestamp, $1::uuid))
+  OR ($2::text = 'email'
+    AND (email, id) > ($4::varchar, $1::uuid))
+ORDER BY
+  CASE WHEN $2::text = 'newest' THEN created_at END DESC,
+  CASE WHEN $2::text = 'newest' THEN id END DESC,
+  email, id
+LIMIT $5
 `
 
-func (q *Queries) ListUsers(ctx context.Context) ([]Users, error) {
-	rows, err := q.db.QueryContext(ctx, listUsers)
+type ListUsersParams struct {
+	CursorID        uuid.NullUUID  `json:"cursor_id"`
+	Sort            string         `json:"sort"`
+	CursorCreatedAt sql.NullTime   `json:"cursor_created_at"`
+	CursorEmail     sql.NullString `json:"cursor_email"`
+	PageLimit       int32          `json:"page_limit"`
+}
+
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]Users, error) {
+	rows, err := q.db.QueryContext(ctx, listUsers,
+		arg.CursorID,
+		arg.Sort,
+		arg.CursorCreatedAt,
+		arg.CursorEmail,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
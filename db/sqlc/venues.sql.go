@@ -13,6 +13,110 @@ import (
 	"github.com/lib/pq"
 )
 
+const countVenueFacets = `-- name: CountVenueFacets :many
+SELECT
+  (CASE
+    WHEN GROUPING(type) = 0 THEN 'type'
+    WHEN GROUPING(room_type) = 0 THEN 'room_type'
+    WHEN GROUPING(bed_type) = 0 THEN 'bed_type'
+    ELSE 'has_accomodation'
+  END)::text AS facet,
+  (CASE
+    WHEN GROUPING(type) = 0 THEN type
+    WHEN GROUPING(room_type) = 0 THEN room_type
+    WHEN GROUPING(bed_type) = 0 THEN bed_type
+    ELSE has_accomodation::text
+  END)::text AS value,
+  count(*) AS venue_count
+FROM venues
+WHERE ($1::varchar IS NULL OR type = $1)
+  AND ($2::varchar IS NULL OR location ILIKE '%' || $2::varchar || '%')
+  AND ($3::integer IS NULL OR capacity >= $3)
+  AND ($4::varchar[] IS NULL OR facilities @> $4::varchar[])
+  AND ($5::boolean IS NULL OR has_accomodation = $5)
+  AND ($6::varchar IS NULL OR bed_type = $6)
+  AND ($7::varchar IS NULL OR room_type = $7)
+  AND ($8::integer IS NULL OR rent >= $8)
+  AND ($9::integer IS NULL OR rent <= $9)
+  AND ($10::integer IS NULL OR booking_price >= $10)
+  AND ($11::integer IS NULL OR booking_price <= $11)
+  AND ($12::date IS NULL OR (
+    is_available
+    AND weekdays_include(rental_days, $12::date)
+    AND NOT EXISTS (
+      SELECT 1 FROM "bookedVenues"
+      WHERE "bookedVenues".venue_id = venues.id
+        AND "bookedVenues".booked_for >= $12::date
+        AND "bookedVenues".booked_for < $12::date + 1
+        AND "bookedVenues".status = 'confirmed')))
+GROUP BY GROUPING SETS ((type), (room_type), (bed_type), (has_accomodation))
+HAVING (CASE
+    WHEN GROUPING(type) = 0 THEN type
+    WHEN GROUPING(room_type) = 0 THEN room_type
+    WHEN GROUPING(bed_type) = 0 THEN bed_type
+    ELSE has_accomodation::text
+  END) IS NOT NULL
+ORDER BY facet, venue_count DESC, value
+`
+
+type CountVenueFacetsParams struct {
+	Type            sql.NullString `json:"type"`
+	Location        sql.NullString `json:"location"`
+	MinCapacity     sql.NullInt32  `json:"min_capacity"`
+	Facilities      []string       `json:"facilities"`
+	HasAccomodation sql.NullBool   `json:"has_accomodation"`
+	BedType         sql.NullString `json:"bed_type"`
+	RoomType        sql.NullString `json:"room_type"`
+	MinRent         sql.NullInt32  `json:"min_rent"`
+	MaxRent         sql.NullInt32  `json:"max_rent"`
+	MinBookingPrice sql.NullInt32  `json:"min_booking_price"`
+	MaxBookingPrice sql.NullInt32  `json:"max_booking_price"`
+	AvailableOn     sql.NullTime   `json:"available_on"`
+}
+
+type CountVenueFacetsRow struct {
+	Facet      string `json:"facet"`
+	Value      string `json:"value"`
+	VenueCount int64  `json:"venue_count"`
+}
+
+// Counts the venues matching a search by each value of the facet columns.
+func (q *Queries) CountVenueFacets(ctx context.Context, arg CountVenueFacetsParams) ([]CountVenueFacetsRow, error) {
+	rows, err := q.db.QueryContext(ctx, countVenueFacets,
+		arg.Type,
+		arg.Location,
+		arg.MinCapacity,
+		pq.Array(arg.Facilities),
+		arg.HasAccomodation,
+		arg.BedType,
+		arg.RoomType,
+		arg.MinRent,
+		arg.MaxRent,
+		arg.MinBookingPrice,
+		arg.MaxBookingPrice,
+		arg.AvailableOn,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountVenueFacetsRow
+	for rows.Next() {
+		var i CountVenueFacetsRow
+		if err := rows.Scan(&i.Facet, &i.Value, &i.VenueCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createVenue = `-- name: CreateVenue :one
 INSERT INTO "venues" (
   image_links,
@@ -34,46 +138,52 @@ INSERT INTO "venues" (
   opens_at,
   closes_at,
   rental_days,
-  booking_price
+  booking_price,
+  latitude,
+  longitude,
+  currency
 ) VALUES (
-  $1::varchar[], $2, $3, $4, $5, $6, $7, $8::varchar[], $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23
 )
-RETURNING id, image_links, name, type, description, location, dimension, capacity, facilities, has_accomodation, room_type, no_of_rooms, sleeps, bed_type, rent, owned_by, is_available, opens_at, closes_at, rental_days, booking_price, created_at
+RETURNING id, image_links, name, type, description, location, dimension, capacity, facilities, has_accomodation, room_type, no_of_rooms, sleeps, bed_type, rent, owned_by, is_available, opens_at, closes_at, rental_days, booking_price, created_at, search_vector, latitude, longitude, full_refund_hours, no_refund_hours, partial_refund_percent, currency
 `
 
 type CreateVenueParams struct {
-	Column1         []string       `json:"column_1"`
-	Name            string         `json:"name"`
-	Type            sql.NullString `json:"type"`
-	Description     sql.NullString `json:"description"`
-	Location        string         `json:"location"`
-	Dimension       sql.NullString `json:"dimension"`
-	Capacity        sql.NullInt32  `json:"capacity"`
-	Column8         []string       `json:"column_8"`
-	HasAccomodation sql.NullBool   `json:"has_accomodation"`
-	RoomType        sql.NullString `json:"room_type"`
-	NoOfRooms       sql.NullInt32  `json:"no_of_rooms"`
-	Sleeps          sql.NullString `json:"sleeps"`
-	BedType         sql.NullString `json:"bed_type"`
-	Rent            sql.NullInt32  `json:"rent"`
-	OwnedBy         uuid.NullUUID  `json:"owned_by"`
-	IsAvailable     sql.NullBool   `json:"is_available"`
-	OpensAt         sql.NullTime   `json:"opens_at"`
-	ClosesAt        sql.NullTime   `json:"closes_at"`
-	RentalDays      sql.NullString `json:"rental_days"`
-	BookingPrice    sql.NullInt32  `json:"booking_price"`
+	ImageLinks      []string        `json:"image_links"`
+	Name            string          `json:"name"`
+	Type            sql.NullString  `json:"type"`
+	Description     sql.NullString  `json:"description"`
+	Location        string          `json:"location"`
+	Dimension       sql.NullString  `json:"dimension"`
+	Capacity        sql.NullInt32   `json:"capacity"`
+	Facilities      []string        `json:"facilities"`
+	HasAccomodation sql.NullBool    `json:"has_accomodation"`
+	RoomType        sql.NullString  `json:"room_type"`
+	NoOfRooms       sql.NullInt32   `json:"no_of_rooms"`
+	Sleeps          sql.NullString  `json:"sleeps"`
+	BedType         sql.NullString  `json:"bed_type"`
+	Rent            sql.NullInt32   `json:"rent"`
+	OwnedBy         uuid.NullUUID   `json:"owned_by"`
+	IsAvailable     sql.NullBool    `json:"is_available"`
+	OpensAt         sql.NullTime    `json:"opens_at"`
+	ClosesAt        sql.NullTime    `json:"closes_at"`
+	RentalDays      sql.NullString  `json:"rental_days"`
+	BookingPrice    sql.NullInt32   `json:"booking_price"`
+	Latitude        sql.NullFloat64 `json:"latitude"`
+	Longitude       sql.NullFloat64 `json:"longitude"`
+	Currency        string          `json:"currency"`
 }
 
 func (q *Queries) CreateVenue(ctx context.Context, arg CreateVenueParams) (Venues, error) {
 	row := q.db.QueryRowContext(ctx, createVenue,
-		pq.Array(arg.Column1),
+		pq.Array(arg.ImageLinks),
 		arg.Name,
 		arg.Type,
 		arg.Description,
 		arg.Location,
 		arg.Dimension,
 		arg.Capacity,
-		pq.Array(arg.Column8),
+		pq.Array(arg.Facilities),
 		arg.HasAccomodation,
 		arg.RoomType,
 		arg.NoOfRooms,
@@ -86,6 +196,9 @@ func (q *Queries) CreateVenue(ctx context.Context, arg CreateVenueParams) (Venue
 		arg.ClosesAt,
 		arg.RentalDays,
 		arg.BookingPrice,
+		arg.Latitude,
+		arg.Longitude,
+		arg.Currency,
 	)
 	var i Venues
 	err := row.Scan(
@@ -111,6 +224,13 @@ func (q *Queries) CreateVenue(ctx context.Context, arg CreateVenueParams) (Venue
 		&i.RentalDays,
 		&i.BookingPrice,
 		&i.CreatedAt,
+		&i.SearchVector,
+		&i.Latitude,
+		&i.Longitude,
+		&i.FullRefundHours,
+		&i.NoRefundHours,
+		&i.PartialRefundPercent,
+		&i.Currency,
 	)
 	return i, err
 }
@@ -126,7 +246,7 @@ func (q *Queries) DeleteVenue(ctx context.Context, id uuid.UUID) error {
 }
 
 const getVenue = `-- name: GetVenue :one
-SELECT id, image_links, name, type, description, location, dimension, capacity, facilities, has_accomodation, room_type, no_of_rooms, sleeps, bed_type, rent, owned_by, is_available, opens_at, closes_at, rental_days, booking_price, created_at FROM venues
+SELECT id, image_links, name, type, description, location, dimension, capacity, facilities, has_accomodation, room_type, no_of_rooms, sleeps, bed_type, rent, owned_by, is_available, opens_at, closes_at, rental_days, booking_price, created_at, search_vector, latitude, longitude, full_refund_hours, no_refund_hours, partial_refund_percent, currency FROM venues
 WHERE id = $1 LIMIT 1
 `
 
@@ -156,17 +276,326 @@ func (q *Queries) GetVenue(ctx context.Context, id uuid.UUID) (Venues, error) {
 		&i.RentalDays,
 		&i.BookingPrice,
 		&i.CreatedAt,
+		&i.SearchVector,
+		&i.Latitude,
+		&i.Longitude,
+		&i.FullRefundHours,
+		&i.NoRefundHours,
+		&i.PartialRefundPercent,
+		&i.Currency,
 	)
 	return i, err
 }
 
+const getVenueForUpdate = `-- name: GetVenueForUpdate :one
+SELECT id, image_links, name, type, description, location, dimension, capacity, facilities, has_accomodation, room_type, no_of_rooms, sleeps, bed_type, rent, owned_by, is_available, opens_at, closes_at, rental_days, booking_price, created_at, search_vector, latitude, longitude, full_refund_hours, no_refund_hours, partial_refund_percent, currency FROM venues
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetVenueForUpdate(ctx context.Context, id uuid.UUID) (Venues, error) {
+	row := q.db.QueryRowContext(ctx, getVenueForUpdate, id)
+	var i Venues
+	err := row.Scan(
+		&i.ID,
+		pq.Array(&i.ImageLinks),
+		&i.Name,
+		&i.Type,
+		&i.Description,
+		&i.Location,
+		&i.Dimension,
+		&i.Capacity,
+		pq.Array(&i.Facilities),
+		&i.HasAccomodation,
+		&i.RoomType,
+		&i.NoOfRooms,
+		&i.Sleeps,
+		&i.BedType,
+		&i.Rent,
+		&i.OwnedBy,
+		&i.IsAvailable,
+		&i.OpensAt,
+		&i.ClosesAt,
+		&i.RentalDays,
+		&i.BookingPrice,
+		&i.CreatedAt,
+		&i.SearchVector,
+		&i.Latitude,
+		&i.Longitude,
+		&i.FullRefundHours,
+		&i.NoRefundHours,
+		&i.PartialRefundPercent,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const listVenuesNearby = `-- name: ListVenuesNearby :many
+WITH nearby AS (
+  SELECT id, (2 * 6371 * asin(least(1, sqrt(
+    power(sin(radians(latitude - $5::float8) / 2), 2) +
+    cos(radians($5::float8)) * cos(radians(latitude)) *
+    power(sin(radians(longitude - $6::float8) / 2), 2)
+  ))))::float8 AS distance_km
+  FROM venues
+  WHERE latitude BETWEEN $7::float8 AND $8::float8
+    AND longitude BETWEEN $9::float8 AND $10::float8
+)
+SELECT venues.id, venues.image_links, venues.name, venues.type, venues.description, venues.location, venues.dimension, venues.capacity, venues.facilities, venues.has_accomodation, venues.room_type, venues.no_of_rooms, venues.sleeps, venues.bed_type, venues.rent, venues.owned_by, venues.is_available, venues.opens_at, venues.closes_at, venues.rental_days, venues.booking_price, venues.created_at, venues.search_vector, venues.latitude, venues.longitude, venues.full_refund_hours, venues.no_refund_hours, venues.partial_refund_percent, venues.currency, nearby.distance_km FROM nearby
+JOIN venues ON venues.id = nearby.id
+WHERE nearby.distance_km <= $1::float8
+  AND ($2::uuid IS NULL
+    OR (nearby.distance_km, nearby.id) > ($3::float8, $2::uuid))
+ORDER BY nearby.distance_km, nearby.id
+LIMIT $4
+`
+
+type ListVenuesNearbyParams struct {
+	RadiusKm       float64         `json:"radius_km"`
+	CursorID       uuid.NullUUID   `json:"cursor_id"`
+	CursorDistance sql.NullFloat64 `json:"cursor_distance"`
+	PageLimit      int32           `json:"page_limit"`
+	Lat            float64         `json:"lat"`
+	Lng            float64         `json:"lng"`
+	MinLat         float64         `json:"min_lat"`
+	MaxLat         float64         `json:"max_lat"`
+	MinLng         float64         `json:"min_lng"`
+	MaxLng         float64         `json:"max_lng"`
+}
+
+type ListVenuesNearbyRow struct {
+	Venues     Venues  `json:"venues"`
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// The bounding box narrows the candidates down using the index before the
+// haversine distance is computed.
+func (q *Queries) ListVenuesNearby(ctx context.Context, arg ListVenuesNearbyParams) ([]ListVenuesNearbyRow, error) {
+	rows, err := q.db.QueryContext(ctx, listVenuesNearby,
+		arg.RadiusKm,
+		arg.CursorID,
+		arg.CursorDistance,
+		arg.PageLimit,
+		arg.Lat,
+		arg.Lng,
+		arg.MinLat,
+		arg.MaxLat,
+		arg.MinLng,
+		arg.MaxLng,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListVenuesNearbyRow
+	for rows.Next() {
+		var i ListVenuesNearbyRow
+		if err := rows.Scan(
+			&i.Venues.ID,
+			pq.Array(&i.Venues.ImageLinks),
+			&i.Venues.Name,
+			&i.Venues.Type,
+			&i.Venues.Description,
+			&i.Venues.Location,
+			&i.Venues.Dimension,
+			&i.Venues.Capacity,
+			pq.Array(&i.Venues.Facilities),
+			&i.Venues.HasAccomodation,
+			&i.Venues.RoomType,
+			&i.Venues.NoOfRooms,
+			&i.Venues.Sleeps,
+			&i.Venues.BedType,
+			&i.Venues.Rent,
+			&i.Venues.OwnedBy,
+			&i.Venues.IsAvailable,
+			&i.Venues.OpensAt,
+			&i.Venues.ClosesAt,
+			&i.Venues.RentalDays,
+			&i.Venues.BookingPrice,
+			&i.Venues.CreatedAt,
+			&i.Venues.SearchVector,
+			&i.Venues.Latitude,
+			&i.Venues.Longitude,
+			&i.Venues.FullRefundHours,
+			&i.Venues.NoRefundHours,
+			&i.Venues.PartialRefundPercent,
+			&i.Venues.Currency,
+			&i.DistanceKm,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listvenues = `-- name: Listvenues :many
-SELECT id, image_links, name, type, description, location, dimension, capacity, facilities, has_accomodation, room_type, no_of_rooms, sleeps, bed_type, rent, owned_by, is_available, opens_at, closes_at, rental_days, booking_price, created_at FROM venues
-ORDER BY name
+SELECT id, image_links, name, type, description, location, dimension, capacity, facilities, has_accomodation, room_type, no_of_rooms, sleeps, bed_type, rent, owned_by, is_available, opens_at, closes_at, rental_days, booking_price, created_at, search_vector, latitude, longitude, full_refund_hours, no_refund_hours, partial_refund_percent, currency FROM venues
+WHERE ($1::varchar IS NULL OR type = $1)
+  AND ($2::boolean IS NULL OR is_available = $2)
+  AND ($3::uuid IS NULL
+    OR ($4::text = 'newest'
+      AND (created_at, id) < ($5::timestamp, $3::uuid))
+    OR ($4::text = 'name'
+      AND (name, id) > ($6::varchar, $3::uuid)))
+ORDER BY
+  CASE WHEN $4::text = 'newest' THEN created_at END DESC,
+  CASE WHEN $4::text = 'newest' THEN id END DESC,
+  name, id
+LIMIT $7
+`
+
+type ListvenuesParams struct {
+	Type            sql.NullString `json:"type"`
+	IsAvailable     sql.NullBool   `json:"is_available"`
+	CursorID        uuid.NullUUID  `json:"cursor_id"`
+	Sort            string         `json:"sort"`
+	CursorCreatedAt sql.NullTime   `json:"cursor_created_at"`
+	CursorName      sql.NullString `json:"cursor_name"`
+	PageLimit       int32          `json:"page_limit"`
+}
+
+func (q *Queries) Listvenues(ctx context.Context, arg ListvenuesParams) ([]Venues, error) {
+	rows, err := q.db.QueryContext(ctx, listvenues,
+		arg.Type,
+		arg.IsAvailable,
+		arg.CursorID,
+		arg.Sort,
+		arg.CursorCreatedAt,
+		arg.CursorName,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Venues
+	for rows.Next() {
+		var i Venues
+		if err := rows.Scan(
+			&i.ID,
+			pq.Array(&i.ImageLinks),
+			&i.Name,
+			&i.Type,
+			&i.Description,
+			&i.Location,
+			&i.Dimension,
+			&i.Capacity,
+			pq.Array(&i.Facilities),
+			&i.HasAccomodation,
+			&i.RoomType,
+			&i.NoOfRooms,
+			&i.Sleeps,
+			&i.BedType,
+			&i.Rent,
+			&i.OwnedBy,
+			&i.IsAvailable,
+			&i.OpensAt,
+			&i.ClosesAt,
+			&i.RentalDays,
+			&i.BookingPrice,
+			&i.CreatedAt,
+			&i.SearchVector,
+			&i.Latitude,
+			&i.Longitude,
+			&i.FullRefundHours,
+			&i.NoRefundHours,
+			&i.PartialRefundPercent,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchVenues = `-- name: SearchVenues :many
+SELECT id, image_links, name, type, description, location, dimension, capacity, facilities, has_accomodation, room_type, no_of_rooms, sleeps, bed_type, rent, owned_by, is_available, opens_at, closes_at, rental_days, booking_price, created_at, search_vector, latitude, longitude, full_refund_hours, no_refund_hours, partial_refund_percent, currency FROM venues
+WHERE ($1::varchar IS NULL OR type = $1)
+  AND ($2::varchar IS NULL OR location ILIKE '%' || $2::varchar || '%')
+  AND ($3::integer IS NULL OR capacity >= $3)
+  AND ($4::varchar[] IS NULL OR facilities @> $4::varchar[])
+  AND ($5::boolean IS NULL OR has_accomodation = $5)
+  AND ($6::varchar IS NULL OR bed_type = $6)
+  AND ($7::varchar IS NULL OR room_type = $7)
+  AND ($8::integer IS NULL OR rent >= $8)
+  AND ($9::integer IS NULL OR rent <= $9)
+  AND ($10::integer IS NULL OR booking_price >= $10)
+  AND ($11::integer IS NULL OR booking_price <= $11)
+  AND ($12::date IS NULL OR (
+    is_available
+    AND weekdays_include(rental_days, $12::date)
+    AND NOT EXISTS (
+      SELECT 1 FROM "bookedVenues"
+      WHERE "bookedVenues".venue_id = venues.id
+        AND "bookedVenues".booked_for >= $12::date
+        AND "bookedVenues".booked_for < $12::date + 1
+        AND "bookedVenues".status = 'confirmed')))
+  AND ($13::uuid IS NULL
+    OR ($14::text = 'newest'
+      AND (created_at, id) < ($15::timestamp, $13::uuid))
+    OR ($14::text = 'name'
+      AND (name, id) > ($16::varchar, $13::uuid)))
+ORDER BY
+  CASE WHEN $14::text = 'newest' THEN created_at END DESC,
+  CASE WHEN $14::text = 'newest' THEN id END DESC,
+  name, id
+LIMIT $17
 `
 
-func (q *Queries) Listvenues(ctx context.Context) ([]Venues, error) {
-	rows, err := q.db.QueryContext(ctx, listvenues)
+type SearchVenuesParams struct {
+	Type            sql.NullString `json:"type"`
+	Location        sql.NullString `json:"location"`
+	MinCapacity     sql.NullInt32  `json:"min_capacity"`
+	Facilities      []string       `json:"facilities"`
+	HasAccomodation sql.NullBool   `json:"has_accomodation"`
+	BedType         sql.NullString `json:"bed_type"`
+	RoomType        sql.NullString `json:"room_type"`
+	MinRent         sql.NullInt32  `json:"min_rent"`
+	MaxRent         sql.NullInt32  `json:"max_rent"`
+	MinBookingPrice sql.NullInt32  `json:"min_booking_price"`
+	MaxBookingPrice sql.NullInt32  `json:"max_booking_price"`
+	AvailableOn     sql.NullTime   `json:"available_on"`
+	CursorID        uuid.NullUUID  `json:"cursor_id"`
+	Sort            string         `json:"sort"`
+	CursorCreatedAt sql.NullTime   `json:"cursor_created_at"`
+	CursorName      sql.NullString `json:"cursor_name"`
+	PageLimit       int32          `json:"page_limit"`
+}
+
+func (q *Queries) SearchVenues(ctx context.Context, arg SearchVenuesParams) ([]Venues, error) {
+	rows, err := q.db.QueryContext(ctx, searchVenues,
+		arg.Type,
+		arg.Location,
+		arg.MinCapacity,
+		pq.Array(arg.Facilities),
+		arg.HasAccomodation,
+		arg.BedType,
+		arg.RoomType,
+		arg.MinRent,
+		arg.MaxRent,
+		arg.MinBookingPrice,
+		arg.MaxBookingPrice,
+		arg.AvailableOn,
+		arg.CursorID,
+		arg.Sort,
+		arg.CursorCreatedAt,
+		arg.CursorName,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -197,6 +626,13 @@ func (q *Queries) Listvenues(ctx context.Context) ([]Venues, error) {
 			&i.RentalDays,
 			&i.BookingPrice,
 			&i.CreatedAt,
+			&i.SearchVector,
+			&i.Latitude,
+			&i.Longitude,
+			&i.FullRefundHours,
+			&i.NoRefundHours,
+			&i.PartialRefundPercent,
+			&i.Currency,
 		); err != nil {
 			return nil, err
 		}
@@ -211,16 +647,16 @@ func (q *Queries) Listvenues(ctx context.Context) ([]Venues, error) {
 	return items, nil
 }
 
-const updateVenue = `-- name: UpdateVenue :exec
+const updateVenue = `-- name: UpdateVenue :one
 UPDATE venues
   set name = $2,
-  image_links = $3::varchar[],
+  image_links = $3,
   type = $4,
   description = $5,
   location = $6,
   dimension = $7,
   capacity = $8,
-  facilities = $9::varchar[],
+  facilities = $9,
   has_accomodation = $10,
   room_type = $11,
   no_of_rooms = $12,
@@ -232,45 +668,52 @@ UPDATE venues
   opens_at = $18,
   closes_at = $19,
   rental_days = $20,
-  booking_price = $21
+  booking_price = $21,
+  latitude = $22,
+  longitude = $23,
+  currency = $24
 WHERE id = $1
+RETURNING id, image_links, name, type, description, location, dimension, capacity, facilities, has_accomodation, room_type, no_of_rooms, sleeps, bed_type, rent, owned_by, is_available, opens_at, closes_at, rental_days, booking_price, created_at, search_vector, latitude, longitude, full_refund_hours, no_refund_hours, partial_refund_percent, currency
 `
 
 type UpdateVenueParams struct {
-	ID              uuid.UUID      `json:"id"`
-	Name            string         `json:"name"`
-	Column3         []string       `json:"column_3"`
-	Type            sql.NullString `json:"type"`
-	Description     sql.NullString `json:"description"`
-	Location        string         `json:"location"`
-	Dimension       sql.NullString `json:"dimension"`
-	Capacity        sql.NullInt32  `json:"capacity"`
-	Column9         []string       `json:"column_9"`
-	HasAccomodation sql.NullBool   `json:"has_accomodation"`
-	RoomType        sql.NullString `json:"room_type"`
-	NoOfRooms       sql.NullInt32  `json:"no_of_rooms"`
-	Sleeps          sql.NullString `json:"sleeps"`
-	BedType         sql.NullString `json:"bed_type"`
-	Rent            sql.NullInt32  `json:"rent"`
-	OwnedBy         uuid.NullUUID  `json:"owned_by"`
-	IsAvailable     sql.NullBool   `json:"is_available"`
-	OpensAt         sql.NullTime   `json:"opens_at"`
-	ClosesAt        sql.NullTime   `json:"closes_at"`
-	RentalDays      sql.NullString `json:"rental_days"`
-	BookingPrice    sql.NullInt32  `json:"booking_price"`
+	ID              uuid.UUID       `json:"id"`
+	Name            string          `json:"name"`
+	ImageLinks      []string        `json:"image_links"`
+	Type            sql.NullString  `json:"type"`
+	Description     sql.NullString  `json:"description"`
+	Location        string          `json:"location"`
+	Dimension       sql.NullString  `json:"dimension"`
+	Capacity        sql.NullInt32   `json:"capacity"`
+	Facilities      []string        `json:"facilities"`
+	HasAccomodation sql.NullBool    `json:"has_accomodation"`
+	RoomType        sql.NullString  `json:"room_type"`
+	NoOfRooms       sql.NullInt32   `json:"no_of_rooms"`
+	Sleeps          sql.NullString  `json:"sleeps"`
+	BedType         sql.NullString  `json:"bed_type"`
+	Rent            sql.NullInt32   `json:"rent"`
+	OwnedBy         uuid.NullUUID   `json:"owned_by"`
+	IsAvailable     sql.NullBool    `json:"is_available"`
+	OpensAt         sql.NullTime    `json:"opens_at"`
+	ClosesAt        sql.NullTime    `json:"closes_at"`
+	RentalDays      sql.NullString  `json:"rental_days"`
+	BookingPrice    sql.NullInt32   `json:"booking_price"`
+	Latitude        sql.NullFloat64 `json:"latitude"`
+	Longitude       sql.NullFloat64 `json:"longitude"`
+	Currency        string          `json:"currency"`
 }
 
-func (q *Queries) UpdateVenue(ctx context.Context, arg UpdateVenueParams) error {
-	_, err := q.db.ExecContext(ctx, updateVenue,
+func (q *Queries) UpdateVenue(ctx context.Context, arg UpdateVenueParams) (Venues, error) {
+	row := q.db.QueryRowContext(ctx, updateVenue,
 		arg.ID,
 		arg.Name,
-		pq.Array(arg.Column3),
+		pq.Array(arg.ImageLinks),
 		arg.Type,
 		arg.Description,
 		arg.Location,
 		arg.Dimension,
 		arg.Capacity,
-		pq.Array(arg.Column9),
+		pq.Array(arg.Facilities),
 		arg.HasAccomodation,
 		arg.RoomType,
 		arg.NoOfRooms,
@@ -283,6 +726,99 @@ func (q *Queries) UpdateVenue(ctx context.Context, arg UpdateVenueParams) error
 		arg.ClosesAt,
 		arg.RentalDays,
 		arg.BookingPrice,
+		arg.Latitude,
+		arg.Longitude,
+		arg.Currency,
 	)
-	return err
+	var i Venues
+	err := row.Scan(
+		&i.ID,
+		pq.Array(&i.ImageLinks),
+		&i.Name,
+		&i.Type,
+		&i.Description,
+		&i.Location,
+		&i.Dimension,
+		&i.Capacity,
+		pq.Array(&i.Facilities),
+		&i.HasAccomodation,
+		&i.RoomType,
+		&i.NoOfRooms,
+		&i.Sleeps,
+		&i.BedType,
+		&i.Rent,
+		&i.OwnedBy,
+		&i.IsAvailable,
+		&i.OpensAt,
+		&i.ClosesAt,
+		&i.RentalDays,
+		&i.BookingPrice,
+		&i.CreatedAt,
+		&i.SearchVector,
+		&i.Latitude,
+		&i.Longitude,
+		&i.FullRefundHours,
+		&i.NoRefundHours,
+		&i.PartialRefundPercent,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const updateVenueCancellationPolicy = `-- name: UpdateVenueCancellationPolicy :one
+UPDATE venues
+SET full_refund_hours = $1,
+    no_refund_hours = $2,
+    partial_refund_percent = $3
+WHERE id = $4
+RETURNING id, image_links, name, type, description, location, dimension, capacity, facilities, has_accomodation, room_type, no_of_rooms, sleeps, bed_type, rent, owned_by, is_available, opens_at, closes_at, rental_days, booking_price, created_at, search_vector, latitude, longitude, full_refund_hours, no_refund_hours, partial_refund_percent, currency
+`
+
+type UpdateVenueCancellationPolicyParams struct {
+	FullRefundHours      int32     `json:"full_refund_hours"`
+	NoRefundHours        int32     `json:"no_refund_hours"`
+	PartialRefundPercent int32     `json:"partial_refund_percent"`
+	ID                   uuid.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateVenueCancellationPolicy(ctx context.Context, arg UpdateVenueCancellationPolicyParams) (Venues, error) {
+	row := q.db.QueryRowContext(ctx, updateVenueCancellationPolicy,
+		arg.FullRefundHours,
+		arg.NoRefundHours,
+		arg.PartialRefundPercent,
+		arg.ID,
+	)
+	var i Venues
+	err := row.Scan(
+		&i.ID,
+		pq.Array(&i.ImageLinks),
+		&i.Name,
+		&i.Type,
+		&i.Description,
+		&i.Location,
+		&i.Dimension,
+		&i.Capacity,
+		pq.Array(&i.Facilities),
+		&i.HasAccomodation,
+		&i.RoomType,
+		&i.NoOfRooms,
+		&i.Sleeps,
+		&i.BedType,
+		&i.Rent,
+		&i.OwnedBy,
+		&i.IsAvailable,
+		&i.OpensAt,
+		&i.ClosesAt,
+		&i.RentalDays,
+		&i.BookingPrice,
+		&i.CreatedAt,
+		&i.SearchVector,
+		&i.Latitude,
+		&i.Longitude,
+		&i.FullRefundHours,
+		&i.NoRefundHours,
+		&i.PartialRefundPercent,
+		&i.Currency,
+	)
+	return i, err
 }
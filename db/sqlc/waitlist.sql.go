@@ -0,0 +1,455 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: waitlist.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const claimEventHold = `-- name: ClaimEventHold :execrows
+UPDATE waitlist_entries
+  set hold_until = NULL
+WHERE event_id = $1 AND profile_id = $2 AND status = 'promoted' AND hold_until > now()
+`
+
+type ClaimEventHoldParams struct {
+	EventID   uuid.NullUUID `json:"event_id"`
+	ProfileID uuid.UUID     `json:"profile_id"`
+}
+
+func (q *Queries) ClaimEventHold(ctx context.Context, arg ClaimEventHoldParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, claimEventHold, arg.EventID, arg.ProfileID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const claimVenueHold = `-- name: ClaimVenueHold :execrows
+UPDATE waitlist_entries
+  set hold_until = NULL
+WHERE venue_id = $1 AND booked_for = $2 AND profile_id = $3 AND status = 'promoted' AND hold_until > now()
+`
+
+type ClaimVenueHoldParams struct {
+	VenueID   uuid.NullUUID `json:"venue_id"`
+	BookedFor sql.NullTime  `json:"booked_for"`
+	ProfileID uuid.UUID     `json:"profile_id"`
+}
+
+func (q *Queries) ClaimVenueHold(ctx context.Context, arg ClaimVenueHoldParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, claimVenueHold, arg.VenueID, arg.BookedFor, arg.ProfileID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countEventHolds = `-- name: CountEventHolds :one
+SELECT count(*) FROM waitlist_entries
+WHERE event_id = $1 AND status = 'promoted' AND hold_until > now()
+`
+
+func (q *Queries) CountEventHolds(ctx context.Context, eventID uuid.NullUUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countEventHolds, eventID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countVenueHolds = `-- name: CountVenueHolds :one
+SELECT count(*) FROM waitlist_entries
+WHERE venue_id = $1 AND booked_for = $2 AND status = 'promoted' AND hold_until > now()
+`
+
+type CountVenueHoldsParams struct {
+	VenueID   uuid.NullUUID `json:"venue_id"`
+	BookedFor sql.NullTime  `json:"booked_for"`
+}
+
+func (q *Queries) CountVenueHolds(ctx context.Context, arg CountVenueHoldsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countVenueHolds, arg.VenueID, arg.BookedFor)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createWaitlistEntry = `-- name: CreateWaitlistEntry :one
+INSERT INTO "waitlist_entries" (
+  event_id,
+  venue_id,
+  booked_for,
+  profile_id
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, event_id, venue_id, booked_for, profile_id, status, promoted_at, created_at, hold_until
+`
+
+type CreateWaitlistEntryParams struct {
+	EventID   uuid.NullUUID `json:"event_id"`
+	VenueID   uuid.NullUUID `json:"venue_id"`
+	BookedFor sql.NullTime  `json:"booked_for"`
+	ProfileID uuid.UUID     `json:"profile_id"`
+}
+
+func (q *Queries) CreateWaitlistEntry(ctx context.Context, arg CreateWaitlistEntryParams) (WaitlistEntries, error) {
+	row := q.db.QueryRowContext(ctx, createWaitlistEntry,
+		arg.EventID,
+		arg.VenueID,
+		arg.BookedFor,
+		arg.ProfileID,
+	)
+	var i WaitlistEntries
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.VenueID,
+		&i.BookedFor,
+		&i.ProfileID,
+		&i.Status,
+		&i.PromotedAt,
+		&i.CreatedAt,
+		&i.HoldUntil,
+	)
+	return i, err
+}
+
+const expireWaitlistHold = `-- name: ExpireWaitlistHold :one
+UPDATE waitlist_entries
+  set status = 'expired'
+WHERE id = $1 AND status = 'promoted' AND hold_until <= now()
+RETURNING id, event_id, venue_id, booked_for, profile_id, status, promoted_at, created_at, hold_until
+`
+
+func (q *Queries) ExpireWaitlistHold(ctx context.Context, id uuid.UUID) (WaitlistEntries, error) {
+	row := q.db.QueryRowContext(ctx, expireWaitlistHold, id)
+	var i WaitlistEntries
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.VenueID,
+		&i.BookedFor,
+		&i.ProfileID,
+		&i.Status,
+		&i.PromotedAt,
+		&i.CreatedAt,
+		&i.HoldUntil,
+	)
+	return i, err
+}
+
+const getWaitlistEntry = `-- name: GetWaitlistEntry :one
+SELECT id, event_id, venue_id, booked_for, profile_id, status, promoted_at, created_at, hold_until FROM waitlist_entries
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetWaitlistEntry(ctx context.Context, id uuid.UUID) (WaitlistEntries, error) {
+	row := q.db.QueryRowContext(ctx, getWaitlistEntry, id)
+	var i WaitlistEntries
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.VenueID,
+		&i.BookedFor,
+		&i.ProfileID,
+		&i.Status,
+		&i.PromotedAt,
+		&i.CreatedAt,
+		&i.HoldUntil,
+	)
+	return i, err
+}
+
+const listLapsedHolds = `-- name: ListLapsedHolds :many
+SELECT id, event_id, venue_id, booked_for, profile_id, status, promoted_at, created_at, hold_until FROM waitlist_entries
+WHERE status = 'promoted'
+  AND hold_until <= now()
+  AND ($1::uuid IS NULL OR event_id = $1)
+  AND ($2::uuid IS NULL OR venue_id = $2)
+ORDER BY hold_until, id
+`
+
+type ListLapsedHoldsParams struct {
+	EventID uuid.NullUUID `json:"event_id"`
+	VenueID uuid.NullUUID `json:"venue_id"`
+}
+
+// Lists the holds that ended before their place was bought, optionally only
+// those of one event or venue.
+func (q *Queries) ListLapsedHolds(ctx context.Context, arg ListLapsedHoldsParams) ([]WaitlistEntries, error) {
+	rows, err := q.db.QueryContext(ctx, listLapsedHolds, arg.EventID, arg.VenueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WaitlistEntries
+	for rows.Next() {
+		var i WaitlistEntries
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.VenueID,
+			&i.BookedFor,
+			&i.ProfileID,
+			&i.Status,
+			&i.PromotedAt,
+			&i.CreatedAt,
+			&i.HoldUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWaitingByEvent = `-- name: ListWaitingByEvent :many
+SELECT id, event_id, venue_id, booked_for, profile_id, status, promoted_at, created_at, hold_until FROM waitlist_entries
+WHERE event_id = $1
+  AND status = 'waiting'
+  AND ($2::uuid IS NULL
+    OR (created_at, id) > ($3::timestamp, $2::uuid))
+ORDER BY created_at, id
+LIMIT $4
+`
+
+type ListWaitingByEventParams struct {
+	EventID         uuid.NullUUID `json:"event_id"`
+	CursorID        uuid.NullUUID `json:"cursor_id"`
+	CursorCreatedAt sql.NullTime  `json:"cursor_created_at"`
+	PageLimit       int32         `json:"page_limit"`
+}
+
+func (q *Queries) ListWaitingByEvent(ctx context.Context, arg ListWaitingByEventParams) ([]WaitlistEntries, error) {
+	rows, err := q.db.QueryContext(ctx, listWaitingByEvent,
+		arg.EventID,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WaitlistEntries
+	for rows.Next() {
+		var i WaitlistEntries
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.VenueID,
+			&i.BookedFor,
+			&i.ProfileID,
+			&i.Status,
+			&i.PromotedAt,
+			&i.CreatedAt,
+			&i.HoldUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWaitingByVenue = `-- name: ListWaitingByVenue :many
+SELECT id, event_id, venue_id, booked_for, profile_id, status, promoted_at, created_at, hold_until FROM waitlist_entries
+WHERE venue_id = $1
+  AND status = 'waiting'
+  AND ($2::date IS NULL OR booked_for = $2)
+  AND ($3::uuid IS NULL
+    OR (created_at, id) > ($4::timestamp, $3::uuid))
+ORDER BY created_at, id
+LIMIT $5
+`
+
+type ListWaitingByVenueParams struct {
+	VenueID         uuid.NullUUID `json:"venue_id"`
+	BookedFor       sql.NullTime  `json:"booked_for"`
+	CursorID        uuid.NullUUID `json:"cursor_id"`
+	CursorCreatedAt sql.NullTime  `json:"cursor_created_at"`
+	PageLimit       int32         `json:"page_limit"`
+}
+
+func (q *Queries) ListWaitingByVenue(ctx context.Context, arg ListWaitingByVenueParams) ([]WaitlistEntries, error) {
+	rows, err := q.db.QueryContext(ctx, listWaitingByVenue,
+		arg.VenueID,
+		arg.BookedFor,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WaitlistEntries
+	for rows.Next() {
+		var i WaitlistEntries
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.VenueID,
+			&i.BookedFor,
+			&i.ProfileID,
+			&i.Status,
+			&i.PromotedAt,
+			&i.CreatedAt,
+			&i.HoldUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const nextWaitingForEvent = `-- name: NextWaitingForEvent :one
+SELECT id, event_id, venue_id, booked_for, profile_id, status, promoted_at, created_at, hold_until FROM waitlist_entries
+WHERE event_id = $1 AND status = 'waiting'
+ORDER BY created_at, id
+LIMIT 1
+FOR UPDATE SKIP LOCKED
+`
+
+func (q *Queries) NextWaitingForEvent(ctx context.Context, eventID uuid.NullUUID) (WaitlistEntries, error) {
+	row := q.db.QueryRowContext(ctx, nextWaitingForEvent, eventID)
+	var i WaitlistEntries
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.VenueID,
+		&i.BookedFor,
+		&i.ProfileID,
+		&i.Status,
+		&i.PromotedAt,
+		&i.CreatedAt,
+		&i.HoldUntil,
+	)
+	return i, err
+}
+
+const nextWaitingForVenue = `-- name: NextWaitingForVenue :one
+SELECT id, event_id, venue_id, booked_for, profile_id, status, promoted_at, created_at, hold_until FROM waitlist_entries
+WHERE venue_id = $1 AND booked_for = $2 AND status = 'waiting'
+ORDER BY created_at, id
+LIMIT 1
+FOR UPDATE SKIP LOCKED
+`
+
+type NextWaitingForVenueParams struct {
+	VenueID   uuid.NullUUID `json:"venue_id"`
+	BookedFor sql.NullTime  `json:"booked_for"`
+}
+
+func (q *Queries) NextWaitingForVenue(ctx context.Context, arg NextWaitingForVenueParams) (WaitlistEntries, error) {
+	row := q.db.QueryRowContext(ctx, nextWaitingForVenue, arg.VenueID, arg.BookedFor)
+	var i WaitlistEntries
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.VenueID,
+		&i.BookedFor,
+		&i.ProfileID,
+		&i.Status,
+		&i.PromotedAt,
+		&i.CreatedAt,
+		&i.HoldUntil,
+	)
+	return i, err
+}
+
+const promoteWaitlistEntry = `-- name: PromoteWaitlistEntry :one
+UPDATE waitlist_entries
+  set status = 'promoted',
+  promoted_at = now(),
+  hold_until = $2
+WHERE id = $1
+RETURNING id, event_id, venue_id, booked_for, profile_id, status, promoted_at, created_at, hold_until
+`
+
+type PromoteWaitlistEntryParams struct {
+	ID        uuid.UUID    `json:"id"`
+	HoldUntil sql.NullTime `json:"hold_until"`
+}
+
+func (q *Queries) PromoteWaitlistEntry(ctx context.Context, arg PromoteWaitlistEntryParams) (WaitlistEntries, error) {
+	row := q.db.QueryRowContext(ctx, promoteWaitlistEntry, arg.ID, arg.HoldUntil)
+	var i WaitlistEntries
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.VenueID,
+		&i.BookedFor,
+		&i.ProfileID,
+		&i.Status,
+		&i.PromotedAt,
+		&i.CreatedAt,
+		&i.HoldUntil,
+	)
+	return i, err
+}
+
+const withdrawEventWaitlistEntry = `-- name: WithdrawEventWaitlistEntry :execrows
+UPDATE waitlist_entries
+  set status = 'withdrawn'
+WHERE event_id = $1 AND profile_id = $2 AND status = 'waiting'
+`
+
+type WithdrawEventWaitlistEntryParams struct {
+	EventID   uuid.NullUUID `json:"event_id"`
+	ProfileID uuid.UUID     `json:"profile_id"`
+}
+
+func (q *Queries) WithdrawEventWaitlistEntry(ctx context.Context, arg WithdrawEventWaitlistEntryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, withdrawEventWaitlistEntry, arg.EventID, arg.ProfileID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const withdrawVenueWaitlistEntry = `-- name: WithdrawVenueWaitlistEntry :execrows
+UPDATE waitlist_entries
+  set status = 'withdrawn'
+WHERE venue_id = $1 AND booked_for = $2 AND profile_id = $3 AND status = 'waiting'
+`
+
+type WithdrawVenueWaitlistEntryParams struct {
+	VenueID   uuid.NullUUID `json:"venue_id"`
+	BookedFor sql.NullTime  `json:"booked_for"`
+	ProfileID uuid.UUID     `json:"profile_id"`
+}
+
+func (q *Queries) WithdrawVenueWaitlistEntry(ctx context.Context, arg WithdrawVenueWaitlistEntryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, withdrawVenueWaitlistEntry, arg.VenueID, arg.BookedFor, arg.ProfileID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
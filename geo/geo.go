@@ -0,0 +1,45 @@
+package geo
+
+import "math"
+
+// EarthRadiusKm is the mean radius of the earth used for distances.
+const EarthRadiusKm = 6371.0
+
+// Point is a position on the earth in decimal degrees.
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// BoundingBox returns the south-west and north-east corners of a box that
+// contains every point within radiusKm of center. It is used to narrow a
+// radius search down with an index before computing exact distances. Near
+// the poles or the antimeridian the box spans every longitude.
+func BoundingBox(center Point, radiusKm float64) (Point, Point) {
+	dLat := radiusKm / EarthRadiusKm * 180 / math.Pi
+	sw := Point{Lat: center.Lat - dLat, Lng: -180}
+	ne := Point{Lat: center.Lat + dLat, Lng: 180}
+
+	if sw.Lat <= -90 || ne.Lat >= 90 {
+		sw.Lat = math.Max(sw.Lat, -90)
+		ne.Lat = math.Min(ne.Lat, 90)
+		return sw, ne
+	}
+
+	dLng := dLat / math.Cos(center.Lat*math.Pi/180)
+	if center.Lng-dLng >= -180 && center.Lng+dLng <= 180 {
+		sw.Lng = center.Lng - dLng
+		ne.Lng = center.Lng + dLng
+	}
+	return sw, ne
+}
+
+// DistanceKm returns the great-circle distance between two points with the
+// haversine formula, the same one ListVenuesNearby orders venues by.
+func DistanceKm(a, b Point) float64 {
+	dLat := (b.Lat - a.Lat) * math.Pi / 180
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+	h := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(a.Lat*math.Pi/180)*math.Cos(b.Lat*math.Pi/180)*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
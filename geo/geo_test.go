@@ -0,0 +1,155 @@
+package geo
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+var (
+	london    = Point{Lat: 51.5074, Lng: -0.1278}
+	paris     = Point{Lat: 48.8566, Lng: 2.3522}
+	brussels  = Point{Lat: 50.8503, Lng: 4.3517}
+	amsterdam = Point{Lat: 52.3676, Lng: 4.9041}
+	berlin    = Point{Lat: 52.5200, Lng: 13.4050}
+	newYork   = Point{Lat: 40.7128, Lng: -74.0060}
+)
+
+func TestDistanceKm(t *testing.T) {
+	testCases := []struct {
+		name string
+		a, b Point
+		want float64
+	}{
+		{"same point", london, london, 0},
+		{"london to paris", london, paris, 343.5},
+		{"london to new york", london, newYork, 5570.2},
+		{"across the antimeridian", Point{Lat: 0, Lng: 179.5}, Point{Lat: 0, Lng: -179.5}, 111.2},
+		{"pole to pole", Point{Lat: 90, Lng: 0}, Point{Lat: -90, Lng: 0}, math.Pi * EarthRadiusKm},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := DistanceKm(tc.a, tc.b)
+			if math.Abs(got-tc.want) > 0.5 {
+				t.Errorf("DistanceKm() = %.1f, want %.1f", got, tc.want)
+			}
+			if back := DistanceKm(tc.b, tc.a); math.Abs(back-got) > 1e-9 {
+				t.Errorf("DistanceKm() is not symmetric: %f and %f", got, back)
+			}
+		})
+	}
+}
+
+func TestBoundingBox(t *testing.T) {
+	testCases := []struct {
+		name     string
+		center   Point
+		radiusKm float64
+		wholeLng bool // the box spans every longitude
+	}{
+		{"london", london, 50, false},
+		{"equator", Point{Lat: 0, Lng: 0}, 500, false},
+		{"southern hemisphere", Point{Lat: -33.8688, Lng: 151.2093}, 100, false},
+		{"near the north pole", Point{Lat: 89.9, Lng: 10}, 50, true},
+		{"near the south pole", Point{Lat: -89.5, Lng: -45}, 100, true},
+		{"near the antimeridian", Point{Lat: -17.7, Lng: 179.9}, 100, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sw, ne := BoundingBox(tc.center, tc.radiusKm)
+
+			if sw.Lat < -90 || ne.Lat > 90 || sw.Lat >= ne.Lat {
+				t.Fatalf("invalid latitudes %.4f..%.4f", sw.Lat, ne.Lat)
+			}
+			if got := sw.Lng == -180 && ne.Lng == 180; got != tc.wholeLng {
+				t.Fatalf("box %v..%v spans every longitude = %v, want %v", sw, ne, got, tc.wholeLng)
+			}
+
+			// Every point on the circle of the radius must be inside the box
+			for bearing := 0.0; bearing < 360; bearing += 5 {
+				point := destination(tc.center, bearing, tc.radiusKm*0.999)
+				if !inBox(point, sw, ne) {
+					t.Errorf("point %v at bearing %.0f is outside the box %v..%v", point, bearing, sw, ne)
+				}
+			}
+		})
+	}
+}
+
+// TestNearbyOrdering checks the steps of ListVenuesNearby: candidates are
+// narrowed down to the bounding box, then kept within the radius and
+// ordered by haversine distance.
+func TestNearbyOrdering(t *testing.T) {
+	venues := map[string]Point{
+		"berlin":    berlin,
+		"amsterdam": amsterdam,
+		"paris":     paris,
+		"brussels":  brussels,
+		"new york":  newYork,
+		"london":    london,
+	}
+
+	testCases := []struct {
+		name     string
+		center   Point
+		radiusKm float64
+		want     []string
+	}{
+		{"from london", london, 400, []string{"london", "brussels", "paris", "amsterdam"}},
+		{"from brussels", brussels, 300, []string{"brussels", "amsterdam", "paris"}},
+		{"from berlin", berlin, 1000, []string{"berlin", "amsterdam", "brussels", "paris", "london"}},
+		{"nothing in range", Point{Lat: 0, Lng: 0}, 100, []string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sw, ne := BoundingBox(tc.center, tc.radiusKm)
+
+			got := []string{}
+			distances := make(map[string]float64)
+			for name, point := range venues {
+				if !inBox(point, sw, ne) {
+					continue
+				}
+				if distance := DistanceKm(tc.center, point); distance <= tc.radiusKm {
+					got = append(got, name)
+					distances[name] = distance
+				}
+			}
+			sort.Slice(got, func(i, j int) bool {
+				return distances[got[i]] < distances[got[j]]
+			})
+
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("got %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func inBox(point, sw, ne Point) bool {
+	return point.Lat >= sw.Lat && point.Lat <= ne.Lat &&
+		point.Lng >= sw.Lng && point.Lng <= ne.Lng
+}
+
+// destination returns the point distanceKm away from start along the bearing,
+// in degrees clockwise from north.
+func destination(start Point, bearing, distanceKm float64) Point {
+	lat1 := start.Lat * math.Pi / 180
+	lng1 := start.Lng * math.Pi / 180
+	theta := bearing * math.Pi / 180
+	delta := distanceKm / EarthRadiusKm
+
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta))
+	lng2 := lng1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1), math.Cos(delta)-math.Sin(lat1)*math.Sin(lat2))
+
+	lng := math.Mod(lng2*180/math.Pi+540, 360) - 180
+	return Point{Lat: lat2 * 180 / math.Pi, Lng: lng}
+}
@@ -0,0 +1,69 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrNotFound is returned when an address cannot be geocoded.
+var ErrNotFound = errors.New("address not found")
+
+// Geocoder turns a free-text address into coordinates.
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (Point, error)
+}
+
+// NopGeocoder is used when no geocoder is configured. It finds nothing.
+type NopGeocoder struct{}
+
+// Geocode always returns ErrNotFound.
+func (NopGeocoder) Geocode(ctx context.Context, address string) (Point, error) {
+	return Point{}, ErrNotFound
+}
+
+// FixtureGeocoder resolves addresses from a fixed table, without any network
+// access. It is meant for tests and offline development.
+type FixtureGeocoder struct {
+	points map[string]Point
+}
+
+// NewFixtureGeocoder creates a FixtureGeocoder from a table of addresses.
+func NewFixtureGeocoder(points map[string]Point) *FixtureGeocoder {
+	geocoder := &FixtureGeocoder{points: make(map[string]Point, len(points))}
+	for address, point := range points {
+		geocoder.points[normalize(address)] = point
+	}
+	return geocoder
+}
+
+// LoadFixtureGeocoder reads the table of a FixtureGeocoder from a JSON file
+// mapping addresses to {"lat": ..., "lng": ...} objects.
+func LoadFixtureGeocoder(path string) (*FixtureGeocoder, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read geocoder fixtures: %w", err)
+	}
+
+	var points map[string]Point
+	if err := json.Unmarshal(data, &points); err != nil {
+		return nil, fmt.Errorf("cannot parse geocoder fixtures: %w", err)
+	}
+	return NewFixtureGeocoder(points), nil
+}
+
+// Geocode looks the address up, ignoring case and extra whitespace.
+func (geocoder *FixtureGeocoder) Geocode(ctx context.Context, address string) (Point, error) {
+	point, ok := geocoder.points[normalize(address)]
+	if !ok {
+		return Point{}, ErrNotFound
+	}
+	return point, nil
+}
+
+func normalize(address string) string {
+	return strings.Join(strings.Fields(strings.ToLower(address)), " ")
+}
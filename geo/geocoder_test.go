@@ -0,0 +1,74 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFixtureGeocoder(t *testing.T) {
+	geocoder := NewFixtureGeocoder(map[string]Point{
+		"10 Downing Street, London": {Lat: 51.5034, Lng: -0.1276},
+	})
+
+	testCases := []struct {
+		name    string
+		address string
+		want    Point
+		wantErr error
+	}{
+		{"exact", "10 Downing Street, London", Point{Lat: 51.5034, Lng: -0.1276}, nil},
+		{"case and whitespace", "  10 downing  STREET,\tlondon ", Point{Lat: 51.5034, Lng: -0.1276}, nil},
+		{"unknown", "1 Nowhere Lane", Point{}, ErrNotFound},
+		{"empty", "", Point{}, ErrNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := geocoder.Geocode(context.Background(), tc.address)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Geocode(%q) error = %v, want %v", tc.address, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("Geocode(%q) = %v, want %v", tc.address, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadFixtureGeocoder(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.json")
+	if err := os.WriteFile(valid, []byte(`{"Eiffel Tower, Paris": {"lat": 48.8584, "lng": 2.2945}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`["not", "a", "table"]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	geocoder, err := LoadFixtureGeocoder(valid)
+	if err != nil {
+		t.Fatalf("LoadFixtureGeocoder() error: %v", err)
+	}
+	point, err := geocoder.Geocode(context.Background(), "eiffel tower, paris")
+	if err != nil || point != (Point{Lat: 48.8584, Lng: 2.2945}) {
+		t.Errorf("Geocode() = %v, %v", point, err)
+	}
+
+	if _, err := LoadFixtureGeocoder(invalid); err == nil {
+		t.Error("LoadFixtureGeocoder() accepted a file that is not a table")
+	}
+	if _, err := LoadFixtureGeocoder(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("LoadFixtureGeocoder() accepted a missing file")
+	}
+}
+
+func TestNopGeocoder(t *testing.T) {
+	if _, err := (NopGeocoder{}).Geocode(context.Background(), "anywhere"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Geocode() error = %v, want %v", err, ErrNotFound)
+	}
+}
@@ -0,0 +1,180 @@
+// Package invoice renders invoices and receipts for purchases as PDF
+// documents, without depending on anything outside the standard library.
+package invoice
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/tedobanks/tabularasa_backend/money"
+)
+
+// Kind is the type of document rendered.
+type Kind string
+
+// Document kinds. An invoice asks for payment of what was sold; a receipt
+// confirms that it was paid.
+const (
+	KindInvoice Kind = "invoice"
+	KindReceipt Kind = "receipt"
+)
+
+// Party is the seller or the buyer named on a document.
+type Party struct {
+	Name    string
+	Address string
+	Country string
+}
+
+// Line is an item sold, or a tax charged on the items when Tax is set.
+type Line struct {
+	Description string
+	Quantity    int32
+	UnitAmount  money.Money
+	Amount      money.Money
+	Tax         bool
+}
+
+// Document holds everything printed on an invoice or receipt.
+type Document struct {
+	Kind             Kind
+	Number           string
+	IssuedAt         time.Time
+	PaidAt           time.Time // printed on receipts
+	PaymentReference string    // printed on receipts
+	Seller           Party
+	Buyer            Party
+	Lines            []Line
+	Subtotal         money.Money
+	Tax              money.Money
+	Total            money.Money
+}
+
+// Layout of the page, in points.
+const (
+	margin     = 50
+	rowHeight  = 18
+	tableTop   = 600 // baseline of the table header on the first page
+	tableStart = 780 // baseline of the table header on following pages
+	tableEnd   = 140 // lowest item row, leaving room for the totals
+
+	qtyRight    = 350
+	unitRight   = 450
+	amountRight = pageWidth - margin
+	descWidth   = 230
+)
+
+const dateLayout = "2 Jan 2006"
+
+// Render writes the document as a PDF. Items that do not fit on the first
+// page continue on the next.
+func Render(w io.Writer, doc Document) error {
+	title := "Invoice"
+	if doc.Kind == KindReceipt {
+		title = "Receipt"
+	}
+
+	pdf := &pdfDocument{title: fmt.Sprintf("%s %s", title, doc.Number)}
+	p := pdf.addPage()
+
+	// Heading and document details
+	p.text(margin, 780, bold, 24, title)
+	details := [][2]string{
+		{title + " no.", doc.Number},
+		{"Issued", doc.IssuedAt.Format(dateLayout)},
+	}
+	if doc.Kind == KindReceipt {
+		details = append(details, [2]string{"Paid", doc.PaidAt.Format(dateLayout)})
+		if doc.PaymentReference != "" {
+			details = append(details, [2]string{"Payment ref.", doc.PaymentReference})
+		}
+	}
+	for i, detail := range details {
+		y := float64(785 - 14*i)
+		p.textRight(unitRight, y, bold, 9, detail[0])
+		p.textRight(amountRight, y, regular, 9, detail[1])
+	}
+
+	// Seller and buyer
+	drawParty(p, margin, 700, "From", doc.Seller)
+	drawParty(p, 320, 700, "Bill to", doc.Buyer)
+
+	// Items, then the taxes charged on them in the totals
+	y := drawTableHeader(p, tableTop)
+	var taxes []Line
+	for _, line := range doc.Lines {
+		if line.Tax {
+			taxes = append(taxes, line)
+			continue
+		}
+
+		if y < tableEnd {
+			p = pdf.addPage()
+			y = drawTableHeader(p, tableStart)
+		}
+
+		p.text(margin+5, y, regular, 10, fitText(regular, 10, line.Description, descWidth))
+		p.textRight(qtyRight, y, regular, 10, fmt.Sprint(line.Quantity))
+		p.textRight(unitRight, y, regular, 10, line.UnitAmount.String())
+		p.textRight(amountRight, y, regular, 10, line.Amount.String())
+		y -= rowHeight
+	}
+
+	if y-float64(rowHeight*(len(taxes)+3)) < margin {
+		p = pdf.addPage()
+		y = tableStart
+	}
+
+	p.line(margin, y+rowHeight/2, amountRight, y+rowHeight/2, 0.5)
+	y -= rowHeight / 2
+
+	drawTotal(p, y, regular, "Subtotal", doc.Subtotal)
+	y -= rowHeight
+	if len(taxes) == 0 {
+		drawTotal(p, y, regular, "Tax", doc.Tax)
+		y -= rowHeight
+	}
+	for _, tax := range taxes {
+		drawTotal(p, y, regular, tax.Description, tax.Amount)
+		y -= rowHeight
+	}
+	drawTotal(p, y, bold, "Total", doc.Total)
+
+	if doc.Kind == KindReceipt {
+		p.text(margin, y, bold, 14, "PAID")
+	}
+
+	return pdf.writeTo(w)
+}
+
+// drawParty draws the name and address of a seller or buyer under a label.
+func drawParty(p *page, x, y float64, label string, party Party) {
+	p.text(x, y, bold, 9, label)
+	y -= 16
+	p.text(x, y, bold, 11, fitText(bold, 11, party.Name, descWidth))
+	for _, line := range []string{party.Address, party.Country} {
+		if line == "" {
+			continue
+		}
+		y -= 14
+		p.text(x, y, regular, 10, fitText(regular, 10, line, descWidth))
+	}
+}
+
+// drawTableHeader draws the column headings of the item table and returns
+// the baseline of its first row.
+func drawTableHeader(p *page, y float64) float64 {
+	p.fillRect(margin, y-6, amountRight-margin, rowHeight, 0.92)
+	p.text(margin+5, y, bold, 10, "Description")
+	p.textRight(qtyRight, y, bold, 10, "Qty")
+	p.textRight(unitRight, y, bold, 10, "Unit price")
+	p.textRight(amountRight, y, bold, 10, "Amount")
+	return y - rowHeight - 4
+}
+
+// drawTotal draws a labelled amount in the totals below the item table.
+func drawTotal(p *page, y float64, f font, label string, amount money.Money) {
+	p.textRight(unitRight, y, f, 10, label)
+	p.textRight(amountRight, y, f, 10, amount.String())
+}
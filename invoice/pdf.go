@@ -0,0 +1,220 @@
+package invoice
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page size in points.
+const (
+	pageWidth  = 595
+	pageHeight = 842
+)
+
+// font is one of the standard PDF fonts, which readers provide themselves,
+// so no font data has to be embedded.
+type font int
+
+const (
+	regular font = iota
+	bold
+)
+
+var fontNames = [...]string{regular: "Helvetica", bold: "Helvetica-Bold"}
+
+// glyphWidths are the widths of the printable ASCII characters from space to
+// tilde, in thousandths of the font size, from the fonts' Adobe metrics.
+var glyphWidths = [...][95]int{
+	regular: {
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	},
+	bold: {
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+}
+
+// defaultGlyphWidth is used for characters outside printable ASCII.
+const defaultGlyphWidth = 556
+
+// textWidth returns the width of s in points when set in f at size.
+func textWidth(f font, size float64, s string) float64 {
+	total := 0
+	for _, r := range s {
+		if r >= ' ' && r <= '~' {
+			total += glyphWidths[f][r-' ']
+		} else {
+			total += defaultGlyphWidth
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// fitText shortens s with an ellipsis until it fits in width points.
+func fitText(f font, size float64, s string, width float64) string {
+	if textWidth(f, size, s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		if short := string(runes) + "..."; textWidth(f, size, short) <= width {
+			return short
+		}
+	}
+	return ""
+}
+
+// winAnsiExtras maps the characters WinAnsiEncoding places in 0x80-0x9F,
+// where Latin-1 has control codes, to their codes.
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encodeText converts s to WinAnsiEncoding, the encoding the fonts are set
+// up with, and escapes it as a PDF string. Characters WinAnsiEncoding cannot
+// represent become question marks.
+func encodeText(s string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r >= ' ' && r <= '~', r >= 0xA0 && r <= 0xFF:
+			b.WriteByte(byte(r))
+		default:
+			c, ok := winAnsiExtras[r]
+			if !ok {
+				c = '?'
+			}
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// page collects the drawing operators of one page. Coordinates are in
+// points from the bottom left corner.
+type page struct {
+	content bytes.Buffer
+}
+
+// text draws s with its baseline starting at (x, y).
+func (p *page) text(x, y float64, f font, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /F%d %.2f Tf %.2f %.2f Td %s Tj ET\n", f+1, size, x, y, encodeText(s))
+}
+
+// textRight draws s with its baseline ending at (x, y).
+func (p *page) textRight(x, y float64, f font, size float64, s string) {
+	p.text(x-textWidth(f, size, s), y, f, size, s)
+}
+
+// line strokes a line from (x1, y1) to (x2, y2).
+func (p *page) line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+// fillRect fills a rectangle in a shade of grey, 0 being black and 1 white.
+func (p *page) fillRect(x, y, width, height, grey float64) {
+	fmt.Fprintf(&p.content, "%.2f g %.2f %.2f %.2f %.2f re f 0 g\n", grey, x, y, width, height)
+}
+
+// pdfDocument is a minimal PDF 1.4 writer for text and lines on A4 pages.
+type pdfDocument struct {
+	title string
+	pages []*page
+}
+
+// addPage starts a new page.
+func (d *pdfDocument) addPage() *page {
+	p := &page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// writeTo writes the document: the catalog, page tree, fonts and info
+// dictionary, then each page and its content stream, then the
+// cross-reference table locating every object.
+func (d *pdfDocument) writeTo(w io.Writer) error {
+	out := &countingWriter{w: bufio.NewWriter(w)}
+	var offsets []int64
+
+	object := func(body string) {
+		offsets = append(offsets, out.n)
+		fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1 to 5 come first, so page i is object 6+2i and its content 7+2i
+	const firstPage = 6
+
+	fmt.Fprint(out, "%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+
+	for _, name := range fontNames {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+	}
+
+	object(fmt.Sprintf("<< /Title %s /Producer (tabularasa) >>", encodeText(d.title)))
+
+	for i, p := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()))
+	}
+
+	xref := out.n
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	if out.err != nil {
+		return out.err
+	}
+	return out.w.Flush()
+}
+
+// countingWriter tracks the byte offset of the output for the
+// cross-reference table, and remembers the first write error.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(b []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(b)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
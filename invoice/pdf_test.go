@@ -0,0 +1,27 @@
+package invoice
+
+import "testing"
+
+func TestEncodeText(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"ascii", "Total 12.50", "(Total 12.50)"},
+		{"escapes", `a (b) \c`, `(a \(b\) \\c)`},
+		{"latin-1", "Café £5", "(Caf\xe9 \xa35)"},
+		{"euro", "€10", "(\x8010)"},
+		{"quotes and dashes", "“Hello” – ‘world’ — …", "(\x93Hello\x94 \x96 \x91world\x92 \x97 \x85)"},
+		{"other extras", "‚ƒ„†‡ˆ‰Š‹ŒŽ•˜™š›œžŸ", "(\x82\x83\x84\x86\x87\x88\x89\x8a\x8b\x8c\x8e\x95\x98\x99\x9a\x9b\x9c\x9e\x9f)"},
+		{"unsupported", "Łódź ✓", "(?\xf3d? ?)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := encodeText(tc.in); got != tc.want {
+				t.Errorf("encodeText(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
@@ -1,16 +1,37 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/tedobanks/tabularasa_backend/api"
+	"github.com/tedobanks/tabularasa_backend/db/migrate"
+	"github.com/tedobanks/tabularasa_backend/db/migrations"
 	db "github.com/tedobanks/tabularasa_backend/db/sqlc"
+	"github.com/tedobanks/tabularasa_backend/money"
 	"github.com/tedobanks/tabularasa_backend/util"
 
 	_ "github.com/lib/pq"
 )
 
+// expiryInterval is how often purchases left unpaid are expired.
+const expiryInterval = time.Minute
+
+const usage = `usage:
+  tabularasa_backend                   start the HTTP server
+  tabularasa_backend migrate up        apply every pending migration
+  tabularasa_backend migrate down [N]  revert the last N migrations (default 1)
+  tabularasa_backend migrate goto V    migrate up or down to version V
+  tabularasa_backend migrate force V   mark version V as applied after a manual fix
+  tabularasa_backend migrate status    show the current and pending versions
+  tabularasa_backend rates load FILE   load exchange rates from a base,quote,rate CSV file`
+
 func main() {
 	// Load configuration from .env or environment variables
 	config, err := util.LoadConfig(".")
@@ -32,12 +53,45 @@ func main() {
 	}
 	log.Println("Successfully connected to the database!")
 
-	// Create a new sqlc Queries instance
-	// This uses the New() function from your sqlc generated db.go file.
-	store := db.New(conn)
+	migrator, err := migrate.New(conn, migrations.FS)
+	if err != nil {
+		log.Fatal("cannot load migrations:", err)
+	}
+
+	// Create a new Store backed by the connection pool.
+	// It wraps the sqlc generated Queries and adds transaction support.
+	store := db.NewStore(conn)
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			if err := runMigrate(context.Background(), migrator, os.Args[2:]); err != nil {
+				log.Fatal("migrate: ", err)
+			}
+		case "rates":
+			if err := runRates(context.Background(), store, os.Args[2:]); err != nil {
+				log.Fatal("rates: ", err)
+			}
+		default:
+			log.Fatal(usage)
+		}
+		return
+	}
+
+	if config.MigrateOnBoot {
+		if err := runMigrate(context.Background(), migrator, []string{"up"}); err != nil {
+			log.Fatal("cannot migrate db: ", err)
+		}
+	}
 
 	// Create a new Gin server and pass the store
-	server := api.NewServer(store) // Pass the *db.Queries directly
+	server, err := api.NewServer(config, store)
+	if err != nil {
+		log.Fatal("cannot create server:", err)
+	}
+
+	// Give the places held by abandoned checkouts back
+	go expirePurchases(context.Background(), store, expiryInterval, config.WaitlistHold)
 
 	// Start the HTTP server
 	log.Printf("Starting server at %s", config.ServerAddress)
@@ -46,3 +100,131 @@ func main() {
 		log.Fatal("cannot start server:", err)
 	}
 }
+
+// runMigrate executes a migrate subcommand.
+func runMigrate(ctx context.Context, migrator *migrate.Migrator, args []string) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	var err error
+	switch args[0] {
+	case "up":
+		err = migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps: %q", args[1])
+			}
+		}
+		err = migrator.Down(ctx, steps)
+	case "goto", "force":
+		if len(args) < 2 {
+			return errors.New(usage)
+		}
+		version, perr := strconv.ParseUint(args[1], 10, 64)
+		if perr != nil {
+			return fmt.Errorf("invalid version: %q", args[1])
+		}
+		if args[0] == "goto" {
+			err = migrator.Goto(ctx, version)
+		} else {
+			err = migrator.Force(ctx, version)
+		}
+	case "status":
+		return printStatus(ctx, migrator)
+	default:
+		return errors.New(usage)
+	}
+
+	if errors.Is(err, migrate.ErrNoChange) {
+		log.Println("migrate: no change")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return printStatus(ctx, migrator)
+}
+
+// runRates executes a rates subcommand.
+func runRates(ctx context.Context, store db.Store, args []string) error {
+	if len(args) != 2 || args[0] != "load" {
+		return errors.New(usage)
+	}
+
+	file, err := os.Open(args[1])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	rates, err := money.ReadRatesCSV(file)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[1], err)
+	}
+
+	arg := make([]db.UpsertExchangeRateParams, 0, len(rates))
+	for _, rate := range rates {
+		arg = append(arg, db.UpsertExchangeRateParams{
+			Base:  string(rate.Base),
+			Quote: string(rate.Quote),
+			Rate:  rate.String(),
+		})
+	}
+
+	if err := store.LoadExchangeRatesTx(ctx, arg); err != nil {
+		return err
+	}
+	log.Printf("rates: loaded %d exchange rates", len(rates))
+	return nil
+}
+
+// expirePurchases fails the purchases whose checkout expired unpaid and
+// expires the waitlist holds that lapsed every interval, passing the places
+// they held to the next waiting profiles for holdFor.
+func expirePurchases(ctx context.Context, store db.Store, interval, holdFor time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := store.ExpirePendingPurchasesTx(ctx, holdFor)
+			if err != nil {
+				log.Println("cannot expire purchases:", err)
+			} else if len(expired) > 0 {
+				log.Printf("expired %d unpaid purchases", len(expired))
+			}
+
+			lapsed, err := store.ExpireWaitlistHoldsTx(ctx, holdFor)
+			if err != nil {
+				log.Println("cannot expire waitlist holds:", err)
+			} else if len(lapsed) > 0 {
+				log.Printf("expired %d lapsed waitlist holds", len(lapsed))
+			}
+		}
+	}
+}
+
+// printStatus logs the current schema version and the pending migrations.
+func printStatus(ctx context.Context, migrator *migrate.Migrator) error {
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	dirty := ""
+	if status.Dirty {
+		dirty = " (dirty)"
+	}
+	log.Printf("migrate: schema version %d%s", status.Version, dirty)
+	for _, migration := range status.Pending() {
+		log.Printf("migrate: pending %06d_%s", migration.Version, migration.Name)
+	}
+	return nil
+}
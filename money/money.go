@@ -0,0 +1,89 @@
+// Package money represents prices as an integer amount of minor units
+// (cents, pence, ...) of an ISO 4217 currency, and converts them between
+// currencies using exchange rates.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors returned when working with money.
+var (
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrCurrencyMismatch = errors.New("currencies do not match")
+)
+
+// Currency is an ISO 4217 currency code.
+type Currency string
+
+// minorUnits gives the number of decimal places of each supported currency.
+var minorUnits = map[Currency]int{
+	"AED": 2, "AUD": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2, "CNY": 2,
+	"DKK": 2, "EGP": 2, "EUR": 2, "GBP": 2, "GHS": 2, "HKD": 2, "INR": 2,
+	"JPY": 0, "KES": 2, "KRW": 0, "KWD": 3, "MAD": 2, "MXN": 2, "NGN": 2,
+	"NOK": 2, "NZD": 2, "OMR": 3, "PLN": 2, "RWF": 0, "SEK": 2, "SGD": 2,
+	"TND": 3, "TRY": 2, "TZS": 2, "UGX": 0, "USD": 2, "XAF": 0, "XOF": 0,
+	"ZAR": 2,
+}
+
+// ParseCurrency checks that code is a supported currency, ignoring case
+// and surrounding spaces.
+func ParseCurrency(code string) (Currency, error) {
+	currency := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if _, ok := minorUnits[currency]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+	return currency, nil
+}
+
+// Exponent returns the number of decimal places of the currency's minor unit.
+func (currency Currency) Exponent() int {
+	return minorUnits[currency]
+}
+
+// Money is an amount in minor units of a currency.
+type Money struct {
+	Amount   int64    `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+// New creates an amount of money.
+func New(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Add returns the sum of two amounts in the same currency.
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return New(m.Amount+other.Amount, m.Currency), nil
+}
+
+// String formats the amount in major units, such as "12.50 USD".
+func (m Money) String() string {
+	exponent := m.Currency.Exponent()
+	if exponent == 0 {
+		return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+	}
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	scale := pow10(exponent)
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/scale, exponent, amount%scale, m.Currency)
+}
+
+func pow10(n int) int64 {
+	result := int64(1)
+	for i := 0; i < n; i++ {
+		result *= 10
+	}
+	return result
+}
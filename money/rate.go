@@ -0,0 +1,125 @@
+package money
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+)
+
+// rateDecimals is the precision rates are stored with in exchange_rates.rate.
+const rateDecimals = 10
+
+// Rate is the price of one unit of Base expressed in Quote.
+type Rate struct {
+	Base  Currency
+	Quote Currency
+	Value *big.Rat
+}
+
+// ParseRate parses a positive decimal exchange rate between two currencies.
+func ParseRate(base, quote, value string) (Rate, error) {
+	baseCurrency, err := ParseCurrency(base)
+	if err != nil {
+		return Rate{}, err
+	}
+	quoteCurrency, err := ParseCurrency(quote)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	rate, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok || rate.Sign() <= 0 {
+		return Rate{}, fmt.Errorf("invalid exchange rate %q", value)
+	}
+	return Rate{Base: baseCurrency, Quote: quoteCurrency, Value: rate}, nil
+}
+
+// String formats the rate value with the precision it is stored with.
+func (rate Rate) String() string {
+	return rate.Value.FloatString(rateDecimals)
+}
+
+// Invert returns the rate from Quote back to Base.
+func (rate Rate) Invert() Rate {
+	return Rate{Base: rate.Quote, Quote: rate.Base, Value: new(big.Rat).Inv(rate.Value)}
+}
+
+// Convert converts an amount in Base into Quote, rounding to the nearest
+// minor unit of Quote, halves away from zero.
+func (rate Rate) Convert(m Money) (Money, error) {
+	if m.Currency != rate.Base {
+		return Money{}, fmt.Errorf("%w: cannot convert %s with a %s rate", ErrCurrencyMismatch, m.Currency, rate.Base)
+	}
+
+	// minor(Quote) = minor(Base) * rate * 10^exp(Quote) / 10^exp(Base)
+	amount := new(big.Rat).SetInt64(m.Amount)
+	amount.Mul(amount, rate.Value)
+	amount.Mul(amount, new(big.Rat).SetInt64(pow10(rate.Quote.Exponent())))
+	amount.Quo(amount, new(big.Rat).SetInt64(pow10(rate.Base.Exponent())))
+
+	return NewFromRat(amount, rate.Quote), nil
+}
+
+// NewFromRat creates an amount of money from a fractional number of minor
+// units, rounding to the nearest minor unit, halves away from zero.
+func NewFromRat(amount *big.Rat, currency Currency) Money {
+	return New(round(amount), currency)
+}
+
+// round rounds x to the nearest integer, halves away from zero.
+func round(x *big.Rat) int64 {
+	num := new(big.Int).Abs(x.Num())
+	den := x.Denom()
+
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Mul(rem, big.NewInt(2)).Cmp(den) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if x.Sign() < 0 {
+		quo.Neg(quo)
+	}
+	return quo.Int64()
+}
+
+// ReadRatesCSV reads exchange rates from CSV with a base,quote,rate header,
+// such as:
+//
+//	base,quote,rate
+//	USD,EUR,0.92
+func ReadRatesCSV(r io.Reader) ([]Rate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("exchange rates file is empty")
+		}
+		return nil, err
+	}
+	if strings.ToLower(strings.Join(header, ",")) != "base,quote,rate" {
+		return nil, fmt.Errorf("exchange rates header must be base,quote,rate, got %q", strings.Join(header, ","))
+	}
+
+	var rates []Rate
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rates, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		rate, err := ParseRate(record[0], record[1], record[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rates = append(rates, rate)
+	}
+}
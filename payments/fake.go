@@ -0,0 +1,203 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// FakeSignatureHeader carries the webhook signature of the fake provider.
+const FakeSignatureHeader = "Fake-Signature"
+
+// FakePayment is a payment held by the fake provider.
+type FakePayment struct {
+	ID         string
+	CheckoutID string
+	Amount     int64
+	Currency   string
+	Captured   bool
+	Voided     bool
+	Refunded   int64
+}
+
+// FakeProvider is an in-memory provider for tests and local development.
+// No money moves: checkouts are completed or failed by calling Complete or
+// Fail, which return the event the real provider would send by webhook.
+// Webhooks are signed with HMAC-SHA256 of the payload under the secret.
+type FakeProvider struct {
+	secret []byte
+
+	mu        sync.Mutex
+	seq       int
+	checkouts map[string]CheckoutParams
+	payments  map[string]*FakePayment
+}
+
+// NewFakeProvider creates a FakeProvider signing webhooks with secret.
+func NewFakeProvider(secret string) *FakeProvider {
+	return &FakeProvider{
+		secret:    []byte(secret),
+		checkouts: make(map[string]CheckoutParams),
+		payments:  make(map[string]*FakePayment),
+	}
+}
+
+// Name returns "fake".
+func (provider *FakeProvider) Name() string {
+	return "fake"
+}
+
+// nextID returns a new identifier with the given prefix. The caller must hold mu.
+func (provider *FakeProvider) nextID(prefix string) string {
+	provider.seq++
+	return fmt.Sprintf("%s_fake_%d", prefix, provider.seq)
+}
+
+// CreateCheckout records the checkout and returns a URL that goes nowhere.
+func (provider *FakeProvider) CreateCheckout(ctx context.Context, arg CheckoutParams) (Checkout, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	id := provider.nextID("cs")
+	provider.checkouts[id] = arg
+	return Checkout{ID: id, URL: "https://payments.invalid/checkout/" + id}, nil
+}
+
+// Complete simulates the buyer paying a checkout. The payment is authorized
+// but not captured.
+func (provider *FakeProvider) Complete(checkoutID string) (Event, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	checkout, ok := provider.checkouts[checkoutID]
+	if !ok {
+		return Event{}, ErrNotFound
+	}
+
+	payment := &FakePayment{
+		ID:         provider.nextID("pay"),
+		CheckoutID: checkoutID,
+		Amount:     checkout.Amount,
+		Currency:   checkout.Currency,
+	}
+	provider.payments[payment.ID] = payment
+
+	return Event{
+		ID:         provider.nextID("evt"),
+		Type:       EventCheckoutCompleted,
+		CheckoutID: checkoutID,
+		PaymentID:  payment.ID,
+	}, nil
+}
+
+// Fail simulates the buyer's payment for a checkout being declined.
+func (provider *FakeProvider) Fail(checkoutID string) (Event, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	if _, ok := provider.checkouts[checkoutID]; !ok {
+		return Event{}, ErrNotFound
+	}
+
+	return Event{
+		ID:         provider.nextID("evt"),
+		Type:       EventCheckoutFailed,
+		CheckoutID: checkoutID,
+	}, nil
+}
+
+// Payment returns a copy of a payment, for inspection in tests.
+func (provider *FakeProvider) Payment(paymentID string) (FakePayment, bool) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	payment, ok := provider.payments[paymentID]
+	if !ok {
+		return FakePayment{}, false
+	}
+	return *payment, true
+}
+
+// Capture marks an authorized payment as captured.
+func (provider *FakeProvider) Capture(ctx context.Context, paymentID string) error {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	payment, ok := provider.payments[paymentID]
+	if !ok {
+		return ErrNotFound
+	}
+	if payment.Voided {
+		return ErrVoided
+	}
+	payment.Captured = true
+	return nil
+}
+
+// Void marks an authorized payment as voided. Captured payments cannot be
+// voided and must be refunded instead.
+func (provider *FakeProvider) Void(ctx context.Context, paymentID string) error {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	payment, ok := provider.payments[paymentID]
+	if !ok || payment.Captured {
+		return ErrNotFound
+	}
+	payment.Voided = true
+	return nil
+}
+
+// Refund returns part of a captured payment.
+func (provider *FakeProvider) Refund(ctx context.Context, arg RefundParams) (Refund, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	payment, ok := provider.payments[arg.PaymentID]
+	if !ok || !payment.Captured {
+		return Refund{}, ErrNotFound
+	}
+	if arg.Amount <= 0 || payment.Refunded+arg.Amount > payment.Amount {
+		return Refund{}, ErrRefundTooLarge
+	}
+
+	payment.Refunded += arg.Amount
+	return Refund{ID: provider.nextID("re"), Amount: arg.Amount}, nil
+}
+
+// WebhookRequest encodes and signs an event the way VerifyWebhook expects it.
+func (provider *FakeProvider) WebhookRequest(event Event) ([]byte, http.Header, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := http.Header{}
+	header.Set(FakeSignatureHeader, provider.sign(payload))
+	return payload, header, nil
+}
+
+// VerifyWebhook checks the HMAC signature of the payload and decodes the event.
+func (provider *FakeProvider) VerifyWebhook(payload []byte, header http.Header) (Event, error) {
+	signature := header.Get(FakeSignatureHeader)
+	if !hmac.Equal([]byte(signature), []byte(provider.sign(payload))) {
+		return Event{}, ErrInvalidSignature
+	}
+
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return Event{}, fmt.Errorf("cannot decode webhook event: %w", err)
+	}
+	return event, nil
+}
+
+func (provider *FakeProvider) sign(payload []byte) string {
+	mac := hmac.New(sha256.New, provider.secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
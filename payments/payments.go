@@ -0,0 +1,86 @@
+// Package payments takes money through an external payment provider.
+//
+// A purchase is paid by sending the buyer to a checkout created with the
+// provider. The provider reports the outcome through a signed webhook; a
+// completed checkout leaves an authorized payment that still has to be
+// captured.
+package payments
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Errors returned by providers.
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrNotFound         = errors.New("payment not found")
+	ErrRefundTooLarge   = errors.New("refund exceeds the captured amount")
+	ErrVoided           = errors.New("payment was voided")
+)
+
+// EventType is the kind of a webhook event.
+type EventType string
+
+// Webhook event types.
+const (
+	// EventCheckoutCompleted means the buyer authorized the payment.
+	EventCheckoutCompleted EventType = "checkout.completed"
+	// EventCheckoutFailed means the payment was declined or abandoned.
+	EventCheckoutFailed EventType = "checkout.failed"
+)
+
+// CheckoutParams describes what the buyer is asked to pay.
+type CheckoutParams struct {
+	Reference   string // our reference, echoed back by the provider
+	Description string
+	Amount      int64  // in minor units of Currency
+	Currency    string // ISO 4217 code
+}
+
+// Checkout is a payment page created with the provider.
+type Checkout struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// RefundParams describes a refund of a captured payment.
+type RefundParams struct {
+	PaymentID string
+	Amount    int64 // in minor units of the payment's currency
+	Reason    string
+}
+
+// Refund is a refund issued by the provider.
+type Refund struct {
+	ID     string `json:"id"`
+	Amount int64  `json:"amount"`
+}
+
+// Event is a verified webhook event.
+type Event struct {
+	ID         string    `json:"id"`
+	Type       EventType `json:"type"`
+	CheckoutID string    `json:"checkout_id"`
+	PaymentID  string    `json:"payment_id,omitempty"`
+}
+
+// Provider is a payment provider.
+type Provider interface {
+	// Name identifies the provider in purchases.provider.
+	Name() string
+	// CreateCheckout creates a checkout for the buyer to pay.
+	CreateCheckout(ctx context.Context, arg CheckoutParams) (Checkout, error)
+	// Capture collects an authorized payment. Capturing a payment twice is
+	// not an error.
+	Capture(ctx context.Context, paymentID string) error
+	// Void releases an authorized payment that will not be captured, so the
+	// buyer is never charged. Voiding a payment twice is not an error.
+	Void(ctx context.Context, paymentID string) error
+	// Refund returns part or all of a captured payment.
+	Refund(ctx context.Context, arg RefundParams) (Refund, error)
+	// VerifyWebhook checks the signature of a webhook request and decodes
+	// its event. It returns ErrInvalidSignature for forged requests.
+	VerifyWebhook(payload []byte, header http.Header) (Event, error)
+}
@@ -0,0 +1,261 @@
+// Package tax works out the taxes charged on purchases under per-country
+// rules: VAT or GST rates, prices that include the tax or have it added on
+// top, and services that are exempt.
+package tax
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/tedobanks/tabularasa_backend/money"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidRules is returned for a rules file that cannot be used.
+var ErrInvalidRules = errors.New("invalid tax rules")
+
+// Rules are the taxes of every jurisdiction. A nil *Rules charges no tax.
+// They are read from a YAML or JSON file such as:
+//
+//	jurisdictions:
+//	  - country: GB
+//	    names: [United Kingdom, UK]
+//	    taxes:
+//	      - name: VAT
+//	        rate: "20"
+//	        inclusive: true
+//	        exempt: [practitioner]
+//	  - country: CA
+//	    taxes:
+//	      - name: GST
+//	        rate: "5"
+//	  - country: CA
+//	    region: QC
+//	    taxes:
+//	      - name: QST
+//	        rate: "9.975"
+type Rules struct {
+	Jurisdictions []Jurisdiction `yaml:"jurisdictions"`
+}
+
+// Jurisdiction is a country, or a region of one, and the taxes it charges.
+// The taxes of a region are charged on top of those of its country.
+type Jurisdiction struct {
+	Country string   `yaml:"country"` // ISO 3166-1 alpha-2 code
+	Region  string   `yaml:"region"`  // ISO 3166-2 subdivision code without the country, such as "QC"
+	Names   []string `yaml:"names"`   // other spellings of the country
+	Taxes   []Tax    `yaml:"taxes"`
+}
+
+// Tax is one tax charged in a jurisdiction.
+type Tax struct {
+	Name      string   `yaml:"name"`
+	Rate      string   `yaml:"rate"`      // percent, such as "7.5"
+	Inclusive bool     `yaml:"inclusive"` // prices already include the tax
+	Exempt    []string `yaml:"exempt"`    // services not taxed, such as "practitioner" or "venue:hotel"
+
+	rate *big.Rat
+}
+
+// LoadRules reads and validates a YAML or JSON rules file.
+func LoadRules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read tax rules: %w", err)
+	}
+	return ParseRules(data)
+}
+
+// ParseRules parses and validates YAML or JSON rules.
+func ParseRules(data []byte) (*Rules, error) {
+	var rules Rules
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRules, err)
+	}
+
+	seen := make(map[string]bool)
+	for i := range rules.Jurisdictions {
+		jurisdiction := &rules.Jurisdictions[i]
+		jurisdiction.Country = strings.ToUpper(strings.TrimSpace(jurisdiction.Country))
+		jurisdiction.Region = strings.ToUpper(strings.TrimSpace(jurisdiction.Region))
+
+		if len(jurisdiction.Country) != 2 {
+			return nil, fmt.Errorf("%w: country %q is not an ISO 3166-1 alpha-2 code", ErrInvalidRules, jurisdiction.Country)
+		}
+		key := jurisdiction.Country + "-" + jurisdiction.Region
+		if seen[key] {
+			return nil, fmt.Errorf("%w: %s is listed more than once", ErrInvalidRules, strings.TrimSuffix(key, "-"))
+		}
+		seen[key] = true
+
+		for j := range jurisdiction.Taxes {
+			tax := &jurisdiction.Taxes[j]
+			if strings.TrimSpace(tax.Name) == "" {
+				return nil, fmt.Errorf("%w: %s has a tax without a name", ErrInvalidRules, jurisdiction.Country)
+			}
+
+			rate, ok := new(big.Rat).SetString(strings.TrimSpace(tax.Rate))
+			if !ok || rate.Sign() < 0 || rate.Cmp(big.NewRat(100, 1)) > 0 {
+				return nil, fmt.Errorf("%w: %s rate %q must be a percentage between 0 and 100", ErrInvalidRules, tax.Name, tax.Rate)
+			}
+			tax.rate = rate
+		}
+	}
+
+	return &rules, nil
+}
+
+// Supply is what a purchase pays for and where it is taxed.
+type Supply struct {
+	// Location is a country as an ISO 3166-1 alpha-2 code or one of the
+	// jurisdiction's names, or a region as an ISO 3166-2 code such as "CA-QC".
+	Location string
+	// Service is the kind of service: "venue", "practitioner" or "ticket".
+	Service string
+	// Type narrows the service down, such as the type of venue.
+	Type string
+}
+
+// Charge is a tax charged on a purchase.
+type Charge struct {
+	Name      string      `json:"name"`
+	Rate      string      `json:"rate"` // percent
+	Inclusive bool        `json:"inclusive"`
+	Amount    money.Money `json:"amount"`
+}
+
+// Result is a price broken down into its net amount and taxes.
+type Result struct {
+	Net   money.Money `json:"net"`
+	Taxes []Charge    `json:"taxes"`
+	Total money.Money `json:"total"`
+}
+
+// Tax returns the sum of the taxes.
+func (result Result) Tax() money.Money {
+	return money.New(result.Total.Amount-result.Net.Amount, result.Total.Currency)
+}
+
+// Apply works out the taxes on a price. Inclusive taxes are taken out of
+// the price and exclusive ones added to it, both at their rate of the net
+// amount. Free supplies and supplies nothing is charged on are returned
+// untaxed.
+func (rules *Rules) Apply(supply Supply, price money.Money) Result {
+	result := Result{Net: price, Total: price}
+
+	taxes := rules.taxes(supply)
+	if len(taxes) == 0 || price.Amount == 0 {
+		return result
+	}
+
+	// net = price / (1 + inclusive rates / 100)
+	inclusive := new(big.Rat)
+	for _, tax := range taxes {
+		if tax.Inclusive {
+			inclusive.Add(inclusive, tax.rate)
+		}
+	}
+	if inclusive.Sign() > 0 {
+		net := new(big.Rat).SetInt64(price.Amount * 100)
+		net.Quo(net, inclusive.Add(inclusive, big.NewRat(100, 1)))
+		result.Net = money.NewFromRat(net, price.Currency)
+	}
+
+	included := price.Amount - result.Net.Amount
+	lastInclusive := -1
+	for i, tax := range taxes {
+		amount := new(big.Rat).SetInt64(result.Net.Amount)
+		amount.Mul(amount, tax.rate)
+		amount.Quo(amount, big.NewRat(100, 1))
+
+		charge := Charge{
+			Name:      tax.Name,
+			Rate:      formatRate(tax.rate),
+			Inclusive: tax.Inclusive,
+			Amount:    money.NewFromRat(amount, price.Currency),
+		}
+		if tax.Inclusive {
+			included -= charge.Amount.Amount
+			lastInclusive = i
+		} else {
+			result.Total.Amount += charge.Amount.Amount
+		}
+		result.Taxes = append(result.Taxes, charge)
+	}
+
+	// Rounding each tax separately can leave the inclusive taxes a minor
+	// unit away from what was taken out of the price
+	if lastInclusive >= 0 {
+		result.Taxes[lastInclusive].Amount.Amount += included
+	}
+
+	return result
+}
+
+// taxes returns the taxes of the supply's country and region that it is not
+// exempt from.
+func (rules *Rules) taxes(supply Supply) []Tax {
+	if rules == nil {
+		return nil
+	}
+
+	location := strings.TrimSpace(supply.Location)
+	region := ""
+	if country, subdivision, ok := strings.Cut(location, "-"); ok && len(country) == 2 {
+		location, region = country, subdivision
+	}
+
+	var taxes []Tax
+	for _, jurisdiction := range rules.Jurisdictions {
+		if !jurisdiction.matches(location) {
+			continue
+		}
+		if jurisdiction.Region != "" && !strings.EqualFold(jurisdiction.Region, region) {
+			continue
+		}
+		for _, tax := range jurisdiction.Taxes {
+			if !tax.exempts(supply) {
+				taxes = append(taxes, tax)
+			}
+		}
+	}
+	return taxes
+}
+
+// matches reports whether location names the jurisdiction's country.
+func (jurisdiction Jurisdiction) matches(location string) bool {
+	if strings.EqualFold(jurisdiction.Country, location) {
+		return true
+	}
+	for _, name := range jurisdiction.Names {
+		if strings.EqualFold(strings.TrimSpace(name), location) {
+			return true
+		}
+	}
+	return false
+}
+
+// exempts reports whether the supply is exempt from the tax, either as a
+// whole service or as a type of it.
+func (tax Tax) exempts(supply Supply) bool {
+	for _, exempt := range tax.Exempt {
+		service, typ, narrowed := strings.Cut(exempt, ":")
+		if !strings.EqualFold(service, supply.Service) {
+			continue
+		}
+		if !narrowed || strings.EqualFold(typ, supply.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatRate formats a percentage without trailing zeros, such as "7.5".
+func formatRate(rate *big.Rat) string {
+	s := rate.FloatString(4)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,233 @@
+package tax
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tedobanks/tabularasa_backend/money"
+)
+
+const testRules = `
+jurisdictions:
+  - country: gb
+    names: [United Kingdom]
+    taxes:
+      - name: VAT
+        rate: "20"
+        inclusive: true
+        exempt: [practitioner]
+  - country: DE
+    names: [Germany]
+    taxes:
+      - name: MwSt
+        rate: "19"
+        inclusive: true
+        exempt: ["venue:hotel"]
+  - country: CA
+    taxes:
+      - name: GST
+        rate: "5"
+  - country: CA
+    region: qc
+    taxes:
+      - name: QST
+        rate: "9.975"
+  - country: MX
+    taxes:
+      - name: IVA
+        rate: "10"
+        inclusive: true
+      - name: Service
+        rate: "5"
+  - country: ZA
+    taxes:
+      - name: First
+        rate: "10"
+        inclusive: true
+      - name: Second
+        rate: "10"
+        inclusive: true
+`
+
+func TestApply(t *testing.T) {
+	rules, err := ParseRules([]byte(testRules))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name   string
+		rules  *Rules
+		supply Supply
+		price  money.Money
+		want   Result
+	}{
+		{
+			name:   "inclusive rate is taken out of the price",
+			rules:  rules,
+			supply: Supply{Location: "GB", Service: "venue"},
+			price:  money.New(1200, "GBP"),
+			want: Result{
+				Net:   money.New(1000, "GBP"),
+				Taxes: []Charge{{Name: "VAT", Rate: "20", Inclusive: true, Amount: money.New(200, "GBP")}},
+				Total: money.New(1200, "GBP"),
+			},
+		},
+		{
+			name:   "exclusive rate is added to the price",
+			rules:  rules,
+			supply: Supply{Location: "CA", Service: "ticket"},
+			price:  money.New(1000, "CAD"),
+			want: Result{
+				Net:   money.New(1000, "CAD"),
+				Taxes: []Charge{{Name: "GST", Rate: "5", Amount: money.New(50, "CAD")}},
+				Total: money.New(1050, "CAD"),
+			},
+		},
+		{
+			name:   "region taxes are added to the country's",
+			rules:  rules,
+			supply: Supply{Location: "CA-QC", Service: "ticket"},
+			price:  money.New(1000, "CAD"),
+			want: Result{
+				Net: money.New(1000, "CAD"),
+				Taxes: []Charge{
+					{Name: "GST", Rate: "5", Amount: money.New(50, "CAD")},
+					{Name: "QST", Rate: "9.975", Amount: money.New(100, "CAD")},
+				},
+				Total: money.New(1150, "CAD"),
+			},
+		},
+		{
+			name:   "inclusive and exclusive rates of the same price",
+			rules:  rules,
+			supply: Supply{Location: "MX", Service: "ticket"},
+			price:  money.New(1100, "MXN"),
+			want: Result{
+				Net: money.New(1000, "MXN"),
+				Taxes: []Charge{
+					{Name: "IVA", Rate: "10", Inclusive: true, Amount: money.New(100, "MXN")},
+					{Name: "Service", Rate: "5", Amount: money.New(50, "MXN")},
+				},
+				Total: money.New(1150, "MXN"),
+			},
+		},
+		{
+			name:   "rounding remainder goes to the last inclusive tax",
+			rules:  rules,
+			supply: Supply{Location: "ZA", Service: "ticket"},
+			price:  money.New(1001, "ZAR"),
+			want: Result{
+				Net: money.New(834, "ZAR"),
+				Taxes: []Charge{
+					{Name: "First", Rate: "10", Inclusive: true, Amount: money.New(83, "ZAR")},
+					{Name: "Second", Rate: "10", Inclusive: true, Amount: money.New(84, "ZAR")},
+				},
+				Total: money.New(1001, "ZAR"),
+			},
+		},
+		{
+			name:   "country found by name",
+			rules:  rules,
+			supply: Supply{Location: "germany", Service: "venue", Type: "hall"},
+			price:  money.New(1190, "EUR"),
+			want: Result{
+				Net:   money.New(1000, "EUR"),
+				Taxes: []Charge{{Name: "MwSt", Rate: "19", Inclusive: true, Amount: money.New(190, "EUR")}},
+				Total: money.New(1190, "EUR"),
+			},
+		},
+		{
+			name:   "exempt service",
+			rules:  rules,
+			supply: Supply{Location: "United Kingdom", Service: "practitioner"},
+			price:  money.New(1200, "GBP"),
+			want:   Result{Net: money.New(1200, "GBP"), Total: money.New(1200, "GBP")},
+		},
+		{
+			name:   "exempt type of service",
+			rules:  rules,
+			supply: Supply{Location: "DE", Service: "venue", Type: "Hotel"},
+			price:  money.New(1190, "EUR"),
+			want:   Result{Net: money.New(1190, "EUR"), Total: money.New(1190, "EUR")},
+		},
+		{
+			name:   "unknown location",
+			rules:  rules,
+			supply: Supply{Location: "FR", Service: "ticket"},
+			price:  money.New(1000, "EUR"),
+			want:   Result{Net: money.New(1000, "EUR"), Total: money.New(1000, "EUR")},
+		},
+		{
+			name:   "free supply",
+			rules:  rules,
+			supply: Supply{Location: "GB", Service: "ticket"},
+			price:  money.New(0, "GBP"),
+			want:   Result{Net: money.New(0, "GBP"), Total: money.New(0, "GBP")},
+		},
+		{
+			name:   "no rules",
+			supply: Supply{Location: "GB", Service: "ticket"},
+			price:  money.New(1200, "GBP"),
+			want:   Result{Net: money.New(1200, "GBP"), Total: money.New(1200, "GBP")},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.rules.Apply(tc.supply, tc.price)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Apply() = %+v, want %+v", got, tc.want)
+			}
+			if tax := got.Tax(); tax.Amount != got.Total.Amount-got.Net.Amount {
+				t.Errorf("Tax() = %d, want %d", tax.Amount, got.Total.Amount-got.Net.Amount)
+			}
+
+			// The inclusive taxes always add up to what was taken out of the price
+			var included int64
+			for _, charge := range got.Taxes {
+				if charge.Inclusive {
+					included += charge.Amount.Amount
+				}
+			}
+			if included != tc.price.Amount-got.Net.Amount {
+				t.Errorf("inclusive taxes = %d, want %d", included, tc.price.Amount-got.Net.Amount)
+			}
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	testCases := []struct {
+		name    string
+		rules   string
+		wantErr bool
+	}{
+		{name: "valid", rules: testRules},
+		{name: "json", rules: `{"jurisdictions": [{"country": "US", "region": "NY", "taxes": [{"name": "Sales", "rate": "4"}]}]}`},
+		{name: "no jurisdictions", rules: `jurisdictions: []`},
+		{name: "country is not a code", rules: "jurisdictions:\n  - country: France\n", wantErr: true},
+		{name: "country listed twice", rules: "jurisdictions:\n  - country: FR\n  - country: fr\n", wantErr: true},
+		{name: "tax without a name", rules: "jurisdictions:\n  - country: FR\n    taxes:\n      - rate: \"20\"\n", wantErr: true},
+		{name: "rate is not a number", rules: "jurisdictions:\n  - country: FR\n    taxes:\n      - name: TVA\n        rate: twenty\n", wantErr: true},
+		{name: "negative rate", rules: "jurisdictions:\n  - country: FR\n    taxes:\n      - name: TVA\n        rate: \"-1\"\n", wantErr: true},
+		{name: "rate above 100", rules: "jurisdictions:\n  - country: FR\n    taxes:\n      - name: TVA\n        rate: \"101\"\n", wantErr: true},
+		{name: "malformed", rules: "jurisdictions: [", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseRules([]byte(tc.rules))
+			if tc.wantErr {
+				if !errors.Is(err, ErrInvalidRules) {
+					t.Fatalf("ParseRules() error = %v, want %v", err, ErrInvalidRules)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRules() error: %v", err)
+			}
+		})
+	}
+}
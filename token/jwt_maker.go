@@ -0,0 +1,92 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const minSecretKeySize = 32
+
+// JWTMaker is a JSON Web Token maker.
+type JWTMaker struct {
+	secretKey string
+}
+
+// NewJWTMaker creates a new JWTMaker.
+func NewJWTMaker(secretKey string) (Maker, error) {
+	if len(secretKey) < minSecretKeySize {
+		return nil, fmt.Errorf("invalid key size: must be at least %d characters", minSecretKeySize)
+	}
+	return &JWTMaker{secretKey}, nil
+}
+
+// jwtClaims adapts Payload to the jwt.Claims interface.
+type jwtClaims struct {
+	*Payload
+}
+
+func (claims jwtClaims) GetExpirationTime() (*jwt.NumericDate, error) {
+	return jwt.NewNumericDate(claims.ExpiredAt), nil
+}
+
+func (claims jwtClaims) GetIssuedAt() (*jwt.NumericDate, error) {
+	return jwt.NewNumericDate(claims.IssuedAt), nil
+}
+
+func (claims jwtClaims) GetNotBefore() (*jwt.NumericDate, error) {
+	return nil, nil
+}
+
+func (claims jwtClaims) GetIssuer() (string, error) {
+	return "", nil
+}
+
+func (claims jwtClaims) GetSubject() (string, error) {
+	return claims.UserID.String(), nil
+}
+
+func (claims jwtClaims) GetAudience() (jwt.ClaimStrings, error) {
+	return nil, nil
+}
+
+// CreateToken creates a new token for a specific user, purpose and duration.
+func (maker *JWTMaker) CreateToken(userID uuid.UUID, purpose Purpose, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(userID, purpose, duration)
+	if err != nil {
+		return "", payload, err
+	}
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtClaims{payload})
+	token, err := jwtToken.SignedString([]byte(maker.secretKey))
+	return token, payload, err
+}
+
+// VerifyToken checks if the token is valid and was created for purpose.
+func (maker *JWTMaker) VerifyToken(token string, purpose Purpose) (*Payload, error) {
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		_, ok := token.Method.(*jwt.SigningMethodHMAC)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+		return []byte(maker.secretKey), nil
+	}
+
+	claims := jwtClaims{&Payload{}}
+	_, err := jwt.ParseWithClaims(token, &claims, keyFunc)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	if err := claims.checkPurpose(purpose); err != nil {
+		return nil, err
+	}
+
+	return claims.Payload, nil
+}
@@ -0,0 +1,36 @@
+package token
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Maker is an interface for managing tokens.
+type Maker interface {
+	// CreateToken creates a new token for a specific user, purpose and duration.
+	CreateToken(userID uuid.UUID, purpose Purpose, duration time.Duration) (string, *Payload, error)
+
+	// VerifyToken checks if the token is valid and was created for purpose.
+	VerifyToken(token string, purpose Purpose) (*Payload, error)
+}
+
+// Supported token types, selected with the TOKEN_TYPE config value.
+const (
+	TypePaseto = "paseto"
+	TypeJWT    = "jwt"
+)
+
+// NewMaker creates a Maker for the given token type.
+// An empty type defaults to PASETO.
+func NewMaker(tokenType string, symmetricKey string) (Maker, error) {
+	switch tokenType {
+	case "", TypePaseto:
+		return NewPasetoMaker(symmetricKey)
+	case TypeJWT:
+		return NewJWTMaker(symmetricKey)
+	default:
+		return nil, fmt.Errorf("unsupported token type: %s", tokenType)
+	}
+}
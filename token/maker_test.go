@@ -0,0 +1,132 @@
+package token
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const testKey = "12345678901234567890123456789012"
+
+func TestNewMaker(t *testing.T) {
+	testCases := []struct {
+		name      string
+		tokenType string
+		key       string
+		wantErr   bool
+	}{
+		{name: "default is paseto", tokenType: "", key: testKey},
+		{name: "paseto", tokenType: TypePaseto, key: testKey},
+		{name: "jwt", tokenType: TypeJWT, key: testKey},
+		{name: "jwt with longer key", tokenType: TypeJWT, key: testKey + "more"},
+		{name: "paseto key too short", tokenType: TypePaseto, key: testKey[:31], wantErr: true},
+		{name: "paseto key too long", tokenType: TypePaseto, key: testKey + "x", wantErr: true},
+		{name: "jwt key too short", tokenType: TypeJWT, key: testKey[:31], wantErr: true},
+		{name: "unknown type", tokenType: "macaroon", key: testKey, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			maker, err := NewMaker(tc.tokenType, tc.key)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NewMaker(%q) error = %v, wantErr %v", tc.tokenType, err, tc.wantErr)
+			}
+			if err == nil && maker == nil {
+				t.Fatal("NewMaker returned a nil maker")
+			}
+		})
+	}
+}
+
+func TestMakerVerifyToken(t *testing.T) {
+	for _, tokenType := range []string{TypePaseto, TypeJWT} {
+		t.Run(tokenType, func(t *testing.T) {
+			maker, err := NewMaker(tokenType, testKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			otherMaker, err := NewMaker(tokenType, "abcdefghijabcdefghijabcdefghijab")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testCases := []struct {
+				name     string
+				maker    Maker
+				purpose  Purpose
+				duration time.Duration
+				verifyAs Purpose
+				wantErr  error
+			}{
+				{name: "access token", maker: maker, purpose: PurposeAccess, duration: time.Minute, verifyAs: PurposeAccess},
+				{name: "refresh token", maker: maker, purpose: PurposeRefresh, duration: time.Minute, verifyAs: PurposeRefresh},
+				{name: "refresh token used as access token", maker: maker, purpose: PurposeRefresh, duration: time.Minute, verifyAs: PurposeAccess, wantErr: ErrInvalidToken},
+				{name: "access token used as refresh token", maker: maker, purpose: PurposeAccess, duration: time.Minute, verifyAs: PurposeRefresh, wantErr: ErrInvalidToken},
+				{name: "expired", maker: maker, purpose: PurposeAccess, duration: -time.Minute, verifyAs: PurposeAccess, wantErr: ErrExpiredToken},
+				{name: "signed with another key", maker: otherMaker, purpose: PurposeAccess, duration: time.Minute, verifyAs: PurposeAccess, wantErr: ErrInvalidToken},
+			}
+
+			for _, tc := range testCases {
+				t.Run(tc.name, func(t *testing.T) {
+					userID := uuid.New()
+					token, created, err := tc.maker.CreateToken(userID, tc.purpose, tc.duration)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if created.UserID != userID || created.Purpose != tc.purpose {
+						t.Fatalf("created payload = %+v, want user %s and purpose %q", created, userID, tc.purpose)
+					}
+
+					payload, err := maker.VerifyToken(token, tc.verifyAs)
+					if tc.wantErr != nil {
+						if !errors.Is(err, tc.wantErr) {
+							t.Fatalf("VerifyToken() error = %v, want %v", err, tc.wantErr)
+						}
+						if payload != nil {
+							t.Fatalf("VerifyToken() returned payload %+v with an error", payload)
+						}
+						return
+					}
+					if err != nil {
+						t.Fatalf("VerifyToken() error: %v", err)
+					}
+					if payload.ID != created.ID || payload.UserID != userID || payload.Purpose != tc.purpose {
+						t.Errorf("VerifyToken() = %+v, want %+v", payload, created)
+					}
+					if !payload.ExpiredAt.Equal(created.ExpiredAt) {
+						t.Errorf("ExpiredAt = %v, want %v", payload.ExpiredAt, created.ExpiredAt)
+					}
+				})
+			}
+
+			t.Run("malformed", func(t *testing.T) {
+				if _, err := maker.VerifyToken("not-a-token", PurposeAccess); !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("VerifyToken() error = %v, want %v", err, ErrInvalidToken)
+				}
+			})
+		})
+	}
+}
+
+func TestJWTMakerRejectsUnsignedToken(t *testing.T) {
+	maker, err := NewJWTMaker(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := NewPayload(uuid.New(), PurposeAccess, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwtClaims{payload}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := maker.VerifyToken(token, PurposeAccess); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("VerifyToken() error = %v, want %v", err, ErrInvalidToken)
+	}
+}
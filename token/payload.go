@@ -0,0 +1,66 @@
+package token
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Different types of error returned by the VerifyToken function.
+var (
+	ErrInvalidToken = errors.New("token is invalid")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// Purpose is what a token may be used for.
+type Purpose string
+
+// Token purposes. Access tokens authenticate requests; refresh tokens only
+// renew access tokens and end sessions.
+const (
+	PurposeAccess  Purpose = "access"
+	PurposeRefresh Purpose = "refresh"
+)
+
+// Payload contains the payload data of the token.
+type Payload struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Purpose   Purpose   `json:"purpose"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+// NewPayload creates a new token payload with a specific user, purpose and duration.
+func NewPayload(userID uuid.UUID, purpose Purpose, duration time.Duration) (*Payload, error) {
+	tokenID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &Payload{
+		ID:        tokenID,
+		UserID:    userID,
+		Purpose:   purpose,
+		IssuedAt:  time.Now(),
+		ExpiredAt: time.Now().Add(duration),
+	}
+	return payload, nil
+}
+
+// Valid checks if the token payload is valid or not.
+func (payload *Payload) Valid() error {
+	if time.Now().After(payload.ExpiredAt) {
+		return ErrExpiredToken
+	}
+	return nil
+}
+
+// checkPurpose returns ErrInvalidToken unless the payload is for purpose.
+func (payload *Payload) checkPurpose(purpose Purpose) error {
+	if payload.Purpose != purpose {
+		return ErrInvalidToken
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package util
+
+import (
+	"errors"
+	"time"
+)
+
+// BookingStatus is the state stored in bookedVenues.status and
+// bookedPractitioners.status.
+type BookingStatus string
+
+// Booking states. Cancelled bookings are kept but no longer hold their slot.
+const (
+	BookingConfirmed BookingStatus = "confirmed"
+	BookingCancelled BookingStatus = "cancelled"
+)
+
+// RefundStatus is the state of the refund of a cancellation, stored in
+// cancellations.refund_status.
+type RefundStatus string
+
+// Refund states. A refund is pending until the payment provider confirms it.
+const (
+	RefundNone    RefundStatus = "none"
+	RefundPending RefundStatus = "pending"
+	RefundIssued  RefundStatus = "issued"
+	RefundFailed  RefundStatus = "failed"
+)
+
+// CancellationPolicy decides how much of a payment is refunded when a
+// booking or ticket is cancelled. Cancelling at least FullRefundHours before
+// the start refunds everything, less than NoRefundHours before refunds
+// nothing, and in between refunds PartialRefundPercent.
+type CancellationPolicy struct {
+	FullRefundHours      int32 `json:"full_refund_hours"`
+	NoRefundHours        int32 `json:"no_refund_hours"`
+	PartialRefundPercent int32 `json:"partial_refund_percent"`
+}
+
+// Validate checks that the policy's windows are ordered and the percentage is in range.
+func (policy CancellationPolicy) Validate() error {
+	if policy.NoRefundHours < 0 || policy.FullRefundHours < policy.NoRefundHours {
+		return errors.New("no_refund_hours must be between 0 and full_refund_hours")
+	}
+	if policy.PartialRefundPercent < 0 || policy.PartialRefundPercent > 100 {
+		return errors.New("partial_refund_percent must be between 0 and 100")
+	}
+	return nil
+}
+
+// Refund returns how much of amount is refunded when cancelling with the
+// given notice before the start. Notice is negative once the start has passed.
+func (policy CancellationPolicy) Refund(amount int64, notice time.Duration) int64 {
+	switch {
+	case notice < time.Duration(policy.NoRefundHours)*time.Hour, notice < 0:
+		return 0
+	case notice >= time.Duration(policy.FullRefundHours)*time.Hour:
+		return amount
+	default:
+		return amount * int64(policy.PartialRefundPercent) / 100
+	}
+}
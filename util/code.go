@@ -0,0 +1,19 @@
+package util
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+)
+
+// codeEncoding avoids padding so codes are plain alphanumeric strings.
+var codeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewCode returns a random, upper-case alphanumeric code of n bytes of
+// entropy, suitable for ticket codes that customers type in.
+func NewCode(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return codeEncoding.EncodeToString(b), nil
+}
@@ -1,15 +1,33 @@
 package util
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
 // Config stores all configuration of the application.
 // The values are read by viper from a config file or environment variable.
 type Config struct {
-	DBDriver      string `mapstructure:"DB_DRIVER"`
-	DBSource      string `mapstructure:"DB_SOURCE"`
-	ServerAddress string `mapstructure:"SERVER_ADDRESS"`
+	DBDriver                string        `mapstructure:"DB_DRIVER"`
+	DBSource                string        `mapstructure:"DB_SOURCE"`
+	ServerAddress           string        `mapstructure:"SERVER_ADDRESS"`
+	TokenType               string        `mapstructure:"TOKEN_TYPE"` // "paseto" (default) or "jwt"
+	TokenSymmetricKey       string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	AccessTokenDuration     time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RefreshTokenDuration    time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	AppointmentDuration     time.Duration `mapstructure:"APPOINTMENT_DURATION"`
+	AvailabilityGranularity time.Duration `mapstructure:"AVAILABILITY_GRANULARITY"` // step between free slot starts
+	DefaultPageSize         int32         `mapstructure:"DEFAULT_PAGE_SIZE"`
+	MaxPageSize             int32         `mapstructure:"MAX_PAGE_SIZE"`
+	MigrateOnBoot           bool          `mapstructure:"MIGRATE_ON_BOOT"`   // apply pending migrations before serving
+	GeocoderFixtures        string        `mapstructure:"GEOCODER_FIXTURES"` // JSON file of address coordinates; empty disables geocoding
+	Currency                string        `mapstructure:"CURRENCY"`          // ISO 4217 code prices are charged in
+	PaymentProvider         string        `mapstructure:"PAYMENT_PROVIDER"`  // only "fake" for now
+	PaymentWebhookSecret    string        `mapstructure:"PAYMENT_WEBHOOK_SECRET"`
+	PendingPurchaseTTL      time.Duration `mapstructure:"PENDING_PURCHASE_TTL"` // how long an unpaid checkout holds its places
+	WaitlistHold            time.Duration `mapstructure:"WAITLIST_HOLD"`        // how long a freed place is held for the promoted profile
+	TaxRules                string        `mapstructure:"TAX_RULES"`            // YAML or JSON file of tax rules; empty charges no tax
 }
 
 // LoadConfig reads configuration from file or environment variables.
@@ -20,6 +38,15 @@ func LoadConfig(path string) (config Config, err error) {
 
 	viper.AutomaticEnv() // Read from environment variables
 
+	viper.SetDefault("APPOINTMENT_DURATION", time.Hour)
+	viper.SetDefault("AVAILABILITY_GRANULARITY", 30*time.Minute)
+	viper.SetDefault("DEFAULT_PAGE_SIZE", 20)
+	viper.SetDefault("MAX_PAGE_SIZE", 100)
+	viper.SetDefault("CURRENCY", "USD")
+	viper.SetDefault("PAYMENT_PROVIDER", "fake")
+	viper.SetDefault("PENDING_PURCHASE_TTL", 30*time.Minute)
+	viper.SetDefault("WAITLIST_HOLD", 24*time.Hour)
+
 	err = viper.ReadInConfig()
 	if err != nil {
 		return
@@ -0,0 +1,33 @@
+package util
+
+// EventStatus is the lifecycle state stored in events.status.
+type EventStatus string
+
+// Event lifecycle states.
+const (
+	EventDraft     EventStatus = "draft"
+	EventPublished EventStatus = "published"
+	EventCancelled EventStatus = "cancelled"
+	EventCompleted EventStatus = "completed"
+)
+
+// eventTransitions lists the states each state may move to.
+var eventTransitions = map[EventStatus][]EventStatus{
+	EventDraft:     {EventPublished, EventCancelled},
+	EventPublished: {EventCancelled, EventCompleted},
+}
+
+// CanTransition reports whether an event may move from one status to another.
+func (from EventStatus) CanTransition(to EventStatus) bool {
+	for _, next := range eventTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEditable reports whether an event in this status may still be changed.
+func (status EventStatus) IsEditable() bool {
+	return status == EventDraft || status == EventPublished
+}
@@ -0,0 +1,39 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// InvoiceLineKind is the kind of an invoice line, stored in invoice_lines.kind.
+type InvoiceLineKind string
+
+// Invoice line kinds. Item lines are what was sold; tax lines are the taxes
+// charged on them.
+const (
+	InvoiceLineItem InvoiceLineKind = "item"
+	InvoiceLineTax  InvoiceLineKind = "tax"
+)
+
+// invoicePrefixLength caps the business name part of an invoice number.
+const invoicePrefixLength = 8
+
+// InvoiceNumber formats the sequence-th invoice of a seller, prefixed with
+// the letters and digits of its business name, such as "ACMESTUD-000042".
+// Sellers without a business name use "INV".
+func InvoiceNumber(businessName string, sequence int64) string {
+	var prefix strings.Builder
+	for _, r := range strings.ToUpper(businessName) {
+		if prefix.Len() == invoicePrefixLength {
+			break
+		}
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			prefix.WriteRune(r)
+		}
+	}
+	if prefix.Len() == 0 {
+		prefix.WriteString("INV")
+	}
+	return fmt.Sprintf("%s-%06d", prefix.String(), sequence)
+}
@@ -0,0 +1,17 @@
+package util
+
+// MemberRole is a user's role within a shared profile, stored in
+// profiles_users.member_role.
+type MemberRole string
+
+// Member roles, from most to least privileged.
+const (
+	MemberOwner   MemberRole = "owner"
+	MemberManager MemberRole = "manager"
+	MemberStaff   MemberRole = "staff"
+)
+
+// CanManage reports whether the member may edit the profile and its members.
+func (role MemberRole) CanManage() bool {
+	return role == MemberOwner || role == MemberManager
+}
@@ -0,0 +1,18 @@
+package util
+
+// PurchaseStatus is the payment state stored in purchases.status.
+type PurchaseStatus string
+
+// Purchase payment states. Purchases start pending and are settled once by
+// the payment provider's webhook. Pending and paid purchases may be cancelled.
+const (
+	PurchasePending   PurchaseStatus = "pending"
+	PurchasePaid      PurchaseStatus = "paid"
+	PurchaseFailed    PurchaseStatus = "failed"
+	PurchaseCancelled PurchaseStatus = "cancelled"
+)
+
+// IsActive reports whether the purchase still holds what it bought.
+func (status PurchaseStatus) IsActive() bool {
+	return status == PurchasePending || status == PurchasePaid
+}
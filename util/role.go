@@ -0,0 +1,74 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Role is a capability granted to a profile through the profiles.roles column.
+type Role string
+
+// Roles understood by the authorization layer.
+const (
+	RoleAttendee     Role = "attendee"
+	RoleVenueOwner   Role = "venue_owner"
+	RolePractitioner Role = "practitioner"
+	RoleOrganiser    Role = "organiser"
+	RoleAdmin        Role = "admin"
+)
+
+// AllRoles lists every supported role.
+var AllRoles = []Role{RoleAttendee, RoleVenueOwner, RolePractitioner, RoleOrganiser, RoleAdmin}
+
+// IsSupportedRole returns true if the role is known.
+func IsSupportedRole(role Role) bool {
+	switch role {
+	case RoleAttendee, RoleVenueOwner, RolePractitioner, RoleOrganiser, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// RoleSet is the set of roles held by a single profile.
+type RoleSet map[Role]struct{}
+
+// ParseRoles parses the comma separated profiles.roles column, e.g. "organiser,venue_owner".
+func ParseRoles(s string) (RoleSet, error) {
+	roles := RoleSet{}
+	for _, field := range strings.Split(s, ",") {
+		role := Role(strings.ToLower(strings.TrimSpace(field)))
+		if role == "" {
+			continue
+		}
+		if !IsSupportedRole(role) {
+			return nil, fmt.Errorf("unsupported role: %s", role)
+		}
+		roles[role] = struct{}{}
+	}
+	return roles, nil
+}
+
+// Has reports whether the set contains any of the given roles.
+// Admins implicitly hold every role.
+func (roles RoleSet) Has(want ...Role) bool {
+	if _, ok := roles[RoleAdmin]; ok {
+		return true
+	}
+	for _, role := range want {
+		if _, ok := roles[role]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// String formats the set the way it is stored in profiles.roles.
+func (roles RoleSet) String() string {
+	names := make([]string, 0, len(roles))
+	for _, role := range AllRoles {
+		if _, ok := roles[role]; ok {
+			names = append(names, string(role))
+		}
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,113 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Weekdays is a set of days of the week, as stored in the free-text
+// venues.rental_days and practitioners.working_days columns.
+type Weekdays uint8
+
+// AllWeekdays contains every day of the week.
+const AllWeekdays Weekdays = 1<<7 - 1
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// ParseWeekdays parses a list of days such as "mon,wed,fri", "Monday-Friday"
+// or "sat, sun". Ranges wrap around the week, so "fri-mon" is Friday to Monday.
+// An empty string or "daily" means every day.
+func ParseWeekdays(s string) (Weekdays, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" || s == "daily" || s == "everyday" {
+		return AllWeekdays, nil
+	}
+
+	var days Weekdays
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == ';' }) {
+		from, to, isRange := strings.Cut(field, "-")
+		start, ok := weekdayNames[from]
+		if !ok {
+			return 0, fmt.Errorf("invalid weekday: %q", from)
+		}
+		if !isRange {
+			days |= 1 << start
+			continue
+		}
+
+		end, ok := weekdayNames[to]
+		if !ok {
+			return 0, fmt.Errorf("invalid weekday: %q", to)
+		}
+		for d := start; ; d = (d + 1) % 7 {
+			days |= 1 << d
+			if d == end {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// Contains reports whether the set includes the given day.
+func (days Weekdays) Contains(day time.Weekday) bool {
+	return days&(1<<day) != 0
+}
+
+// clock returns the time of day of t as an offset from midnight.
+func clock(t time.Time) time.Duration {
+	h, m, s := t.Clock()
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+}
+
+// OpeningHours is a daily opening window. Only the time of day of OpensAt
+// and ClosesAt is used, matching how opens_at/closes_at are stored.
+type OpeningHours struct {
+	OpensAt  time.Time
+	ClosesAt time.Time
+	Valid    bool // false means open around the clock
+}
+
+// Validate checks that the window opens before it closes on the same day.
+func (hours OpeningHours) Validate() error {
+	if hours.Valid && clock(hours.OpensAt) >= clock(hours.ClosesAt) {
+		return fmt.Errorf("opens_at must be before closes_at")
+	}
+	return nil
+}
+
+// Window returns the opening window on the calendar day of t.
+func (hours OpeningHours) Window(t time.Time) (time.Time, time.Time) {
+	midnight := StartOfDay(t)
+	if !hours.Valid {
+		return midnight, midnight.AddDate(0, 0, 1)
+	}
+	return midnight.Add(clock(hours.OpensAt)), midnight.Add(clock(hours.ClosesAt))
+}
+
+// Contains reports whether t falls inside the opening window of its day.
+func (hours OpeningHours) Contains(t time.Time) bool {
+	opens, closes := hours.Window(t)
+	return !t.Before(opens) && t.Before(closes)
+}
+
+// Covers reports whether [start, end) lies inside the opening window of
+// the day start falls on.
+func (hours OpeningHours) Covers(start, end time.Time) bool {
+	opens, closes := hours.Window(start)
+	return !start.Before(opens) && !end.After(closes)
+}
+
+// StartOfDay returns midnight of the calendar day of t.
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
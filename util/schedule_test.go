@@ -0,0 +1,89 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+// weekdays returns the set of the given days.
+func weekdays(days ...time.Weekday) Weekdays {
+	var set Weekdays
+	for _, day := range days {
+		set |= 1 << day
+	}
+	return set
+}
+
+func TestParseWeekdays(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    Weekdays
+		wantErr bool
+	}{
+		{name: "empty means every day", input: "", want: AllWeekdays},
+		{name: "daily", input: " Daily ", want: AllWeekdays},
+		{name: "list", input: "mon,wed,fri", want: weekdays(time.Monday, time.Wednesday, time.Friday)},
+		{name: "full names and spaces", input: "Saturday, Sunday", want: weekdays(time.Saturday, time.Sunday)},
+		{name: "semicolons", input: "tue;thurs", want: weekdays(time.Tuesday, time.Thursday)},
+		{
+			name:  "range",
+			input: "Monday-Friday",
+			want:  weekdays(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday),
+		},
+		{name: "range wrapping the week", input: "fri-mon", want: weekdays(time.Friday, time.Saturday, time.Sunday, time.Monday)},
+		{name: "single day range", input: "wed-wed", want: weekdays(time.Wednesday)},
+		{name: "range and list", input: "mon-tue, sat", want: weekdays(time.Monday, time.Tuesday, time.Saturday)},
+		{name: "unknown day", input: "mon,funday", wantErr: true},
+		{name: "unknown range end", input: "mon-someday", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseWeekdays(tc.input)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("ParseWeekdays(%q) = %07b, want error", tc.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseWeekdays(%q) error: %v", tc.input, err)
+			}
+			if got != tc.want {
+				t.Errorf("ParseWeekdays(%q) = %07b, want %07b", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOpeningHoursValidate(t *testing.T) {
+	clockTime := func(hour, minute int) time.Time {
+		return time.Date(2024, time.January, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		name    string
+		hours   OpeningHours
+		wantErr bool
+	}{
+		{name: "open around the clock", hours: OpeningHours{}},
+		{name: "opens before closing", hours: OpeningHours{OpensAt: clockTime(9, 0), ClosesAt: clockTime(17, 30), Valid: true}},
+		{name: "only the time of day counts", hours: OpeningHours{
+			OpensAt:  clockTime(9, 0),
+			ClosesAt: clockTime(17, 0).AddDate(-1, 0, 0),
+			Valid:    true,
+		}},
+		{name: "opens and closes at once", hours: OpeningHours{OpensAt: clockTime(9, 0), ClosesAt: clockTime(9, 0), Valid: true}, wantErr: true},
+		{name: "closes after midnight", hours: OpeningHours{OpensAt: clockTime(22, 0), ClosesAt: clockTime(2, 0), Valid: true}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.hours.Validate()
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}